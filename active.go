@@ -0,0 +1,765 @@
+package main
+
+import ( "errors"
+		 "fmt"
+		 "math"
+		 "math/cmplx"
+		 "slices" )
+
+
+type Stage struct {
+
+	Topology	  Topology
+	Response	  Response
+	Frequency	  float64
+	Q			  float64
+	Gain		  float64
+	Numerator	  []float64
+	Denominator	  []float64
+	Resistors	  []float64
+	Capacitors	  []float64
+	Amplifiers	  int
+	GainBandwidth float64
+	Adequate	  bool
+
+}
+
+type ActiveFilter struct {
+
+	Stages		  []Stage
+	GainBandwidth float64
+
+}
+
+func transformRoots(roots []complex128, response Response, cutoff float64, center float64, bandwidth float64) []complex128 {
+
+	transformed := []complex128{}
+
+	for _, root := range roots {
+
+		switch response {
+
+			case HPF:
+
+				transformed = append(transformed, complex(cutoff, 0) / root)
+
+			case BPF, BSF:
+
+				shift := root*complex(bandwidth, 0)
+
+				if (response == BSF) {
+
+					shift = complex(bandwidth, 0) / root
+
+				}
+
+				discriminant := cmplx.Sqrt(shift*shift - complex(4.0*center*center, 0))
+				transformed = append(transformed, (shift + discriminant) / 2.0, (shift - discriminant) / 2.0)
+
+			default:
+
+				transformed = append(transformed, root*complex(cutoff, 0))
+
+		}
+
+	}
+
+	return transformed
+
+}
+
+func sectionFactors(roots []complex128) ([][]float64, []float64, int) {
+
+	pairs := [][]float64{}
+	singles := []float64{}
+	origin := 0
+
+	for _, root := range roots {
+
+		tolerance := 1e-8*math.Max(cmplx.Abs(root), 1.0)
+
+		if (imag(root) > tolerance) {
+
+			pairs = append(pairs, []float64{ 1.0, -2.0*real(root), real(root)*real(root) + imag(root)*imag(root) })
+
+		} else if (cmplx.Abs(root) < tolerance) {
+
+			origin++
+
+		} else if (math.Abs(imag(root)) <= tolerance) {
+
+			singles = append(singles, real(root))
+
+		}
+
+	}
+
+	slices.Sort(singles)
+
+	for (len(singles) > 1) {
+
+		pairs = append(pairs, []float64{ 1.0, -(singles[0] + singles[1]), singles[0]*singles[1] })
+		singles = singles[2:]
+
+	}
+
+	return pairs, singles, origin
+
+}
+
+func analogueSections(config Specs) []Stage {
+
+	cutoffFrequency, centerFrequency, bandwidth := analogueFrequencies(config)
+	cutoff := 2.0*math.Pi*cutoffFrequency
+	center := 2.0*math.Pi*centerFrequency
+	response := LPF
+
+	switch config.Response {
+
+		case HPF, BPF, BSF:
+
+			response = config.Response
+
+		case BRF:
+
+			response = BSF
+
+	}
+
+	prototype := zeroPoleGain(analoguePrototype(config))
+	poles := transformRoots(prototype.Poles, response, cutoff, center, 2.0*math.Pi*bandwidth)
+	zeros := transformRoots(prototype.Zeros, response, cutoff, center, 2.0*math.Pi*bandwidth)
+	excess := len(prototype.Poles) - len(prototype.Zeros)
+
+	if (response == BSF) {
+
+		for index := 0; index < excess; index++ {
+
+			zeros = append(zeros, complex(0, center), complex(0, -center))
+
+		}
+
+	}
+
+	denominators, singles, _ := sectionFactors(poles)
+	numerators, _, origin := sectionFactors(zeros)
+
+	if ((response == HPF) || (response == BPF)) {
+
+		origin += excess
+
+	}
+
+	stages := []Stage{}
+
+	for _, denominator := range denominators {
+
+		omega := math.Sqrt(denominator[2])
+		stages = append(stages, Stage{ Denominator: denominator, Frequency: omega / (2.0*math.Pi), Q: omega / denominator[1] })
+
+	}
+
+	slices.SortStableFunc(stages, func(p Stage, q Stage) int {
+
+		if (p.Q > q.Q) {
+
+			return -1
+
+		} else if (p.Q < q.Q) {
+
+			return 1
+
+		}
+
+		return 0
+
+	})
+
+	for index := range stages {
+
+		if (len(numerators) == 0) {
+
+			break
+
+		}
+
+		nearest := 0
+		omega := 2.0*math.Pi*stages[index].Frequency
+
+		for candidate := range numerators {
+
+			if (math.Abs(math.Sqrt(numerators[candidate][2]) - omega) < math.Abs(math.Sqrt(numerators[nearest][2]) - omega)) {
+
+				nearest = candidate
+
+			}
+
+		}
+
+		stages[index].Numerator = numerators[nearest]
+		numerators = slices.Delete(numerators, nearest, nearest + 1)
+
+	}
+
+	for _, single := range singles {
+
+		stage := Stage{ Denominator: []float64{ 1.0, -single }, Frequency: -single / (2.0*math.Pi), Numerator: []float64{ 1.0 } }
+
+		if (origin > 0) {
+
+			stage.Numerator = []float64{ 1.0, 0.0 }
+			origin--
+
+		}
+
+		stages = append([]Stage{ stage }, stages...)
+
+	}
+
+	remaining := 0
+
+	for _, stage := range stages {
+
+		if (stage.Numerator == nil) {
+
+			remaining++
+
+		}
+
+	}
+
+	for index := range stages {
+
+		if (stages[index].Numerator != nil) {
+
+			continue
+
+		}
+
+		take := origin / remaining
+
+		if (origin%remaining != 0) {
+
+			take++
+
+		}
+
+		take = min(take, 2)
+		stages[index].Numerator = append([]float64{ 1.0 }, make([]float64, take)...)
+		origin -= take
+		remaining--
+
+	}
+
+	reference := complex(0, 0)
+
+	if (response == BPF) {
+
+		reference = complex(0, center)
+
+	}
+
+	for index := range stages {
+
+		stage := &stages[index]
+
+		switch {
+
+			case (len(stage.Numerator) == 3) && (stage.Numerator[2] != 0.0):
+
+				stage.Response = Notch
+
+			case (len(stage.Numerator) == len(stage.Denominator)):
+
+				stage.Response = HPF
+
+			case (len(stage.Numerator) == 2):
+
+				stage.Response = BPF
+
+			default:
+
+				stage.Response = LPF
+
+		}
+
+		if (response != HPF) {
+
+			scale := 1.0 / cmplx.Abs(hornerComplex(stage.Numerator, reference) / hornerComplex(stage.Denominator, reference))
+
+			for coefficient := range stage.Numerator {
+
+				stage.Numerator[coefficient] *= scale
+
+			}
+
+		}
+
+	}
+
+	slices.SortStableFunc(stages, func(p Stage, q Stage) int {
+
+		if (p.Q < q.Q) {
+
+			return -1
+
+		} else if (p.Q > q.Q) {
+
+			return 1
+
+		}
+
+		return 0
+
+	})
+
+	return stages
+
+}
+
+func designStage(stage Stage, topology Topology, capacitance float64) Stage {
+
+	const reference = 10e3
+
+	omega := 2.0*math.Pi*stage.Frequency
+	q := stage.Q
+	c := capacitance
+
+	if (len(stage.Denominator) == 2) {
+
+		stage.Topology = FirstOrder
+		stage.Resistors = []float64{ 1.0 / (omega*c) }
+		stage.Capacitors = []float64{ c }
+		stage.Amplifiers = 1
+		return stage
+
+	}
+
+	supported := false
+
+	switch topology {
+
+		case SallenKeyUnity, SallenKeyEqual:
+
+			supported = ((stage.Response == LPF) || (stage.Response == HPF))
+
+		case MultipleFeedback:
+
+			supported = ((stage.Response == LPF) || (stage.Response == HPF) || (stage.Response == BPF))
+
+		case TowThomas:
+
+			supported = ((stage.Response == LPF) || (stage.Response == BPF))
+
+	}
+
+	if (!supported) {
+
+		topology = StateVariable
+
+	}
+
+	if ((topology == SallenKeyEqual) && (q < 0.5)) {
+
+		topology = SallenKeyUnity
+
+	}
+
+	stage.Topology = topology
+	stage.Amplifiers = 1
+	target := stage.Numerator[len(stage.Numerator) - 1] / stage.Denominator[2]
+
+	switch stage.Response {
+
+		case HPF:
+
+			target = stage.Numerator[0]
+
+		case BPF:
+
+			target = stage.Numerator[0]*q / omega
+
+	}
+
+	switch topology {
+
+		case SallenKeyUnity:
+
+			if (stage.Response == HPF) {
+
+				stage.Resistors = []float64{ 1.0 / (2.0*q*omega*c), 2.0*q / (omega*c) }
+				stage.Capacitors = []float64{ c, c }
+
+			} else {
+
+				stage.Resistors = []float64{ 2.0*q / (omega*c), 2.0*q / (omega*c) }
+				stage.Capacitors = []float64{ c, c / (4.0*q*q) }
+
+			}
+
+		case SallenKeyEqual:
+
+			gain := 3.0 - 1.0 / q
+			r := 1.0 / (omega*c)
+			stage.Resistors = []float64{ r, r, reference, (gain - 1.0)*reference }
+			stage.Capacitors = []float64{ c, c }
+
+			// the amplifier gain is tied to Q, so split the input element into a divider that scales it down to the target
+			if ((target > 0.0) && (target < gain)) {
+
+				attenuation := target / gain
+
+				if (stage.Response == HPF) {
+
+					stage.Capacitors = []float64{ attenuation*c, c, (1.0 - attenuation)*c }
+
+				} else {
+
+					stage.Resistors = []float64{ r / attenuation, r, reference, (gain - 1.0)*reference, r / (1.0 - attenuation) }
+
+				}
+
+			}
+
+		case MultipleFeedback:
+
+			switch stage.Response {
+
+				case HPF:
+
+					stage.Resistors = []float64{ target / (omega*q*c*(2.0*target + 1.0)), q*(2.0*target + 1.0) / (omega*c) }
+					stage.Capacitors = []float64{ c, c / target, c }
+
+				case BPF:
+
+					gain := math.Min(target, q*q)
+					feedback := 2.0*q / (omega*c)
+					input := feedback / (2.0*gain)
+					parallel := 1.0 / (omega*omega*c*c*feedback)
+					stage.Resistors = []float64{ input, 1.0 / (1.0 / parallel - 1.0 / input), feedback }
+					stage.Capacitors = []float64{ c, c }
+
+				default:
+
+					integrating := c / (4.0*q*q*(1.0 + target))
+					feedback := 1.0 / (2.0*omega*q*integrating)
+					stage.Resistors = []float64{ feedback / target, feedback, 1.0 / (omega*omega*integrating*c*feedback) }
+					stage.Capacitors = []float64{ integrating, c }
+
+			}
+
+		case TowThomas:
+
+			r := 1.0 / (omega*c)
+			input := r / target
+
+			if (stage.Response == BPF) {
+
+				input = q*r / target
+
+			}
+
+			stage.Resistors = []float64{ input, q*r, r, r, reference, reference }
+			stage.Capacitors = []float64{ c, c }
+			stage.Amplifiers = 3
+
+		case StateVariable:
+
+			r := 1.0 / (omega*c)
+			gain := target
+
+			switch stage.Response {
+
+				case BPF:
+
+					gain = target / q
+
+				case Notch:
+
+					gain = 1.0
+
+			}
+
+			damping := reference*math.Max((2.0 + gain)*q - 1.0, 0.0)
+			stage.Resistors = []float64{ reference / gain, reference, reference, damping, reference, r, r }
+			stage.Capacitors = []float64{ c, c }
+			stage.Amplifiers = 3
+
+			if (stage.Response == Notch) {
+
+				high := stage.Numerator[0] / gain
+				low := stage.Numerator[2] / (gain*omega*omega)
+				stage.Resistors = append(stage.Resistors, reference, reference / high, reference / low)
+				stage.Amplifiers = 4
+
+			}
+
+	}
+
+	return stage
+
+}
+
+func (s Stage) coefficients() ([]float64, []float64) {
+
+	r := s.Resistors
+	c := s.Capacitors
+
+	switch s.Topology {
+
+		case FirstOrder:
+
+			if (s.Response == HPF) {
+
+				return []float64{ r[0]*c[0], 0.0 }, []float64{ r[0]*c[0], 1.0 }
+
+			}
+
+			return []float64{ 1.0 }, []float64{ r[0]*c[0], 1.0 }
+
+		case SallenKeyUnity, SallenKeyEqual:
+
+			amplifier := 1.0
+			input := r[0]
+			capacitance := c[0]
+
+			if (s.Topology == SallenKeyEqual) {
+
+				amplifier = 1.0 + r[3] / r[2]
+
+			}
+
+			gain := amplifier
+
+			// an input divider is replaced by its thevenin equivalent
+			if (len(c) > 2) {
+
+				capacitance = c[0] + c[2]
+				gain *= c[0] / capacitance
+
+			} else if (len(r) > 4) {
+
+				input = r[0]*r[4] / (r[0] + r[4])
+				gain *= r[4] / (r[0] + r[4])
+
+			}
+
+			if (s.Response == HPF) {
+
+				return []float64{ gain*r[0]*r[1]*capacitance*c[1], 0.0, 0.0 },
+					   []float64{ r[0]*r[1]*capacitance*c[1], r[0]*(capacitance + c[1]) + r[1]*c[1]*(1.0 - amplifier), 1.0 }
+
+			}
+
+			return []float64{ gain },
+				   []float64{ input*r[1]*capacitance*c[1], input*c[1] + r[1]*c[1] + input*capacitance*(1.0 - amplifier), 1.0 }
+
+		case MultipleFeedback:
+
+			switch s.Response {
+
+				case HPF:
+
+					return []float64{ -c[0]*c[2]*r[0]*r[1], 0.0, 0.0 },
+						   []float64{ c[1]*c[2]*r[0]*r[1], r[0]*(c[0] + c[1] + c[2]), 1.0 }
+
+				case BPF:
+
+					return []float64{ -c[1]*r[2], 0.0 },
+						   []float64{ c[0]*c[1]*r[0]*r[2], r[0]*(c[0] + c[1]), 1.0 + r[0] / r[1] }
+
+			}
+
+			return []float64{ -r[1] / r[0] },
+				   []float64{ c[0]*c[1]*r[1]*r[2], c[0]*(r[1] + r[2] + r[1]*r[2] / r[0]), 1.0 }
+
+		case TowThomas:
+
+			denominator := []float64{ c[0]*c[1]*r[2]*r[3], r[2]*r[3]*c[1] / r[1], r[5] / r[4] }
+
+			if (s.Response == BPF) {
+
+				return []float64{ -r[2]*r[3]*c[1] / r[0], 0.0 }, denominator
+
+			}
+
+			return []float64{ r[2] / r[0] }, denominator
+
+		case StateVariable:
+
+			input := r[2] / r[0]
+			loop := r[2] / r[1]
+			divider := r[4] / (r[3] + r[4])
+			first := r[5]*c[0]
+			second := r[6]*c[1]
+			denominator := []float64{ first*second, (1.0 + input + loop)*divider*second, loop }
+
+			switch s.Response {
+
+				case HPF:
+
+					return []float64{ -input*first*second, 0.0, 0.0 }, denominator
+
+				case BPF:
+
+					return []float64{ input*second, 0.0 }, denominator
+
+				case Notch:
+
+					return []float64{ input*first*second*r[7] / r[8], 0.0, input*r[7] / r[9] }, denominator
+
+			}
+
+			return []float64{ -input }, denominator
+
+	}
+
+	return slices.Clone(s.Numerator), slices.Clone(s.Denominator)
+
+}
+
+func (s Stage) response(x complex128) complex128 {
+
+	numerator, denominator := s.coefficients()
+	return hornerComplex(numerator, x) / hornerComplex(denominator, x)
+
+}
+
+func (s Stage) passbandGain() float64 {
+
+	switch s.Response {
+
+		case HPF:
+
+			numerator, denominator := s.coefficients()
+			return math.Abs(numerator[0] / denominator[0])
+
+		case BPF:
+
+			return cmplx.Abs(s.response(complex(0, 2.0*math.Pi*s.Frequency)))
+
+	}
+
+	return cmplx.Abs(s.response(0))
+
+}
+
+func (s Stage) noiseGain() float64 {
+
+	r := s.Resistors
+	c := s.Capacitors
+
+	switch s.Topology {
+
+		case SallenKeyEqual:
+
+			return 1.0 + r[3] / r[2]
+
+		case MultipleFeedback:
+
+			switch s.Response {
+
+				case HPF:
+
+					return 1.0 + c[0] / c[1]
+
+				case BPF:
+
+					return 1.0 + r[2] / (2.0*r[0])
+
+			}
+
+			return 1.0 + r[1] / r[0]
+
+		case TowThomas:
+
+			return 2.0
+
+		case StateVariable:
+
+			return 1.0 + r[2] / r[0] + r[2] / r[1]
+
+	}
+
+	return 1.0
+
+}
+
+func designActive(config Specs) (ActiveFilter, error) {
+
+	topology := SallenKeyUnity
+
+	if (config.Topology.exists() && (config.Topology != FirstOrder)) {
+
+		topology = config.Topology
+
+	}
+
+	capacitance := 10e-9
+
+	if ((config.Capacitance != nil) && (*config.Capacitance > 0.0)) {
+
+		capacitance = *config.Capacitance
+
+	}
+
+	filter := ActiveFilter{ Stages: analogueSections(config) }
+
+	if ((config.GainBandwidth != nil) && (*config.GainBandwidth > 0.0)) {
+
+		filter.GainBandwidth = *config.GainBandwidth
+
+	}
+
+	problems := []error{}
+
+	for index, stage := range filter.Stages {
+
+		stage = designStage(stage, topology, capacitance)
+
+		if ((topology == config.Topology) && (stage.Topology != FirstOrder) && (stage.Topology != topology)) {
+
+			problems = append(problems, fmt.Errorf("%s cannot realize the %s stage at %.6g Hz, used %s", topology, stage.Response, stage.Frequency, stage.Topology))
+
+		}
+
+		stage.GainBandwidth = 100.0*stage.noiseGain()*stage.Frequency
+
+		if (stage.Topology != FirstOrder) {
+
+			stage.GainBandwidth *= stage.Q
+
+		}
+
+		stage.Gain = stage.passbandGain()
+		requested := Stage{ Response: stage.Response, Frequency: stage.Frequency, Numerator: stage.Numerator, Denominator: stage.Denominator }.passbandGain()
+
+		if ((stage.Topology == SallenKeyEqual) && (stage.Gain < requested*(1.0 - 1e-9))) {
+
+			problems = append(problems, fmt.Errorf("%s reaches a gain of %.6g for the %s stage at %.6g Hz, %.6g was requested", stage.Topology, stage.Gain, stage.Response, stage.Frequency, requested))
+
+		}
+
+		stage.Adequate = ((filter.GainBandwidth == 0.0) || (filter.GainBandwidth >= stage.GainBandwidth))
+		filter.Stages[index] = stage
+
+	}
+
+	return filter, errors.Join(problems...)
+
+}
+
+func (a ActiveFilter) response(frequency float64) complex128 {
+
+	x := complex(0, 2.0*math.Pi*frequency)
+	product := complex(1, 0)
+
+	for _, stage := range a.Stages {
+
+		product *= stage.response(x)
+
+	}
+
+	return product
+
+}
@@ -0,0 +1,143 @@
+package main
+
+import ( "math"
+		 "math/cmplx"
+		 "testing" )
+
+
+func TestActiveStages(t *testing.T) {
+
+	tests := []struct {
+
+		topology Topology
+		response Response
+
+	}{
+
+		{ SallenKeyUnity, LPF },
+		{ SallenKeyUnity, HPF },
+		{ SallenKeyEqual, LPF },
+		{ SallenKeyEqual, HPF },
+		{ MultipleFeedback, LPF },
+		{ MultipleFeedback, HPF },
+		{ MultipleFeedback, BPF },
+		{ StateVariable, LPF },
+		{ StateVariable, HPF },
+		{ StateVariable, BPF },
+		{ StateVariable, BSF },
+		{ TowThomas, LPF },
+		{ TowThomas, BPF },
+
+	}
+
+	for _, test := range tests {
+
+		filter, err := designActive(Specs{ Response: test.response, Approximation: Chebyshev, PassbandAttenuation: pointer(1.0), Order: pointer(uint16(4)), CutoffFrequency: pointer(1000.0), CenterFrequency: pointer(1000.0), Bandwidth: pointer(300.0), Topology: test.topology })
+
+		if (err != nil) {
+
+			t.Fatalf("%s %s: %v", test.topology, test.response, err)
+
+		}
+
+		for _, stage := range filter.Stages {
+
+			if (stage.Topology != test.topology) {
+
+				t.Errorf("%s %s: stage at %g Hz used %s", test.topology, test.response, stage.Frequency, stage.Topology)
+
+			}
+
+			// inverting topologies flip the sign, so compare magnitudes of the realized and target sections
+			for _, frequency := range []float64{ 100.0, 700.0, 1000.0, 1300.0, 5000.0 } {
+
+				x := complex(0, 2.0*math.Pi*frequency)
+				want := cmplx.Abs(hornerComplex(stage.Numerator, x) / hornerComplex(stage.Denominator, x))
+
+				if got := cmplx.Abs(stage.response(x)); (math.Abs(got - want) > 1e-9*math.Max(want, 1.0)) {
+
+					t.Errorf("%s %s stage at %g Hz: gain at %g Hz = %g, want %g", test.topology, test.response, stage.Frequency, frequency, got, want)
+
+				}
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestActiveFallback(t *testing.T) {
+
+	filter, err := designActive(Specs{ Response: HPF, Order: pointer(uint16(2)), CutoffFrequency: pointer(1000.0), Topology: TowThomas })
+
+	if ((err == nil) || (err.Error() != "tow-thomas cannot realize the hpf stage at 1000 Hz, used state variable")) {
+
+		t.Errorf("got %v", err)
+
+	}
+
+	if ((len(filter.Stages) != 1) || (filter.Stages[0].Topology != StateVariable)) {
+
+		t.Errorf("fell back to %+v, want a state variable stage", filter.Stages)
+
+	}
+
+}
+
+func TestSallenKeyEqualDivider(t *testing.T) {
+
+	// the equal component stage has a fixed gain of 3 - 1/Q, an input divider brings it back to unity
+	for response, count := range map[Response][2]int{ LPF: { 5, 2 }, HPF: { 4, 3 } } {
+
+		filter, err := designActive(Specs{ Response: response, Order: pointer(uint16(4)), CutoffFrequency: pointer(1000.0), Topology: SallenKeyEqual })
+
+		if (err != nil) {
+
+			t.Fatalf("%s: %v", response, err)
+
+		}
+
+		for _, stage := range filter.Stages {
+
+			if (math.Abs(stage.Gain - 1.0) > 1e-9) {
+
+				t.Errorf("%s stage with Q %g has a gain of %g, want 1", response, stage.Q, stage.Gain)
+
+			}
+
+			if ((len(stage.Resistors) != count[0]) || (len(stage.Capacitors) != count[1])) {
+
+				t.Errorf("%s stage has %d resistors and %d capacitors, want %d and %d", response, len(stage.Resistors), len(stage.Capacitors), count[0], count[1])
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestActiveGainBandwidth(t *testing.T) {
+
+	filter, _ := designActive(Specs{ Order: pointer(uint16(4)), CutoffFrequency: pointer(1000.0), GainBandwidth: pointer(1e5) })
+
+	for _, stage := range filter.Stages {
+
+		// a hundred times the unity gain stage's Q and frequency
+		if want := 100.0*stage.Q*stage.Frequency; (math.Abs(stage.GainBandwidth - want) > 1e-6) {
+
+			t.Errorf("stage with Q %g needs %g Hz, want %g Hz", stage.Q, stage.GainBandwidth, want)
+
+		}
+
+		if (stage.Adequate != (stage.GainBandwidth <= 1e5)) {
+
+			t.Errorf("stage needing %g Hz adequate %t on a 100 kHz op-amp", stage.GainBandwidth, stage.Adequate)
+
+		}
+
+	}
+
+}
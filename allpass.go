@@ -0,0 +1,198 @@
+package main
+
+import ( "math" )
+
+
+type Equalizer struct {
+
+	Sections	  []Section
+	Equalizer	  Polynomial
+	Cascade		  Polynomial
+	Delay		  float64
+	InitialRipple float64
+	Ripple		  float64
+
+}
+
+func allPassSection(radius float64, angle float64) Section {
+
+	a1 := -2.0*radius*math.Cos(angle)
+	a2 := math.Pow(radius, 2)
+
+	return Section{
+
+		Numerator: [3]float64{ a2, a1, 1.0 },
+		Denominator: [3]float64{ 1.0, a1, a2 },
+
+	}
+
+}
+
+func allPassDelay(radius float64, angle float64, omega float64) float64 {
+
+	delay := 0.0
+	r := math.Pow(radius, 2)
+
+	for _, pole := range []float64{ angle, -angle } {
+
+		delay += (1.0 - r) / (1.0 - 2.0*radius*math.Cos(omega - pole) + r)
+
+	}
+
+	return delay
+
+}
+
+func allPassParameters(parameters []float64, index int) (float64, float64) {
+
+	radius := 0.99 / (1.0 + math.Exp(-parameters[2*index]))
+	angle := math.Pi / (1.0 + math.Exp(-parameters[2*index + 1]))
+	return radius, angle
+
+}
+
+func delayRipple(delay []float64) (float64, float64) {
+
+	minimum := math.Inf(1)
+	maximum := math.Inf(-1)
+	mean := 0.0
+
+	for _, value := range delay {
+
+		minimum = math.Min(minimum, value)
+		maximum = math.Max(maximum, value)
+		mean += value / float64(len(delay))
+
+	}
+
+	return maximum - minimum, mean
+
+}
+
+func equalizeGroupDelay(filter Polynomial, lowerFrequency float64, upperFrequency float64, samplingFrequency float64, maximumSections ...uint16) Equalizer {
+
+	limit := 4
+	points := 64
+
+	if (len(maximumSections) > 0) {
+
+		limit = int(maximumSections[0])
+
+	}
+
+	if (lowerFrequency > upperFrequency) {
+
+		lowerFrequency, upperFrequency = upperFrequency, lowerFrequency
+
+	}
+
+	grid := linearSpace(lowerFrequency, upperFrequency, points)
+	omega := make([]float64, points)
+	target := make([]float64, points)
+
+	for index, frequency := range grid {
+
+		omega[index] = 2.0*math.Pi*frequency / samplingFrequency
+		target[index] = groupDelay(filter, frequency, samplingFrequency)*samplingFrequency
+
+	}
+
+	total := func(parameters []float64) []float64 {
+
+		delay := append([]float64{}, target...)
+
+		for section := 0; section < len(parameters) / 2; section++ {
+
+			radius, angle := allPassParameters(parameters, section)
+
+			for index := range delay {
+
+				delay[index] += allPassDelay(radius, angle, omega[index])
+
+			}
+
+		}
+
+		return delay
+
+	}
+
+	cost := func(parameters []float64) float64 {
+
+		delay := total(parameters)
+		_, mean := delayRipple(delay)
+		variance := 0.0
+
+		for _, value := range delay {
+
+			variance += math.Pow(value - mean, 2)
+
+		}
+
+		return variance / float64(len(delay))
+
+	}
+
+	parameters := []float64{}
+	ripple, _ := delayRipple(target)
+	initialRipple := ripple
+
+	for section := 0; section < limit; section++ {
+
+		delay := total(parameters)
+		deepest := 0
+
+		for index := range delay {
+
+			if (delay[index] < delay[deepest]) {
+
+				deepest = index
+
+			}
+
+		}
+
+		angle := math.Min(math.Max(omega[deepest], 1e-3), math.Pi - 1e-3)
+		candidate := append(append([]float64{}, parameters...),
+							math.Log(0.7 / (0.99 - 0.7)),
+							math.Log(angle / (math.Pi - angle)))
+
+		candidate, _ = nelderMead(cost, candidate, 400*len(candidate), 1e-10)
+		candidateRipple, _ := delayRipple(total(candidate))
+
+		if (candidateRipple > 0.98*ripple) {
+
+			break
+
+		}
+
+		parameters = candidate
+		ripple = candidateRipple
+
+	}
+
+	sections := []Section{}
+
+	for section := 0; section < len(parameters) / 2; section++ {
+
+		radius, angle := allPassParameters(parameters, section)
+		sections = append(sections, allPassSection(radius, angle))
+
+	}
+
+	_, mean := delayRipple(total(parameters))
+	cascade := filter
+	cascade.multiply(cascadePolynomial(sections))
+
+	return Equalizer{
+
+		Sections: sections,
+		Equalizer: cascadePolynomial(sections),
+		Cascade: cascade,
+		Delay: mean / samplingFrequency,
+		InitialRipple: initialRipple / samplingFrequency,
+		Ripple: ripple / samplingFrequency,
+
+	}
+
+}
@@ -0,0 +1,112 @@
+package main
+
+import ( "math"
+		 "testing" )
+
+
+// fourth order butterworth low pass at 100 Hz for a 1 kHz sampling rate
+var (
+
+	equalizerNumerator = []float64{ 0.00482434, 0.01929737, 0.02894606, 0.01929737, 0.00482434 }
+	equalizerDenominator = []float64{ 1.0, -2.36951301, 2.31398841, -1.05466541, 0.18737949 }
+
+)
+
+func TestAllPassSection(t *testing.T) {
+
+	const samplingFrequency = 1000.0
+
+	for _, pole := range [][2]float64{ { 0.5, 0.3 }, { 0.9, 1.2 }, { 0.7, 2.8 } } {
+
+		section := allPassSection(pole[0], pole[1])
+		filter := cascadePolynomial([]Section{ section })
+
+		for _, frequency := range []float64{ 0.0, 50.0, 125.0, 300.0, 480.0 } {
+
+			omega := 2.0*math.Pi*frequency / samplingFrequency
+
+			if magnitude := magnitudeResponse(filter, frequency, samplingFrequency); (math.Abs(magnitude - 1.0) > 1e-12) {
+
+				t.Errorf("r=%g θ=%g: |H| at %g Hz = %.15g, want 1", pole[0], pole[1], frequency, magnitude)
+
+			}
+
+			// groupDelay is in seconds, allPassDelay in samples
+			got := groupDelay(filter, frequency, samplingFrequency)*samplingFrequency
+			want := allPassDelay(pole[0], pole[1], omega)
+
+			if (math.Abs(got - want) > 1e-6*want) {
+
+				t.Errorf("r=%g θ=%g: delay at %g Hz = %.12g samples, want %.12g", pole[0], pole[1], frequency, got, want)
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestDelayRipple(t *testing.T) {
+
+	ripple, mean := delayRipple([]float64{ 2.0, 5.0, 3.0, 6.0 })
+
+	if ((ripple != 4.0) || (mean != 4.0)) {
+
+		t.Errorf("ripple, mean = %g, %g, want 4, 4", ripple, mean)
+
+	}
+
+}
+
+func TestEqualizeGroupDelay(t *testing.T) {
+
+	const samplingFrequency = 1000.0
+
+	filter := digitalPolynomial(equalizerNumerator, equalizerDenominator)
+	equalizer := equalizeGroupDelay(filter, 0.0, 80.0, samplingFrequency, 3)
+
+	if ((len(equalizer.Sections) == 0) || (len(equalizer.Sections) > 3)) {
+
+		t.Fatalf("got %d sections, want between 1 and 3", len(equalizer.Sections))
+
+	}
+
+	if (equalizer.Ripple >= equalizer.InitialRipple / 2.0) {
+
+		t.Errorf("ripple %g s did not improve on %g s", equalizer.Ripple, equalizer.InitialRipple)
+
+	}
+
+	minimum, maximum := math.Inf(1), math.Inf(-1)
+
+	for _, frequency := range linearSpace(0.0, 80.0, 64) {
+
+		// the equalizer is all-pass, so the cascade keeps the magnitude of the filter
+		want := magnitudeResponse(filter, frequency, samplingFrequency)
+
+		if got := magnitudeResponse(equalizer.Cascade, frequency, samplingFrequency); (math.Abs(got - want) > 1e-9) {
+
+			t.Errorf("|H| at %g Hz = %.12g, want %.12g", frequency, got, want)
+
+		}
+
+		delay := groupDelay(equalizer.Cascade, frequency, samplingFrequency)
+		minimum = math.Min(minimum, delay)
+		maximum = math.Max(maximum, delay)
+
+	}
+
+	if (math.Abs((maximum - minimum) - equalizer.Ripple) > 1e-6) {
+
+		t.Errorf("measured ripple %g s, reported %g s", maximum - minimum, equalizer.Ripple)
+
+	}
+
+	if ((equalizer.Delay < minimum) || (equalizer.Delay > maximum)) {
+
+		t.Errorf("mean delay %g s outside [%g, %g]", equalizer.Delay, minimum, maximum)
+
+	}
+
+}
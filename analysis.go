@@ -0,0 +1,70 @@
+package main
+
+import ( "math"
+		 "math/cmplx" )
+
+
+func frequencyVariable(frequency float64, samplingFrequency ...float64) (complex128, complex128) {
+
+	omega := 2.0*math.Pi*frequency
+
+	if ((len(samplingFrequency) > 0) &&
+		(samplingFrequency[0] > 0.0)) {
+
+		samplingPeriod := 1.0 / samplingFrequency[0]
+		z := cmplx.Exp(complex(0, omega*samplingPeriod))
+		return z, complex(0, samplingPeriod)*z
+
+	}
+
+	return complex(0, omega), complex(0, 1)
+
+}
+
+func frequencyResponse(p Polynomial, frequency float64, samplingFrequency ...float64) complex128 {
+
+	x, _ := frequencyVariable(frequency, samplingFrequency...)
+	return p.response(x)
+
+}
+
+func magnitudeResponse(p Polynomial, frequency float64, samplingFrequency ...float64) float64 {
+
+	return cmplx.Abs(frequencyResponse(p, frequency, samplingFrequency...))
+
+}
+
+func decibels(magnitude float64) float64 {
+
+	return 20.0*math.Log10(magnitude)
+
+}
+
+func phaseResponse(p Polynomial, frequency float64, samplingFrequency ...float64) float64 {
+
+	return cmplx.Phase(frequencyResponse(p, frequency, samplingFrequency...))
+
+}
+
+func groupDelay(p Polynomial, frequency float64, samplingFrequency ...float64) float64 {
+
+	x, dx := frequencyVariable(frequency, samplingFrequency...)
+	slope := complex(0, 0)
+	numerator := p.Numerator.response(x)
+	denominator := p.Denominator.response(x)
+
+	if (numerator != 0) {
+
+		slope += p.Numerator.derivative(x) / numerator
+
+	}
+
+	if (denominator != 0) {
+
+		slope -= p.Denominator.derivative(x) / denominator
+
+	}
+
+	return -imag(slope*dx)
+
+}
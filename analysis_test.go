@@ -0,0 +1,67 @@
+package main
+
+import ( "math"
+		 "testing" )
+
+
+func TestAnalogueResponse(t *testing.T) {
+
+	// H(s) = 1/(s + 1) has |H| = 1/sqrt(1 + ω²), phase -atan(ω) and delay 1/(1 + ω²)
+	filter := analoguePolynomial([]float64{ 1.0 }, []float64{ 1.0, 1.0 })
+
+	for _, omega := range []float64{ 0.0, 0.5, 1.0, 3.0 } {
+
+		frequency := omega / (2.0*math.Pi)
+
+		if got, want := magnitudeResponse(filter, frequency), 1.0 / math.Sqrt(1.0 + omega*omega); (math.Abs(got - want) > 1e-12) {
+
+			t.Errorf("|H(j%g)| = %.15g, want %.15g", omega, got, want)
+
+		}
+
+		if got, want := phaseResponse(filter, frequency), -math.Atan(omega); (math.Abs(got - want) > 1e-12) {
+
+			t.Errorf("arg H(j%g) = %.15g, want %.15g", omega, got, want)
+
+		}
+
+		if got, want := groupDelay(filter, frequency), 1.0 / (1.0 + omega*omega); (math.Abs(got - want) > 1e-6) {
+
+			t.Errorf("delay at ω=%g = %.12g, want %.12g", omega, got, want)
+
+		}
+
+	}
+
+	if got := decibels(magnitudeResponse(filter, 1.0 / (2.0*math.Pi))); (math.Abs(got + 10.0*math.Log10(2.0)) > 1e-12) {
+
+		t.Errorf("gain at the corner = %g dB, want -3.0103 dB", got)
+
+	}
+
+}
+
+func TestDigitalResponse(t *testing.T) {
+
+	// a pure delay of three samples
+	const samplingFrequency = 8000.0
+
+	filter := digitalPolynomial([]float64{ 0.0, 0.0, 0.0, 1.0 }, []float64{ 1.0 })
+
+	for _, frequency := range []float64{ 100.0, 1000.0, 3000.0 } {
+
+		if got := magnitudeResponse(filter, frequency, samplingFrequency); (math.Abs(got - 1.0) > 1e-12) {
+
+			t.Errorf("|H| at %g Hz = %.15g, want 1", frequency, got)
+
+		}
+
+		if got := groupDelay(filter, frequency, samplingFrequency); (math.Abs(got - 3.0 / samplingFrequency) > 1e-9) {
+
+			t.Errorf("delay at %g Hz = %g s, want %g s", frequency, got, 3.0 / samplingFrequency)
+
+		}
+
+	}
+
+}
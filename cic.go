@@ -0,0 +1,209 @@
+package main
+
+import ( "math" )
+
+
+func (c CIC) parameters() (int, int, int) {
+
+	return max(int(c.Stages), 1), max(int(c.DifferentialDelay), 1), max(int(c.Rate), 1)
+
+}
+
+func (c CIC) gain() float64 {
+
+	n, m, r := c.parameters()
+	return math.Pow(float64(r*m), float64(n))
+
+}
+
+func (c CIC) response(frequency float64) float64 {
+
+	n, m, r := c.parameters()
+	samplingFrequency := c.SamplingFrequency
+
+	if (samplingFrequency == 0.0) {
+
+		samplingFrequency = 1.0
+
+	}
+
+	x := math.Pi*frequency / samplingFrequency
+	denominator := float64(r*m)*math.Sin(x)
+
+	if (math.Abs(denominator) < 1e-15) {
+
+		return 1.0
+
+	}
+
+	return math.Pow(math.Abs(math.Sin(float64(r*m)*x) / denominator), float64(n))
+
+}
+
+func (c CIC) polynomial() Polynomial {
+
+	n, m, r := c.parameters()
+	boxcar := make([]float64, r*m)
+
+	for index := range boxcar {
+
+		boxcar[index] = 1.0
+
+	}
+
+	numerator := []float64{ 1.0 }
+
+	for stage := 0; stage < n; stage++ {
+
+		numerator = convolve(numerator, boxcar)
+
+	}
+
+	return digitalPolynomial(numerator, []float64{ c.gain() })
+
+}
+
+func (c CIC) bitGrowth() uint16 {
+
+	n, m, r := c.parameters()
+	growth := float64(n)*math.Log2(float64(r*m))
+
+	if c.Interpolator {
+
+		growth -= math.Log2(float64(r))
+
+	}
+
+	return uint16(math.Ceil(growth))
+
+}
+
+func (c CIC) registerWidths(inputWidth uint16) []uint16 {
+
+	n, m, r := c.parameters()
+	widths := make([]uint16, 2*n)
+
+	for stage := 1; stage <= 2*n; stage++ {
+
+		growth := float64(n)*math.Log2(float64(r*m))
+
+		if c.Interpolator {
+
+			if (stage <= n) {
+
+				growth = float64(stage)
+
+			} else {
+
+				growth = float64(2*n - stage) + float64(stage - n)*math.Log2(float64(r*m)) - math.Log2(float64(r))
+
+			}
+
+		}
+
+		widths[stage - 1] = inputWidth + uint16(math.Ceil(growth))
+
+	}
+
+	return widths
+
+}
+
+func designCompensator(c CIC, taps uint16, passbandEdge float64, stopbandEdge ...float64) []float64 {
+
+	_, _, r := c.parameters()
+	samplingFrequency := c.SamplingFrequency
+
+	if (samplingFrequency == 0.0) {
+
+		samplingFrequency = 1.0
+
+	}
+
+	rate := samplingFrequency / float64(r)
+	stop := passbandEdge + (rate / 2.0 - passbandEdge) / 2.0
+
+	if ((len(stopbandEdge) > 0) &&
+		(stopbandEdge[0] > passbandEdge)) {
+
+		stop = stopbandEdge[0]
+
+	}
+
+	length := int(taps)
+
+	if (length%2 == 0) {
+
+		length++
+
+	}
+
+	half := (length - 1) / 2
+	size := half + 1
+	matrix := make([][]float64, size)
+	vector := make([]float64, size)
+
+	for row := range matrix {
+
+		matrix[row] = make([]float64, size)
+
+	}
+
+	weights := map[bool]float64{ true: 1.0, false: 10.0 }
+
+	for _, frequency := range linearSpace(0.0, rate / 2.0, 16*length) {
+
+		passband := frequency <= passbandEdge
+
+		if (!passband && (frequency < stop)) {
+
+			continue
+
+		}
+
+		target := 0.0
+
+		if passband {
+
+			target = 1.0 / c.response(frequency)
+
+		}
+
+		omega := 2.0*math.Pi*frequency / rate
+		basis := make([]float64, size)
+
+		for k := range basis {
+
+			basis[k] = 2.0*math.Cos(float64(k)*omega)
+
+		}
+
+		basis[0] = 1.0
+
+		for i := range basis {
+
+			for j := range basis {
+
+				matrix[i][j] += weights[passband]*basis[i]*basis[j]
+
+			}
+
+			vector[i] += weights[passband]*basis[i]*target
+
+		}
+
+	}
+
+	solution := solveLinear(matrix, vector)
+	coefficients := make([]float64, length)
+
+	for k, value := range solution {
+
+		coefficients[half + k] = value
+		coefficients[half - k] = value
+
+	}
+
+	return coefficients
+
+}
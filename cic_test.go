@@ -0,0 +1,116 @@
+package main
+
+import ( "math"
+		 "slices"
+		 "testing" )
+
+
+func TestCICResponse(t *testing.T) {
+
+	c := CIC{ Stages: 4, DifferentialDelay: 1, Rate: 16, SamplingFrequency: 16000.0 }
+	filter := c.polynomial()
+
+	if (c.gain() != 65536.0) {
+
+		t.Errorf("gain = %g, want (RM)^N = 65536", c.gain())
+
+	}
+
+	for _, frequency := range []float64{ 0.0, 100.0, 250.0, 600.0, 3000.0 } {
+
+		if got, want := magnitudeResponse(filter, frequency, 16000.0), c.response(frequency); (math.Abs(got - want) > 1e-9) {
+
+			t.Errorf("polynomial gain at %g Hz = %.12g, closed form %.12g", frequency, got, want)
+
+		}
+
+	}
+
+	// the comb nulls every multiple of the output rate
+	for _, frequency := range []float64{ 1000.0, 2000.0, 5000.0 } {
+
+		if got := c.response(frequency); (got > 1e-12) {
+
+			t.Errorf("gain at %g Hz = %g, want a null", frequency, got)
+
+		}
+
+	}
+
+}
+
+func TestCICRegisterWidths(t *testing.T) {
+
+	decimator := CIC{ Stages: 4, DifferentialDelay: 1, Rate: 16 }
+
+	if (decimator.bitGrowth() != 16) {
+
+		t.Errorf("decimator growth = %d bits, want 16", decimator.bitGrowth())
+
+	}
+
+	if widths := decimator.registerWidths(12); !slices.Equal(widths, []uint16{ 28, 28, 28, 28, 28, 28, 28, 28 }) {
+
+		t.Errorf("decimator widths = %v, want 28 bits throughout", widths)
+
+	}
+
+	// hogenauer: combs grow a bit per stage, integrators by 2^(2N - j) (RM)^(j - N) / R
+	interpolator := CIC{ Stages: 4, DifferentialDelay: 1, Rate: 16, Interpolator: true }
+
+	if (interpolator.bitGrowth() != 12) {
+
+		t.Errorf("interpolator growth = %d bits, want 12", interpolator.bitGrowth())
+
+	}
+
+	if widths := interpolator.registerWidths(12); !slices.Equal(widths, []uint16{ 13, 14, 15, 16, 15, 18, 21, 24 }) {
+
+		t.Errorf("interpolator widths = %v, want [13 14 15 16 15 18 21 24]", widths)
+
+	}
+
+}
+
+func TestCICCompensator(t *testing.T) {
+
+	c := CIC{ Stages: 4, DifferentialDelay: 1, Rate: 16, SamplingFrequency: 16000.0 }
+	taps := designCompensator(c, 20, 250.0)
+
+	if (len(taps) != 21) {
+
+		t.Fatalf("got %d taps, want an odd length of 21", len(taps))
+
+	}
+
+	for index := range taps {
+
+		if (taps[index] != taps[len(taps) - index - 1]) {
+
+			t.Errorf("taps are not symmetric at %d", index)
+
+		}
+
+	}
+
+	compensator := digitalPolynomial(taps, []float64{ 1.0 })
+	uncompensated := decibels(c.response(250.0))
+
+	for _, frequency := range []float64{ 0.0, 100.0, 200.0, 250.0 } {
+
+		if got := decibels(c.response(frequency)*magnitudeResponse(compensator, frequency, 1000.0)); (math.Abs(got) > 0.2) {
+
+			t.Errorf("compensated gain at %g Hz = %g dB, want within 0.2 dB (uncompensated droop %g dB)", frequency, got, uncompensated)
+
+		}
+
+	}
+
+}
+
+func TestSolveLinear(t *testing.T) {
+
+	solution := solveLinear([][]float64{ { 2.0, 1.0, -1.0 }, { -3.0, -1.0, 2.0 }, { -2.0, 1.0, 2.0 } }, []float64{ 8.0, -11.0, -3.0 })
+	compareSignals(t, "solution", solution, []float64{ 2.0, 3.0, -1.0 }, 1e-12)
+
+}
@@ -0,0 +1,496 @@
+package main
+
+import ( "fmt"
+		 "math"
+		 "strings" )
+
+
+func identifier(name string) string {
+
+	builder := strings.Builder{}
+
+	for index, character := range strings.ToLower(name) {
+
+		if (((character >= 'a') && (character <= 'z')) ||
+			((character >= '0') && (character <= '9') && (index > 0)) ||
+			(character == '_')) {
+
+			builder.WriteRune(character)
+
+		} else if ((character == ' ') || (character == '-')) {
+
+			builder.WriteRune('_')
+
+		}
+
+	}
+
+	if (builder.Len() == 0) {
+
+		return "filter"
+
+	}
+
+	return builder.String()
+
+}
+
+func testStimulus(samples int) []float64 {
+
+	stimulus := make([]float64, samples)
+	seed := uint32(12345)
+
+	for index := range stimulus {
+
+		seed = seed*1664525 + 1013904223
+		noise := float64(seed >> 8) / float64(1 << 24) - 0.5
+		phase := float64(index)
+		stimulus[index] = 0.25*math.Sin(0.05*phase) + 0.15*math.Sin(0.9*phase) + 0.1*noise
+
+	}
+
+	if (samples > 0) {
+
+		stimulus[0] = 0.5
+
+	}
+
+	return stimulus
+
+}
+
+func parseCodegen(config Codegen) (string, Precision, Structure, Datapath, int) {
+
+	precision := Double
+
+	if config.Precision.exists() {
+
+		precision = config.Precision
+
+	}
+
+	structure := SecondOrderSections
+
+	if config.Structure.exists() {
+
+		structure = config.Structure
+
+	}
+
+	if (precision == Fixed) {
+
+		structure = SecondOrderSections
+
+	}
+
+	datapath := config.Datapath
+
+	if (datapath.Data.bits() == 1) {
+
+		datapath.Data = Format{ Integer: 0, Fraction: 15 }
+
+	}
+
+	if (datapath.Coefficient.bits() == 1) {
+
+		datapath.Coefficient = Format{ Integer: 1, Fraction: 14 }
+
+	}
+
+	samples := config.Samples
+
+	if (samples <= 0) {
+
+		samples = 256
+
+	}
+
+	return identifier(config.Name), precision, structure, datapath, samples
+
+}
+
+func integerType(bits uint16) string {
+
+	if (bits <= 16) {
+
+		return "int16_t"
+
+	} else if (bits <= 32) {
+
+		return "int32_t"
+
+	}
+
+	return "int64_t"
+
+}
+
+func literal(value float64, precision Precision) string {
+
+	if (precision == Single) {
+
+		return singleLiteral(value) + "f"
+
+	}
+
+	return fmt.Sprintf("%.17g", value)
+
+}
+
+func singleLiteral(value float64) string {
+
+	text := fmt.Sprintf("%.9g", float32(value))
+
+	if (!strings.ContainsAny(text, ".eE")) {
+
+		text += ".0"
+
+	}
+
+	return text
+
+}
+
+func writeTable(builder *strings.Builder, declaration string, values []string, width int, grouped ...bool) {
+
+	prefix := ""
+	suffix := ""
+
+	if ((len(grouped) > 0) && grouped[0]) {
+
+		prefix = "{ "
+		suffix = " }"
+
+	}
+
+	fmt.Fprintf(builder, "%s = {\n", declaration)
+
+	for index := 0; index < len(values); index += width {
+
+		end := min(index + width, len(values))
+		fmt.Fprintf(builder, "    %s%s%s", prefix, strings.Join(values[index:end], ", "), suffix)
+
+		if (end < len(values)) {
+
+			builder.WriteString(",")
+
+		}
+
+		builder.WriteString("\n")
+
+	}
+
+	builder.WriteString("};\n\n")
+
+}
+
+func roundingC(rounding Rounding) string {
+
+	switch rounding {
+
+		case Truncate:
+
+			return "    return value >> shift;\n"
+
+		case TowardZero:
+
+			return "    return (value < 0) ? -((-value) >> shift) : (value >> shift);\n"
+
+		case Convergent:
+
+			return "    int64_t half = (int64_t)1 << (shift - 1);\n" +
+				   "    int64_t remainder = value & (((int64_t)1 << shift) - 1);\n" +
+				   "    int64_t quotient = value >> shift;\n" +
+				   "    if ((remainder > half) || ((remainder == half) && (quotient & 1))) {\n" +
+				   "        quotient++;\n" +
+				   "    }\n" +
+				   "    return quotient;\n"
+
+		default:
+
+			return "    return (value + ((int64_t)1 << (shift - 1))) >> shift;\n"
+
+	}
+
+}
+
+func generateC(filter Polynomial, config Codegen) (string, string) {
+
+	name, precision, structure, datapath, samples := parseCodegen(config)
+	guard := strings.ToUpper(name) + "_H"
+	sampleType := string(precision)
+	sections := filter.sections()
+	numerator, denominator := filter.digitalCoefficients()
+	order := max(len(numerator), len(denominator))
+	numerator = append(numerator, make([]float64, order - len(numerator))...)
+	denominator = append(denominator, make([]float64, order - len(denominator))...)
+	stimulus := testStimulus(samples)
+	expected := make([]float64, samples)
+
+	if (structure == SymmetricFolded) {
+
+		// folding only pays off for symmetric fir taps, so a general filter falls back like the hdl generator does
+		structure = DirectFormI
+
+	}
+
+	if (precision == Fixed) {
+
+		fixed := newFixedPoint(sections, datapath)
+		datapath = fixed.Datapath
+		sampleType = integerType(datapath.Data.bits())
+		fixed.ProcessBlock(stimulus, expected)
+
+		for index := range stimulus {
+
+			q, _ := datapath.Data.quantize(stimulus[index], datapath.Rounding)
+			stimulus[index] = float64(q)
+			q, _ = datapath.Data.quantize(expected[index], datapath.Rounding)
+			expected[index] = float64(q)
+
+		}
+
+	} else if (structure == SecondOrderSections) {
+
+		newCascadeProcessor(sections).ProcessBlock(stimulus, expected)
+
+	} else {
+
+		newProcessor(filter, structure).ProcessBlock(stimulus, expected)
+
+	}
+
+	header := strings.Builder{}
+	fmt.Fprintf(&header, "#ifndef %s\n#define %s\n\n#include <stdint.h>\n\n", guard, guard)
+
+	if (structure == SecondOrderSections) {
+
+		fmt.Fprintf(&header, "#define %s_SECTIONS %d\n", strings.ToUpper(name), len(sections))
+
+	} else {
+
+		fmt.Fprintf(&header, "#define %s_ORDER %d\n", strings.ToUpper(name), order - 1)
+
+	}
+
+	fmt.Fprintf(&header, "#define %s_TEST_LENGTH %d\n\n", strings.ToUpper(name), samples)
+
+	if (precision == Fixed) {
+
+		fmt.Fprintf(&header, "typedef struct {\n    %s x[%s_SECTIONS][2];\n    %s y[%s_SECTIONS][2];\n} %s_state;\n\n",
+					sampleType, strings.ToUpper(name), sampleType, strings.ToUpper(name), name)
+
+	} else if (structure == SecondOrderSections) {
+
+		fmt.Fprintf(&header, "typedef struct {\n    %s z[%s_SECTIONS][2];\n} %s_state;\n\n", sampleType, strings.ToUpper(name), name)
+
+	} else if (structure == DirectFormI) {
+
+		fmt.Fprintf(&header, "typedef struct {\n    %s x[%s_ORDER > 0 ? %s_ORDER : 1];\n    %s y[%s_ORDER > 0 ? %s_ORDER : 1];\n} %s_state;\n\n",
+					sampleType, strings.ToUpper(name), strings.ToUpper(name), sampleType, strings.ToUpper(name), strings.ToUpper(name), name)
+
+	} else if (structure == DirectFormII) {
+
+		fmt.Fprintf(&header, "typedef struct {\n    %s w[%s_ORDER > 0 ? %s_ORDER : 1];\n} %s_state;\n\n",
+					sampleType, strings.ToUpper(name), strings.ToUpper(name), name)
+
+	} else {
+
+		fmt.Fprintf(&header, "typedef struct {\n    %s z[%s_ORDER > 0 ? %s_ORDER : 1];\n} %s_state;\n\n",
+					sampleType, strings.ToUpper(name), strings.ToUpper(name), name)
+
+	}
+
+	fmt.Fprintf(&header, "void %s_init(%s_state *state);\n", name, name)
+	fmt.Fprintf(&header, "void %s_reset(%s_state *state);\n", name, name)
+	fmt.Fprintf(&header, "%s %s_process(%s_state *state, %s x);\n", sampleType, name, name, sampleType)
+	fmt.Fprintf(&header, "void %s_process_block(%s_state *state, const %s *in, %s *out, int length);\n", name, name, sampleType, sampleType)
+	fmt.Fprintf(&header, "int %s_self_test(void);\n\n", name)
+	fmt.Fprintf(&header, "extern const %s %s_test_input[%s_TEST_LENGTH];\n", sampleType, name, strings.ToUpper(name))
+	fmt.Fprintf(&header, "extern const %s %s_test_output[%s_TEST_LENGTH];\n\n", sampleType, name, strings.ToUpper(name))
+	fmt.Fprintf(&header, "#endif\n")
+
+	source := strings.Builder{}
+	fmt.Fprintf(&source, "#include \"%s.h\"\n#include <string.h>\n\n", name)
+
+	if (precision == Fixed) {
+
+		coefficientType := integerType(datapath.Coefficient.bits())
+		values := []string{}
+
+		for _, section := range newFixedPoint(sections, datapath).Sections {
+
+			for _, value := range []int64{ section[0][0], section[0][1], section[0][2], section[1][1], section[1][2] } {
+
+				values = append(values, fmt.Sprintf("%d", value))
+
+			}
+
+		}
+
+		writeTable(&source, fmt.Sprintf("static const %s coefficients[%s_SECTIONS][5]", coefficientType, strings.ToUpper(name)), values, 5, true)
+		fmt.Fprintf(&source, "static int64_t round_shift(int64_t value, int shift) {\n%s}\n\n", roundingC(datapath.Rounding))
+		fmt.Fprintf(&source, "static int64_t constrain(int64_t value, int width) {\n")
+		fmt.Fprintf(&source, "    int64_t maximum = ((int64_t)1 << (width - 1)) - 1;\n")
+		fmt.Fprintf(&source, "    int64_t minimum = -((int64_t)1 << (width - 1));\n")
+
+		if (datapath.Overflow == Wrap) {
+
+			fmt.Fprintf(&source, "    if ((value > maximum) || (value < minimum)) {\n")
+			fmt.Fprintf(&source, "        return (int64_t)((uint64_t)value << (64 - width)) >> (64 - width);\n    }\n")
+
+		} else {
+
+			fmt.Fprintf(&source, "    if (value > maximum) {\n        return maximum;\n    }\n")
+			fmt.Fprintf(&source, "    if (value < minimum) {\n        return minimum;\n    }\n")
+
+		}
+
+		fmt.Fprintf(&source, "    return value;\n}\n\n")
+		product := "(int64_t)%s * %s"
+
+		if datapath.RoundProducts {
+
+			product = fmt.Sprintf("(round_shift((int64_t)%%s * %%s, %d) << %d)", datapath.Coefficient.Fraction, datapath.Coefficient.Fraction)
+
+		}
+
+		fmt.Fprintf(&source, "%s %s_process(%s_state *state, %s x) {\n", sampleType, name, name, sampleType)
+		fmt.Fprintf(&source, "    int64_t sample = x;\n")
+		fmt.Fprintf(&source, "    for (int k = 0; k < %s_SECTIONS; k++) {\n", strings.ToUpper(name))
+		fmt.Fprintf(&source, "        const %s *c = coefficients[k];\n", coefficientType)
+		fmt.Fprintf(&source, "        int64_t acc = constrain(%s, %d);\n", fmt.Sprintf(product, "c[0]", "sample"), datapath.Accumulator)
+		fmt.Fprintf(&source, "        acc = constrain(acc + %s, %d);\n", fmt.Sprintf(product, "c[1]", "state->x[k][0]"), datapath.Accumulator)
+		fmt.Fprintf(&source, "        acc = constrain(acc + %s, %d);\n", fmt.Sprintf(product, "c[2]", "state->x[k][1]"), datapath.Accumulator)
+		fmt.Fprintf(&source, "        acc = constrain(acc - %s, %d);\n", fmt.Sprintf(product, "c[3]", "state->y[k][0]"), datapath.Accumulator)
+		fmt.Fprintf(&source, "        acc = constrain(acc - %s, %d);\n", fmt.Sprintf(product, "c[4]", "state->y[k][1]"), datapath.Accumulator)
+		fmt.Fprintf(&source, "        int64_t y = constrain(round_shift(acc, %d), %d);\n", datapath.Coefficient.Fraction, datapath.Data.bits())
+		fmt.Fprintf(&source, "        state->x[k][1] = state->x[k][0];\n        state->x[k][0] = (%s)sample;\n", sampleType)
+		fmt.Fprintf(&source, "        state->y[k][1] = state->y[k][0];\n        state->y[k][0] = (%s)y;\n", sampleType)
+		fmt.Fprintf(&source, "        sample = y;\n    }\n    return (%s)sample;\n}\n\n", sampleType)
+
+	} else if (structure == SecondOrderSections) {
+
+		values := []string{}
+
+		for _, section := range sections {
+
+			for _, value := range []float64{ section.Numerator[0], section.Numerator[1], section.Numerator[2], section.Denominator[1], section.Denominator[2] } {
+
+				values = append(values, literal(value, precision))
+
+			}
+
+		}
+
+		writeTable(&source, fmt.Sprintf("static const %s coefficients[%s_SECTIONS][5]", sampleType, strings.ToUpper(name)), values, 5, true)
+		fmt.Fprintf(&source, "%s %s_process(%s_state *state, %s x) {\n", sampleType, name, name, sampleType)
+		fmt.Fprintf(&source, "    for (int k = 0; k < %s_SECTIONS; k++) {\n", strings.ToUpper(name))
+		fmt.Fprintf(&source, "        const %s *c = coefficients[k];\n", sampleType)
+		fmt.Fprintf(&source, "        %s y = c[0] * x + state->z[k][0];\n", sampleType)
+		fmt.Fprintf(&source, "        state->z[k][0] = c[1] * x - c[3] * y + state->z[k][1];\n")
+		fmt.Fprintf(&source, "        state->z[k][1] = c[2] * x - c[4] * y;\n")
+		fmt.Fprintf(&source, "        x = y;\n    }\n    return x;\n}\n\n")
+
+	} else {
+
+		top := []string{}
+		bottom := []string{}
+
+		for index := range numerator {
+
+			top = append(top, literal(numerator[index], precision))
+			bottom = append(bottom, literal(denominator[index], precision))
+
+		}
+
+		writeTable(&source, fmt.Sprintf("static const %s numerator[%s_ORDER + 1]", sampleType, strings.ToUpper(name)), top, 4)
+		writeTable(&source, fmt.Sprintf("static const %s denominator[%s_ORDER + 1]", sampleType, strings.ToUpper(name)), bottom, 4)
+		fmt.Fprintf(&source, "%s %s_process(%s_state *state, %s x) {\n", sampleType, name, name, sampleType)
+
+		switch structure {
+
+			case DirectFormI:
+
+				fmt.Fprintf(&source, "    %s y = numerator[0] * x;\n", sampleType)
+				fmt.Fprintf(&source, "    if (%s_ORDER == 0) {\n        return y;\n    }\n", strings.ToUpper(name))
+				fmt.Fprintf(&source, "    for (int k = 0; k < %s_ORDER; k++) {\n", strings.ToUpper(name))
+				fmt.Fprintf(&source, "        y += numerator[k + 1] * state->x[k] - denominator[k + 1] * state->y[k];\n    }\n")
+				fmt.Fprintf(&source, "    for (int k = %s_ORDER - 1; k > 0; k--) {\n", strings.ToUpper(name))
+				fmt.Fprintf(&source, "        state->x[k] = state->x[k - 1];\n        state->y[k] = state->y[k - 1];\n    }\n")
+				fmt.Fprintf(&source, "    state->x[0] = x;\n    state->y[0] = y;\n    return y;\n}\n\n")
+
+			case DirectFormII:
+
+				fmt.Fprintf(&source, "    %s w = x;\n", sampleType)
+				fmt.Fprintf(&source, "    for (int k = 0; k < %s_ORDER; k++) {\n", strings.ToUpper(name))
+				fmt.Fprintf(&source, "        w -= denominator[k + 1] * state->w[k];\n    }\n")
+				fmt.Fprintf(&source, "    %s y = numerator[0] * w;\n", sampleType)
+				fmt.Fprintf(&source, "    if (%s_ORDER == 0) {\n        return y;\n    }\n", strings.ToUpper(name))
+				fmt.Fprintf(&source, "    for (int k = 0; k < %s_ORDER; k++) {\n", strings.ToUpper(name))
+				fmt.Fprintf(&source, "        y += numerator[k + 1] * state->w[k];\n    }\n")
+				fmt.Fprintf(&source, "    for (int k = %s_ORDER - 1; k > 0; k--) {\n", strings.ToUpper(name))
+				fmt.Fprintf(&source, "        state->w[k] = state->w[k - 1];\n    }\n")
+				fmt.Fprintf(&source, "    state->w[0] = w;\n    return y;\n}\n\n")
+
+			default:
+
+				fmt.Fprintf(&source, "    %s y = numerator[0] * x;\n", sampleType)
+				fmt.Fprintf(&source, "    if (%s_ORDER == 0) {\n        return y;\n    }\n", strings.ToUpper(name))
+				fmt.Fprintf(&source, "    y += state->z[0];\n")
+				fmt.Fprintf(&source, "    for (int k = 0; k < %s_ORDER; k++) {\n", strings.ToUpper(name))
+				fmt.Fprintf(&source, "        %s next = (k + 1 < %s_ORDER) ? state->z[k + 1] : 0;\n", sampleType, strings.ToUpper(name))
+				fmt.Fprintf(&source, "        state->z[k] = numerator[k + 1] * x - denominator[k + 1] * y + next;\n")
+				fmt.Fprintf(&source, "    }\n    return y;\n}\n\n")
+
+		}
+
+	}
+
+	fmt.Fprintf(&source, "void %s_init(%s_state *state) {\n    memset(state, 0, sizeof(*state));\n}\n\n", name, name)
+	fmt.Fprintf(&source, "void %s_reset(%s_state *state) {\n    memset(state, 0, sizeof(*state));\n}\n\n", name, name)
+	fmt.Fprintf(&source, "void %s_process_block(%s_state *state, const %s *in, %s *out, int length) {\n", name, name, sampleType, sampleType)
+	fmt.Fprintf(&source, "    for (int n = 0; n < length; n++) {\n        out[n] = %s_process(state, in[n]);\n    }\n}\n\n", name)
+
+	input := []string{}
+	output := []string{}
+
+	for index := range stimulus {
+
+		if (precision == Fixed) {
+
+			input = append(input, fmt.Sprintf("%d", int64(stimulus[index])))
+			output = append(output, fmt.Sprintf("%d", int64(expected[index])))
+
+		} else {
+
+			input = append(input, literal(stimulus[index], precision))
+			output = append(output, literal(expected[index], precision))
+
+		}
+
+	}
+
+	writeTable(&source, fmt.Sprintf("const %s %s_test_input[%s_TEST_LENGTH]", sampleType, name, strings.ToUpper(name)), input, 4)
+	writeTable(&source, fmt.Sprintf("const %s %s_test_output[%s_TEST_LENGTH]", sampleType, name, strings.ToUpper(name)), output, 4)
+
+	tolerance := "0"
+
+	if (precision == Single) {
+
+		tolerance = "1e-4"
+
+	} else if (precision == Double) {
+
+		tolerance = "1e-9"
+
+	}
+
+	fmt.Fprintf(&source, "int %s_self_test(void) {\n    %s_state state;\n    %s_init(&state);\n", name, name, name)
+	fmt.Fprintf(&source, "    for (int n = 0; n < %s_TEST_LENGTH; n++) {\n", strings.ToUpper(name))
+	fmt.Fprintf(&source, "        double error = (double)%s_process(&state, %s_test_input[n]) - (double)%s_test_output[n];\n", name, name, name)
+	fmt.Fprintf(&source, "        if ((error > %s) || (error < -%s)) {\n            return n + 1;\n        }\n    }\n    return 0;\n}\n", tolerance, tolerance)
+
+	return header.String(), source.String()
+
+}
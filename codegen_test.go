@@ -0,0 +1,122 @@
+package main
+
+import ( "fmt"
+		 "os"
+		 "os/exec"
+		 "path/filepath"
+		 "strings"
+		 "testing" )
+
+
+func TestIdentifier(t *testing.T) {
+
+	for name, want := range map[string]string{ "Low Pass": "low_pass", "band-stop 2": "band_stop_2", "2nd order": "nd_order", "!!!": "filter" } {
+
+		if got := identifier(name); (got != want) {
+
+			t.Errorf("identifier(%q) = %q, want %q", name, got, want)
+
+		}
+
+	}
+
+}
+
+func TestParseCodegen(t *testing.T) {
+
+	name, precision, structure, datapath, samples := parseCodegen(Codegen{})
+
+	if ((name != "filter") || (precision != Double) || (structure != SecondOrderSections) || (samples != 256)) {
+
+		t.Errorf("defaults are %q, %s, %s and %d samples", name, precision, structure, samples)
+
+	}
+
+	if ((datapath.Data != Format{ Integer: 0, Fraction: 15 }) || (datapath.Coefficient != Format{ Integer: 1, Fraction: 14 })) {
+
+		t.Errorf("default datapath is %+v", datapath)
+
+	}
+
+	// the fixed-point datapath is only generated as biquads
+	if _, _, structure, _, _ := parseCodegen(Codegen{ Precision: Fixed, Structure: DirectFormI }); (structure != SecondOrderSections) {
+
+		t.Errorf("fixed point structure is %s, want %s", structure, SecondOrderSections)
+
+	}
+
+}
+
+func TestGenerateC(t *testing.T) {
+
+	filter := digitalPolynomial(equalizerNumerator, equalizerDenominator)
+	compiler, err := exec.LookPath("cc")
+
+	tests := []struct {
+
+		config Codegen
+		state  string
+
+	}{
+
+		{ Codegen{ Name: "sos", Precision: Double }, "double z[SOS_SECTIONS][2]" },
+		{ Codegen{ Name: "single", Precision: Single }, "float z[SINGLE_SECTIONS][2]" },
+		{ Codegen{ Name: "direct one", Structure: DirectFormI }, "double y[DIRECT_ONE_ORDER > 0 ? DIRECT_ONE_ORDER : 1]" },
+		{ Codegen{ Name: "direct two", Precision: Single, Structure: DirectFormII }, "float w[DIRECT_TWO_ORDER > 0 ? DIRECT_TWO_ORDER : 1]" },
+		{ Codegen{ Name: "transposed", Structure: TransposedDirectFormII }, "double z[TRANSPOSED_ORDER > 0 ? TRANSPOSED_ORDER : 1]" },
+		{ Codegen{ Name: "folded", Structure: SymmetricFolded }, "double x[FOLDED_ORDER > 0 ? FOLDED_ORDER : 1]" },
+		{ Codegen{ Name: "fixed", Precision: Fixed, Samples: 64 }, "#define FIXED_TEST_LENGTH 64" },
+		{ Codegen{ Name: "wrapped", Precision: Fixed, Datapath: Datapath{ Overflow: Wrap, Rounding: Convergent, RoundProducts: true, Accumulator: 32 } }, "WRAPPED_SECTIONS" },
+
+	}
+
+	for _, test := range tests {
+
+		header, source := generateC(filter, test.config)
+		name := identifier(test.config.Name)
+
+		if (!strings.Contains(header, test.state)) {
+
+			t.Errorf("%s: header does not declare %q", name, test.state)
+
+		}
+
+		for _, function := range []string{ "_init(", "_reset(", "_process(", "_process_block(", "_self_test(" } {
+
+			if (!strings.Contains(source, name + function)) {
+
+				t.Errorf("%s: source does not define %s%s", name, name, function)
+
+			}
+
+		}
+
+		if (err != nil) {
+
+			continue
+
+		}
+
+		// the generated self test replays the go reference output through the c implementation
+		directory := t.TempDir()
+		os.WriteFile(filepath.Join(directory, name + ".h"), []byte(header), 0644)
+		os.WriteFile(filepath.Join(directory, name + ".c"), []byte(source), 0644)
+		os.WriteFile(filepath.Join(directory, "main.c"), []byte(fmt.Sprintf("#include \"%s.h\"\nint main(void) { return %s_self_test(); }\n", name, name)), 0644)
+		binary := filepath.Join(directory, "main")
+
+		if output, err := exec.Command(compiler, "-std=c99", "-Wall", "-Werror", "-o", binary, filepath.Join(directory, "main.c"), filepath.Join(directory, name + ".c")).CombinedOutput(); (err != nil) {
+
+			t.Errorf("%s: compilation failed: %v\n%s", name, err, output)
+			continue
+
+		}
+
+		if output, err := exec.Command(binary).CombinedOutput(); (err != nil) {
+
+			t.Errorf("%s: self test failed: %v\n%s", name, err, output)
+
+		}
+
+	}
+
+}
@@ -0,0 +1,153 @@
+package main
+
+import ( "math" )
+
+
+func cookbookAlpha(config Cookbook, omega float64, amplitude float64) float64 {
+
+	if ((config.Bandwidth != nil) &&
+		(*config.Bandwidth > 0.0)) {
+
+		octaves := *config.Bandwidth
+		return math.Sin(omega)*math.Sinh(math.Ln2 / 2.0*octaves*omega / math.Sin(omega))
+
+	}
+
+	if ((config.Slope != nil) &&
+		(*config.Slope > 0.0)) {
+
+		slope := *config.Slope
+		return math.Sin(omega) / 2.0*math.Sqrt((amplitude + 1.0 / amplitude)*(1.0 / slope - 1.0) + 2.0)
+
+	}
+
+	quality := math.Sqrt(0.5)
+
+	if ((config.QualityFactor != nil) &&
+		(*config.QualityFactor > 0.0)) {
+
+		quality = *config.QualityFactor
+
+	}
+
+	return math.Sin(omega) / (2.0*quality)
+
+}
+
+func cookbookSection(config Cookbook) Section {
+
+	section := Section{
+
+		Numerator: [3]float64{ 1.0, 0.0, 0.0 },
+		Denominator: [3]float64{ 1.0, 0.0, 0.0 },
+
+	}
+
+	if (!config.Shape.exists() ||
+		(config.SamplingFrequency <= 0.0) ||
+		(config.Frequency <= 0.0)) {
+
+		return section
+
+	}
+
+	gain := 0.0
+
+	if (config.Gain != nil) {
+
+		gain = *config.Gain
+
+	}
+
+	amplitude := math.Pow(10, gain / 40.0)
+	omega := 2.0*math.Pi*config.Frequency / config.SamplingFrequency
+	cosine := math.Cos(omega)
+	alpha := cookbookAlpha(config, omega, amplitude)
+	root := 2.0*math.Sqrt(amplitude)*alpha
+
+	switch config.Shape {
+
+		case LowShelf:
+
+			section.Numerator = [3]float64{ amplitude*((amplitude + 1.0) - (amplitude - 1.0)*cosine + root),
+											2.0*amplitude*((amplitude - 1.0) - (amplitude + 1.0)*cosine),
+											amplitude*((amplitude + 1.0) - (amplitude - 1.0)*cosine - root) }
+			section.Denominator = [3]float64{ (amplitude + 1.0) + (amplitude - 1.0)*cosine + root,
+											  -2.0*((amplitude - 1.0) + (amplitude + 1.0)*cosine),
+											  (amplitude + 1.0) + (amplitude - 1.0)*cosine - root }
+
+		case HighShelf:
+
+			section.Numerator = [3]float64{ amplitude*((amplitude + 1.0) + (amplitude - 1.0)*cosine + root),
+											-2.0*amplitude*((amplitude - 1.0) + (amplitude + 1.0)*cosine),
+											amplitude*((amplitude + 1.0) + (amplitude - 1.0)*cosine - root) }
+			section.Denominator = [3]float64{ (amplitude + 1.0) - (amplitude - 1.0)*cosine + root,
+											  2.0*((amplitude - 1.0) - (amplitude + 1.0)*cosine),
+											  (amplitude + 1.0) - (amplitude - 1.0)*cosine - root }
+
+		case PeakingEQ:
+
+			section.Numerator = [3]float64{ 1.0 + alpha*amplitude, -2.0*cosine, 1.0 - alpha*amplitude }
+			section.Denominator = [3]float64{ 1.0 + alpha / amplitude, -2.0*cosine, 1.0 - alpha / amplitude }
+
+		case BandPassConstantSkirt:
+
+			section.Numerator = [3]float64{ math.Sin(omega) / 2.0, 0.0, -math.Sin(omega) / 2.0 }
+			section.Denominator = [3]float64{ 1.0 + alpha, -2.0*cosine, 1.0 - alpha }
+
+		case BandPassConstantPeak:
+
+			section.Numerator = [3]float64{ alpha, 0.0, -alpha }
+			section.Denominator = [3]float64{ 1.0 + alpha, -2.0*cosine, 1.0 - alpha }
+
+		case AllPass:
+
+			section.Numerator = [3]float64{ 1.0 - alpha, -2.0*cosine, 1.0 + alpha }
+			section.Denominator = [3]float64{ 1.0 + alpha, -2.0*cosine, 1.0 - alpha }
+
+		case FirstOrderLowShelf, FirstOrderHighShelf:
+
+			k := math.Tan(omega / 2.0)
+			g := math.Pow(amplitude, 2)
+			root = math.Sqrt(g)
+
+			if (config.Shape == FirstOrderLowShelf) {
+
+				section.Numerator = [3]float64{ 1.0 + k*root, k*root - 1.0, 0.0 }
+				section.Denominator = [3]float64{ 1.0 + k / root, k / root - 1.0, 0.0 }
+
+			} else {
+
+				section.Numerator = [3]float64{ root + k, k - root, 0.0 }
+				section.Denominator = [3]float64{ 1.0 / root + k, k - 1.0 / root, 0.0 }
+
+			}
+
+	}
+
+	scale := section.Denominator[0]
+
+	for index := range section.Numerator {
+
+		section.Numerator[index] /= scale
+		section.Denominator[index] /= scale
+
+	}
+
+	return section
+
+}
+
+func cookbookPolynomial(config ...Cookbook) Polynomial {
+
+	sections := []Section{}
+
+	for _, biquad := range config {
+
+		sections = append(sections, cookbookSection(biquad))
+
+	}
+
+	return cascadePolynomial(sections)
+
+}
@@ -0,0 +1,111 @@
+package main
+
+import ( "math"
+		 "testing" )
+
+
+func TestCookbookGains(t *testing.T) {
+
+	const samplingFrequency = 48000.0
+
+	// expected gains in dB at DC, the corner or center frequency and Nyquist
+	cases := []struct {
+
+		shape Biquad
+		gains [3]float64
+
+	}{
+
+		{ LowShelf, [3]float64{ 6.0, 3.0, 0.0 } },
+		{ HighShelf, [3]float64{ 0.0, 3.0, 6.0 } },
+		{ PeakingEQ, [3]float64{ 0.0, 6.0, 0.0 } },
+		{ BandPassConstantPeak, [3]float64{ math.Inf(-1), 0.0, math.Inf(-1) } },
+		{ BandPassConstantSkirt, [3]float64{ math.Inf(-1), decibels(2.0), math.Inf(-1) } },
+		{ AllPass, [3]float64{ 0.0, 0.0, 0.0 } },
+		{ FirstOrderLowShelf, [3]float64{ 6.0, 3.0, 0.0 } },
+		{ FirstOrderHighShelf, [3]float64{ 0.0, 3.0, 6.0 } },
+
+	}
+
+	for _, c := range cases {
+
+		filter := cookbookPolynomial(Cookbook{ Shape: c.shape, Frequency: 1000.0, SamplingFrequency: samplingFrequency, Gain: pointer(6.0), QualityFactor: pointer(2.0) })
+
+		for index, frequency := range []float64{ 0.0, 1000.0, samplingFrequency / 2.0 } {
+
+			got := decibels(magnitudeResponse(filter, frequency, samplingFrequency))
+			want := c.gains[index]
+
+			if ((math.IsInf(want, -1) && (got > -200.0)) || (!math.IsInf(want, -1) && (math.Abs(got - want) > 1e-9))) {
+
+				t.Errorf("%s: gain at %g Hz = %g dB, want %g dB", c.shape, frequency, got, want)
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestCookbookAllPassPhase(t *testing.T) {
+
+	filter := cookbookPolynomial(Cookbook{ Shape: AllPass, Frequency: 1000.0, SamplingFrequency: 48000.0, QualityFactor: pointer(0.7) })
+
+	if phase := phaseResponse(filter, 1000.0, 48000.0); (math.Abs(math.Abs(phase) - math.Pi) > 1e-9) {
+
+		t.Errorf("phase at the center = %g rad, want ±π", phase)
+
+	}
+
+}
+
+func TestCookbookBandwidth(t *testing.T) {
+
+	// a one octave cut reaches half its depth an octave apart, measured on the prewarped axis
+	filter := cookbookPolynomial(Cookbook{ Shape: PeakingEQ, Frequency: 1000.0, SamplingFrequency: 48000.0, Gain: pointer(-12.0), Bandwidth: pointer(1.0) })
+
+	if got := decibels(magnitudeResponse(filter, 1000.0, 48000.0)); (math.Abs(got + 12.0) > 1e-9) {
+
+		t.Errorf("depth = %g dB, want -12 dB", got)
+
+	}
+
+	lower := decibels(magnitudeResponse(filter, 1000.0 / math.Sqrt2, 48000.0))
+	upper := decibels(magnitudeResponse(filter, 1000.0*math.Sqrt2, 48000.0))
+
+	if ((math.Abs(lower + 6.0) > 0.1) || (math.Abs(upper + 6.0) > 0.1)) {
+
+		t.Errorf("band edges at %g dB and %g dB, want -6 dB", lower, upper)
+
+	}
+
+}
+
+func TestCookbookShelfSlope(t *testing.T) {
+
+	filter := cookbookPolynomial(Cookbook{ Shape: LowShelf, Frequency: 1000.0, SamplingFrequency: 48000.0, Gain: pointer(12.0), Slope: pointer(1.0) })
+
+	if got := decibels(magnitudeResponse(filter, 1000.0, 48000.0)); (math.Abs(got - 6.0) > 1e-9) {
+
+		t.Errorf("midpoint = %g dB, want 6 dB", got)
+
+	}
+
+}
+
+func TestCookbookInvalid(t *testing.T) {
+
+	for _, config := range []Cookbook{ { Shape: "tilt", Frequency: 1000.0, SamplingFrequency: 48000.0 }, { Shape: PeakingEQ, Frequency: 1000.0 } } {
+
+		section := cookbookSection(config)
+
+		if ((section.Numerator != [3]float64{ 1.0, 0.0, 0.0 }) || (section.Denominator != [3]float64{ 1.0, 0.0, 0.0 })) {
+
+			t.Errorf("%+v: got %+v, want a unity section", config, section)
+
+		}
+
+	}
+
+}
@@ -0,0 +1,375 @@
+package main
+
+import ( "fmt"
+		 "math"
+		 "math/cmplx"
+		 "slices" )
+
+
+type Band struct {
+
+	Lower  float64
+	Center float64
+	Upper  float64
+	Filter Polynomial
+	Stages []Polynomial
+
+}
+
+type FilterBank struct {
+
+	Bands			[]Band
+	Complementarity Complementarity
+	Deviation		float64
+
+}
+
+func parseCrossover(config Crossover) (string, string, string, []float64, uint16, float64, float64, float64, error) {
+
+	domain := string(Analogue)
+
+	if config.Domain.exists() {
+
+		domain = string(config.Domain)
+
+	}
+
+	approximation := string(Butterworth)
+
+	if config.Approximation.exists() {
+
+		approximation = string(config.Approximation)
+
+	}
+
+	complementarity := string(Magnitude)
+
+	if (config.Complementarity == Power) {
+
+		complementarity = string(Power)
+
+	}
+
+	var err error
+
+	// a linkwitz-riley crossover is the magnitude complementary pair of squared butterworth sections
+	if ((config.Complementarity == LinkwitzRiley) && (approximation != string(Butterworth))) {
+
+		err = fmt.Errorf("linkwitz-riley crossovers are squared butterworth filters, not %s", approximation)
+
+	}
+
+	samplingFrequency := 0.0
+
+	if (config.SamplingFrequency != nil) {
+
+		samplingFrequency = math.Abs(*config.SamplingFrequency)
+
+	}
+
+	if (samplingFrequency == 0.0) {
+
+		domain = string(Analogue)
+
+	}
+
+	frequencies := []float64{}
+
+	for _, frequency := range config.Frequencies {
+
+		if ((frequency > 0.0) &&
+			((domain == string(Analogue)) || (frequency < samplingFrequency / 2.0))) {
+
+			frequencies = append(frequencies, frequency)
+
+		}
+
+	}
+
+	slices.Sort(frequencies)
+	frequencies = slices.Compact(frequencies)
+	order := config.Order
+
+	if (order == 0) {
+
+		order = 4
+
+	}
+
+	if (complementarity == string(Magnitude)) {
+
+		order = max(order / 2, 1)
+
+	}
+
+	epsilonPass := 1.0
+
+	if (config.PassbandAttenuation != nil) {
+
+		epsilonPass = math.Sqrt(math.Pow(10, math.Abs(*config.PassbandAttenuation) / 10.0) - 1.0)
+
+	}
+
+	epsilonStop := math.Sqrt(math.Pow(10, 4.0) - 1.0)
+
+	if (config.StopbandAttenuation != nil) {
+
+		epsilonStop = math.Sqrt(math.Pow(10, math.Abs(*config.StopbandAttenuation) / 10.0) - 1.0)
+
+	}
+
+	return domain, approximation, complementarity, frequencies, order, epsilonPass, epsilonStop, samplingFrequency, err
+
+}
+
+func designCrossover(config Crossover) (FilterBank, error) {
+
+	domain, approximation,
+	complementarity, frequencies,
+	order, epsilonPass,
+	epsilonStop, samplingFrequency, err := parseCrossover(config)
+
+	bank := FilterBank{ Complementarity: Complementarity(complementarity) }
+
+	if ((err != nil) || (len(frequencies) == 0)) {
+
+		return bank, err
+
+	}
+
+	prototype := analogueLowPassFilterPrototype(approximation, order, epsilonPass, epsilonStop)
+	lowPass := []Polynomial{}
+	highPass := []Polynomial{}
+	allPass := []Polynomial{}
+
+	for _, frequency := range frequencies {
+
+		lp := lowPassToLowPass(prototype, frequency)
+		hp := lowPassToHighPass(prototype, frequency)
+
+		if (complementarity == string(Magnitude)) {
+
+			lp.multiply(lowPassToLowPass(prototype, frequency))
+			hp.multiply(lowPassToHighPass(prototype, frequency))
+
+			if (order%2 != 0) {
+
+				hp.scale(-1.0)
+
+			}
+
+		}
+
+		if (domain == string(Digital)) {
+
+			lp = bilinear(lp, samplingFrequency, frequency)
+			hp = bilinear(hp, samplingFrequency, frequency)
+
+		}
+
+		lowPass = append(lowPass, lp)
+		highPass = append(highPass, hp)
+
+		if (complementarity == string(Magnitude)) {
+
+			ap := lp
+			ap.add(hp)
+			allPass = append(allPass, ap)
+
+		}
+
+	}
+
+	last := len(frequencies) - 1
+
+	for band := 0; band <= len(frequencies); band++ {
+
+		lower := 0.0
+		upper := math.Inf(1)
+		filter := highPass[last]
+
+		if (band <= last) {
+
+			upper = frequencies[band]
+			filter = lowPass[band]
+
+		}
+
+		stages := []Polynomial{ filter }
+
+		if (band > 0) {
+
+			lower = frequencies[band - 1]
+
+		}
+
+		for index := 0; index < min(band, last); index++ {
+
+			filter.multiply(highPass[index])
+			stages = append(stages, highPass[index])
+
+		}
+
+		for index := band + 1; index < len(allPass); index++ {
+
+			filter.multiply(allPass[index])
+			stages = append(stages, allPass[index])
+
+		}
+
+		center := math.Sqrt(lower*upper)
+
+		if (band == 0) {
+
+			center = 0.0
+
+		} else if (band == len(frequencies)) {
+
+			center = math.Inf(1)
+
+		}
+
+		bank.Bands = append(bank.Bands, Band{
+
+			Lower: lower,
+			Center: center,
+			Upper: upper,
+			Filter: filter,
+			Stages: stages,
+
+		})
+
+	}
+
+	bank.Deviation = bank.recombination(frequencies, samplingFrequency)
+	return bank, nil
+
+}
+
+func (b *FilterBank) recombination(frequencies []float64, samplingFrequency float64) float64 {
+
+	if (len(b.Bands) == 0) {
+
+		return 0.0
+
+	}
+
+	lower := math.Log10(frequencies[0] / 10.0)
+	upper := math.Log10(frequencies[len(frequencies) - 1]*10.0)
+
+	if (samplingFrequency > 0.0) {
+
+		upper = math.Min(upper, math.Log10(0.499*samplingFrequency))
+
+	}
+
+	deviation := 0.0
+
+	for _, exponent := range linearSpace(lower, upper, 512) {
+
+		frequency := math.Pow(10, exponent)
+		sum := complex(0, 0)
+		power := 0.0
+
+		for _, band := range b.Bands {
+
+			h := complex(1, 0)
+
+			for _, stage := range band.Stages {
+
+				h *= frequencyResponse(stage, frequency, samplingFrequency)
+
+			}
+
+			sum += h
+			power += math.Pow(cmplx.Abs(h), 2)
+
+		}
+
+		level := decibels(cmplx.Abs(sum))
+
+		if (b.Complementarity == Power) {
+
+			level = 10.0*math.Log10(power)
+
+		}
+
+		deviation = math.Max(deviation, math.Abs(level))
+
+	}
+
+	return deviation
+
+}
+
+func octaveBands(lowerFrequency float64, upperFrequency float64, fraction uint16, reference ...float64) []float64 {
+
+	centre := 1000.0
+
+	if (len(reference) > 0) {
+
+		centre = reference[0]
+
+	}
+
+	if (fraction == 0) {
+
+		fraction = 1
+
+	}
+
+	b := float64(fraction)
+	first := math.Ceil(b*math.Log2(lowerFrequency / centre))
+	last := math.Floor(b*math.Log2(upperFrequency / centre))
+	centres := []float64{}
+
+	for index := first; index <= last; index++ {
+
+		centres = append(centres, centre*math.Pow(2, index / b))
+
+	}
+
+	return centres
+
+}
+
+func designFilterBank(config Crossover, lowerFrequency float64, upperFrequency float64, fraction uint16) (FilterBank, error) {
+
+	if (fraction == 0) {
+
+		fraction = 1
+
+	}
+
+	centres := octaveBands(lowerFrequency, upperFrequency, fraction)
+	edges := []float64{}
+	half := math.Pow(2, 1.0 / (2.0*float64(fraction)))
+
+	for _, centre := range centres {
+
+		edges = append(edges, centre / half)
+
+	}
+
+	if (len(centres) > 0) {
+
+		edges = append(edges, centres[len(centres) - 1]*half)
+
+	}
+
+	config.Frequencies = edges
+	bank, err := designCrossover(config)
+
+	for index := range bank.Bands {
+
+		if ((index > 0) && (index <= len(centres))) {
+
+			bank.Bands[index].Center = centres[index - 1]
+
+		}
+
+	}
+
+	return bank, err
+
+}
@@ -0,0 +1,186 @@
+package main
+
+import ( "math"
+		 "math/cmplx"
+		 "testing" )
+
+
+func bankSum(bank FilterBank, frequency float64, samplingFrequency ...float64) complex128 {
+
+	// the stages are evaluated one by one, expanding them into a single polynomial costs precision at low frequencies
+	sum := complex(0, 0)
+
+	for _, band := range bank.Bands {
+
+		h := complex(1, 0)
+
+		for _, stage := range band.Stages {
+
+			h *= frequencyResponse(stage, frequency, samplingFrequency...)
+
+		}
+
+		sum += h
+
+	}
+
+	return sum
+
+}
+
+func TestLinkwitzRileyCrossover(t *testing.T) {
+
+	for _, order := range []uint16{ 2, 4, 6 } {
+
+		bank, err := designCrossover(Crossover{ Complementarity: LinkwitzRiley, Frequencies: []float64{ 500.0 }, Order: order })
+
+		if (err != nil) {
+
+			t.Fatal(err)
+
+		}
+
+		if (len(bank.Bands) != 2) {
+
+			t.Fatalf("order %d: got %d bands, want 2", order, len(bank.Bands))
+
+		}
+
+		for _, band := range bank.Bands {
+
+			if got := decibels(magnitudeResponse(band.Filter, 500.0)); (math.Abs(got - decibels(0.5)) > 1e-9) {
+
+				t.Errorf("order %d: band %g-%g Hz is %g dB at the crossover, want -6.02 dB", order, band.Lower, band.Upper, got)
+
+			}
+
+		}
+
+		for _, frequency := range []float64{ 10.0, 250.0, 500.0, 1000.0, 1e5 } {
+
+			if got := cmplx.Abs(bankSum(bank, frequency)); (math.Abs(got - 1.0) > 1e-9) {
+
+				t.Errorf("order %d: |sum| at %g Hz = %.12g, want 1", order, frequency, got)
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestDigitalThreeWayCrossover(t *testing.T) {
+
+	bank, err := designCrossover(Crossover{ Domain: Digital, SamplingFrequency: pointer(48000.0), Frequencies: []float64{ 8000.0, 100.0, 1000.0 }, Order: 4 })
+
+	if (err != nil) {
+
+		t.Fatal(err)
+
+	}
+
+	if (len(bank.Bands) != 4) {
+
+		t.Fatalf("got %d bands, want 4", len(bank.Bands))
+
+	}
+
+	if ((bank.Bands[1].Lower != 100.0) || (bank.Bands[1].Upper != 1000.0) || (math.Abs(bank.Bands[1].Center - math.Sqrt(1e5)) > 1e-9)) {
+
+		t.Errorf("second band is %g-%g Hz around %g Hz, want 100-1000 Hz around 316.2 Hz", bank.Bands[1].Lower, bank.Bands[1].Upper, bank.Bands[1].Center)
+
+	}
+
+	if (bank.Deviation > 1e-6) {
+
+		t.Errorf("recombined response deviates by %g dB", bank.Deviation)
+
+	}
+
+	for _, frequency := range []float64{ 30.0, 100.0, 1000.0, 8000.0, 20000.0 } {
+
+		if got := cmplx.Abs(bankSum(bank, frequency, 48000.0)); (math.Abs(got - 1.0) > 1e-6) {
+
+			t.Errorf("|sum| at %g Hz = %.9g, want 1", frequency, got)
+
+		}
+
+	}
+
+}
+
+func TestPowerComplementaryCrossover(t *testing.T) {
+
+	bank, err := designCrossover(Crossover{ Complementarity: Power, Frequencies: []float64{ 2000.0 }, Order: 3 })
+
+	if (err != nil) {
+
+		t.Fatal(err)
+
+	}
+
+	for _, frequency := range []float64{ 100.0, 2000.0, 5000.0 } {
+
+		power := 0.0
+
+		for _, band := range bank.Bands {
+
+			power += math.Pow(magnitudeResponse(band.Filter, frequency), 2)
+
+		}
+
+		if (math.Abs(power - 1.0) > 1e-9) {
+
+			t.Errorf("power at %g Hz = %.12g, want 1", frequency, power)
+
+		}
+
+	}
+
+	if (bank.Deviation > 1e-9) {
+
+		t.Errorf("recombined power deviates by %g dB", bank.Deviation)
+
+	}
+
+}
+
+func TestLinkwitzRileyRequiresButterworth(t *testing.T) {
+
+	_, err := designCrossover(Crossover{ Complementarity: LinkwitzRiley, Approximation: Chebyshev, Frequencies: []float64{ 500.0 } })
+
+	if ((err == nil) || (err.Error() != "linkwitz-riley crossovers are squared butterworth filters, not chebyshev")) {
+
+		t.Errorf("got %v, want a butterworth error", err)
+
+	}
+
+}
+
+func TestOctaveBands(t *testing.T) {
+
+	compareSignals(t, "octaves", octaveBands(100.0, 10000.0, 1), []float64{ 125.0, 250.0, 500.0, 1000.0, 2000.0, 4000.0, 8000.0 }, 1e-9)
+	compareSignals(t, "third octaves", octaveBands(790.0, 1300.0, 3), []float64{ 1000.0 / math.Cbrt(2.0), 1000.0, 1000.0*math.Cbrt(2.0) }, 1e-9)
+
+	bank, err := designFilterBank(Crossover{ Domain: Digital, SamplingFrequency: pointer(48000.0), Order: 4 }, 100.0, 10000.0, 1)
+
+	if (err != nil) {
+
+		t.Fatal(err)
+
+	}
+
+	if (len(bank.Bands) != 9) {
+
+		t.Fatalf("got %d bands, want 9", len(bank.Bands))
+
+	}
+
+	if ((bank.Bands[1].Center != 125.0) || (math.Abs(bank.Bands[1].Lower*bank.Bands[1].Upper - 125.0*125.0) > 1e-6)) {
+
+		t.Errorf("first octave is %g-%g Hz around %g Hz, want it centred on 125 Hz", bank.Bands[1].Lower, bank.Bands[1].Upper, bank.Bands[1].Center)
+
+	}
+
+}
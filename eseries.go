@@ -0,0 +1,409 @@
+package main
+
+import ( "math"
+		 "math/cmplx"
+		 "slices" )
+
+
+type Snap struct {
+
+	Ideal	 float64
+	Value	 float64
+	Parts	 []float64
+	Parallel bool
+
+}
+
+type Deviation struct {
+
+	Cutoff float64
+	Q	   float64
+	Ripple float64
+
+}
+
+func seriesValues(series Series) []float64 {
+
+	switch series {
+
+		case E6, E12, E24:
+
+			step := map[Series]int{ E6: 4, E12: 2, E24: 1 }[series]
+			values := []float64{}
+
+			for index := 0; index < len(e24); index += step {
+
+				values = append(values, e24[index])
+
+			}
+
+			return values
+
+		case E48, E96, E192:
+
+			count := map[Series]int{ E48: 48, E96: 96, E192: 192 }[series]
+			values := make([]float64, count)
+
+			for index := range values {
+
+				values[index] = math.Round(100.0*math.Pow(10, float64(index) / float64(count))) / 100.0
+
+			}
+
+			if (series == E192) {
+
+				values[185] = 9.20
+
+			}
+
+			return values
+
+	}
+
+	return seriesValues(E24)
+
+}
+
+func standardValues(value float64, series Series) []float64 {
+
+	mantissas := seriesValues(series)
+	decade := math.Floor(math.Log10(value))
+	values := []float64{}
+
+	for exponent := decade - 1.0; exponent <= decade + 1.0; exponent++ {
+
+		for _, mantissa := range mantissas {
+
+			values = append(values, mantissa*math.Pow(10, exponent))
+
+		}
+
+	}
+
+	return values
+
+}
+
+func nearestValue(value float64, series Series) float64 {
+
+	nearest := 0.0
+
+	for _, candidate := range standardValues(value, series) {
+
+		if ((nearest == 0.0) || (math.Abs(math.Log(candidate / value)) < math.Abs(math.Log(nearest / value)))) {
+
+			nearest = candidate
+
+		}
+
+	}
+
+	return nearest
+
+}
+
+func combinedValue(p float64, q float64, parallel bool, capacitor bool) float64 {
+
+	if (parallel != capacitor) {
+
+		return p*q / (p + q)
+
+	}
+
+	return p + q
+
+}
+
+func snapValue(value float64, series Series, capacitor bool, combine ...bool) Snap {
+
+	if (value <= 0.0) {
+
+		return Snap{ Ideal: value, Value: value, Parts: []float64{} }
+
+	}
+
+	nearest := nearestValue(value, series)
+	snap := Snap{ Ideal: value, Value: nearest, Parts: []float64{ nearest } }
+
+	if ((len(combine) == 0) || !combine[0]) {
+
+		return snap
+
+	}
+
+	mismatch := math.Abs(nearest - value)
+
+	for _, first := range standardValues(value, series) {
+
+		for _, parallel := range []bool{ false, true } {
+
+			remainder := value - first
+
+			if (parallel != capacitor) {
+
+				remainder = 1.0 / (1.0 / value - 1.0 / first)
+
+			}
+
+			if ((remainder <= 0.0) || math.IsInf(remainder, 0)) {
+
+				continue
+
+			}
+
+			second := nearestValue(remainder, series)
+			combined := combinedValue(first, second, parallel, capacitor)
+
+			if (math.Abs(combined - value) < 0.5*mismatch) {
+
+				snap = Snap{ Ideal: value, Value: combined, Parts: []float64{ first, second }, Parallel: parallel }
+				mismatch = math.Abs(combined - value)
+
+			}
+
+		}
+
+	}
+
+	return snap
+
+}
+
+func (a ActiveFilter) snap(series Series, combine ...bool) (ActiveFilter, []Snap) {
+
+	snapped := ActiveFilter{ Stages: make([]Stage, len(a.Stages)), GainBandwidth: a.GainBandwidth }
+	snaps := []Snap{}
+
+	for index, stage := range a.Stages {
+
+		stage.Resistors = slices.Clone(stage.Resistors)
+		stage.Capacitors = slices.Clone(stage.Capacitors)
+
+		for component, value := range stage.Resistors {
+
+			snap := snapValue(value, series, false, combine...)
+			stage.Resistors[component] = snap.Value
+			snaps = append(snaps, snap)
+
+		}
+
+		for component, value := range stage.Capacitors {
+
+			snap := snapValue(value, series, true, combine...)
+			stage.Capacitors[component] = snap.Value
+			snaps = append(snaps, snap)
+
+		}
+
+		snapped.Stages[index] = stage
+
+	}
+
+	return snapped, snaps
+
+}
+
+func (l Ladder) snap(series Series, combine ...bool) (Ladder, []Snap) {
+
+	snapped := Ladder{ Elements: slices.Clone(l.Elements), Source: l.Source, Load: l.Load }
+	snaps := []Snap{}
+
+	for index, element := range snapped.Elements {
+
+		if (element.Inductance > 0.0) {
+
+			snap := snapValue(element.Inductance, series, false, combine...)
+			snapped.Elements[index].Inductance = snap.Value
+			snaps = append(snaps, snap)
+
+		}
+
+		if (element.Capacitance > 0.0) {
+
+			snap := snapValue(element.Capacitance, series, true, combine...)
+			snapped.Elements[index].Capacitance = snap.Value
+			snaps = append(snaps, snap)
+
+		}
+
+	}
+
+	return snapped, snaps
+
+}
+
+func (a ActiveFilter) poles() []complex128 {
+
+	poles := []complex128{}
+
+	for _, stage := range a.Stages {
+
+		_, denominator := stage.coefficients()
+		poles = append(poles, polynomialRoots(denominator)...)
+
+	}
+
+	return poles
+
+}
+
+func passbandEdges(config Specs) ([]float64, [][2]float64) {
+
+	cutoffFrequency, centerFrequency, bandwidth := analogueFrequencies(config)
+	lower := -bandwidth / 2.0 + math.Sqrt(bandwidth*bandwidth / 4.0 + centerFrequency*centerFrequency)
+	upper := lower + bandwidth
+
+	switch config.Response {
+
+		case HPF:
+
+			return []float64{ cutoffFrequency }, [][2]float64{ { cutoffFrequency, 100.0*cutoffFrequency } }
+
+		case BPF:
+
+			return []float64{ lower, upper }, [][2]float64{ { lower, upper } }
+
+		case BSF, BRF:
+
+			return []float64{ lower, upper }, [][2]float64{ { lower / 100.0, lower }, { upper, 100.0*upper } }
+
+	}
+
+	return []float64{ cutoffFrequency }, [][2]float64{ { cutoffFrequency / 100.0, cutoffFrequency } }
+
+}
+
+func poleQualities(poles []complex128) []float64 {
+
+	qualities := []float64{}
+
+	for _, pole := range poles {
+
+		if ((imag(pole) > 1e-9*cmplx.Abs(pole)) && (real(pole) < 0.0)) {
+
+			qualities = append(qualities, cmplx.Abs(pole) / (-2.0*real(pole)))
+
+		}
+
+	}
+
+	slices.Sort(qualities)
+	slices.Reverse(qualities)
+	return qualities
+
+}
+
+func measureDeviation(config Specs, ideal func(float64) complex128, realized func(float64) complex128,
+					  idealPoles []complex128, realizedPoles []complex128) Deviation {
+
+	edges, passbands := passbandEdges(config)
+	deviation := Deviation{}
+	ripple := [2][2]float64{ { math.Inf(1), math.Inf(-1) }, { math.Inf(1), math.Inf(-1) } }
+
+	for _, band := range passbands {
+
+		for _, exponent := range linearSpace(math.Log10(band[0]), math.Log10(band[1]), 1001) {
+
+			frequency := math.Pow(10, exponent)
+
+			for index, response := range []func(float64) complex128{ ideal, realized } {
+
+				level := decibels(cmplx.Abs(response(frequency)))
+				ripple[index][0] = math.Min(ripple[index][0], level)
+				ripple[index][1] = math.Max(ripple[index][1], level)
+
+			}
+
+		}
+
+	}
+
+	deviation.Ripple = (ripple[1][1] - ripple[1][0]) - (ripple[0][1] - ripple[0][0])
+
+	for _, edge := range edges {
+
+		target := decibels(cmplx.Abs(ideal(edge))) - ripple[0][1]
+		level := func(frequency float64) float64 {
+
+			return decibels(cmplx.Abs(realized(frequency))) - ripple[1][1] - target
+
+		}
+
+		crossing := edge
+		nearest := math.Inf(1)
+		grid := linearSpace(math.Log10(edge / 2.0), math.Log10(2.0*edge), 401)
+
+		for index := 1; index < len(grid); index++ {
+
+			low := math.Pow(10, grid[index - 1])
+			high := math.Pow(10, grid[index])
+
+			if ((level(low) > 0.0) == (level(high) > 0.0)) {
+
+				continue
+
+			}
+
+			for iteration := 0; iteration < 60; iteration++ {
+
+				middle := math.Sqrt(low*high)
+
+				if ((level(middle) > 0.0) == (level(low) > 0.0)) {
+
+					low = middle
+
+				} else {
+
+					high = middle
+
+				}
+
+			}
+
+			if (math.Abs(math.Log(low / edge)) < nearest) {
+
+				crossing = low
+				nearest = math.Abs(math.Log(low / edge))
+
+			}
+
+		}
+
+		if (math.Abs(crossing / edge - 1.0) > math.Abs(deviation.Cutoff)) {
+
+			deviation.Cutoff = crossing / edge - 1.0
+
+		}
+
+	}
+
+	idealQualities := poleQualities(idealPoles)
+	realizedQualities := poleQualities(realizedPoles)
+
+	for index := 0; index < min(len(idealQualities), len(realizedQualities)); index++ {
+
+		change := realizedQualities[index] / idealQualities[index] - 1.0
+
+		if (math.Abs(change) > math.Abs(deviation.Q)) {
+
+			deviation.Q = change
+
+		}
+
+	}
+
+	return deviation
+
+}
+
+func (a ActiveFilter) deviation(realized ActiveFilter, config Specs) Deviation {
+
+	return measureDeviation(config, a.response, realized.response, a.poles(), realized.poles())
+
+}
+
+func (l Ladder) deviation(realized Ladder, config Specs) Deviation {
+
+	return measureDeviation(config, l.response, realized.response, l.poles(), realized.poles())
+
+}
@@ -0,0 +1,157 @@
+package main
+
+import ( "math"
+		 "slices"
+		 "testing" )
+
+
+func TestSeriesValues(t *testing.T) {
+
+	if got := seriesValues(E12); !slices.Equal(got, []float64{ 1.0, 1.2, 1.5, 1.8, 2.2, 2.7, 3.3, 3.9, 4.7, 5.6, 6.8, 8.2 }) {
+
+		t.Errorf("E12 = %v", got)
+
+	}
+
+	for series, count := range map[Series]int{ E6: 6, E24: 24, E48: 48, E96: 96, E192: 192 } {
+
+		if got := len(seriesValues(series)); (got != count) {
+
+			t.Errorf("%s has %d values, want %d", series, got, count)
+
+		}
+
+	}
+
+	// the rounded formula gives 9.19 where the published E192 table has 9.20
+	if got := seriesValues(E192)[185]; (got != 9.2) {
+
+		t.Errorf("E192[185] = %g, want 9.2", got)
+
+	}
+
+}
+
+func TestSnapValue(t *testing.T) {
+
+	tests := []struct {
+
+		value	  float64
+		capacitor bool
+		combine	  bool
+		want	  Snap
+
+	}{
+
+		{ 4321.7, false, false, Snap{ Ideal: 4321.7, Value: 4700.0, Parts: []float64{ 4700.0 } } },
+		{ 4321.7, false, true, Snap{ Ideal: 4321.7, Value: 4700.0*56000.0 / 60700.0, Parts: []float64{ 4700.0, 56000.0 }, Parallel: true } },
+		{ 4321.7, true, true, Snap{ Ideal: 4321.7, Value: 4700.0*56000.0 / 60700.0, Parts: []float64{ 4700.0, 56000.0 } } },
+		{ 1.23456e-8, true, true, Snap{ Ideal: 1.23456e-8, Value: 1.233e-8, Parts: []float64{ 1.2e-8, 3.3e-10 }, Parallel: true } },
+		{ 0.0, false, true, Snap{ Ideal: 0.0, Value: 0.0, Parts: []float64{} } },
+
+	}
+
+	differs := func(p float64, q float64) bool {
+
+		return ((p != q) && (math.Abs(p / q - 1.0) > 1e-12))
+
+	}
+
+	for _, test := range tests {
+
+		got := snapValue(test.value, E12, test.capacitor, test.combine)
+
+		if (differs(got.Value, test.want.Value) ||
+			(got.Parallel != test.want.Parallel) ||
+			(len(got.Parts) != len(test.want.Parts))) {
+
+			t.Errorf("snapValue(%g, capacitor %t, combine %t) = %+v, want %+v", test.value, test.capacitor, test.combine, got, test.want)
+			continue
+
+		}
+
+		for index := range got.Parts {
+
+			if differs(got.Parts[index], test.want.Parts[index]) {
+
+				t.Errorf("snapValue(%g) part %d = %g, want %g", test.value, index, got.Parts[index], test.want.Parts[index])
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestActiveSnapDeviation(t *testing.T) {
+
+	config := Specs{ Response: LPF, Approximation: Chebyshev, Order: pointer(uint16(5)), PassbandAttenuation: pointer(0.5), CutoffFrequency: pointer(1000.0), Topology: SallenKeyUnity }
+	filter, _ := designActive(config)
+
+	if deviation := filter.deviation(filter, config); ((math.Abs(deviation.Cutoff) > 1e-12) || (deviation.Q != 0.0) || (deviation.Ripple != 0.0)) {
+
+		t.Errorf("ideal design deviates from itself by %+v", deviation)
+
+	}
+
+	// finer series and two-part combinations both pull the realized response towards the ideal one
+	coarse, _ := filter.snap(E6)
+	fine, snaps := filter.snap(E96, true)
+	rough := filter.deviation(coarse, config)
+	tight := filter.deviation(fine, config)
+
+	if ((math.Abs(tight.Cutoff) >= math.Abs(rough.Cutoff)) || (math.Abs(tight.Q) >= math.Abs(rough.Q)) || (tight.Ripple >= rough.Ripple)) {
+
+		t.Errorf("E96 combinations deviate by %+v, E6 singles by %+v", tight, rough)
+
+	}
+
+	if ((math.Abs(tight.Cutoff) > 1e-4) || (math.Abs(tight.Q) > 1e-3)) {
+
+		t.Errorf("E96 combinations deviate by %+v", tight)
+
+	}
+
+	for _, snap := range snaps {
+
+		if (math.Abs(snap.Value / snap.Ideal - 1.0) > 1e-3) {
+
+			t.Errorf("%g snapped to %g", snap.Ideal, snap.Value)
+
+		}
+
+	}
+
+}
+
+func TestLadderSnap(t *testing.T) {
+
+	config := Specs{ Response: BPF, Approximation: Chebyshev, Order: pointer(uint16(3)), PassbandAttenuation: pointer(0.5), CenterFrequency: pointer(1e6), Bandwidth: pointer(2e5) }
+	ladder, _ := designPassive(config)
+	snapped, snaps := ladder.snap(E24, true)
+
+	// narrow band resonators are too sensitive for single parts, so elements may combine two E24 values
+	for _, snap := range snaps {
+
+		for _, part := range snap.Parts {
+
+			mantissa := part / math.Pow(10, math.Floor(math.Log10(part)))
+
+			if (!slices.ContainsFunc(seriesValues(E24), func(value float64) bool { return (math.Abs(value - mantissa) < 1e-9) })) {
+
+				t.Errorf("%g was built from %g, which is not an E24 value", snap.Ideal, part)
+
+			}
+
+		}
+
+	}
+
+	if deviation := ladder.deviation(snapped, config); ((math.Abs(deviation.Cutoff) > 1e-3) || (math.Abs(deviation.Ripple) > 0.05)) {
+
+		t.Errorf("E24 ladder deviates by %+v", deviation)
+
+	}
+
+}
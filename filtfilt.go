@@ -0,0 +1,195 @@
+package main
+
+import ( "slices" )
+
+
+func steadyState(numerator []float64, denominator []float64) []float64 {
+
+	order := len(denominator) - 1
+
+	if (order < 1) {
+
+		return []float64{}
+
+	}
+
+	matrix := make([][]float64, order)
+	vector := make([]float64, order)
+
+	for row := range matrix {
+
+		matrix[row] = make([]float64, order)
+		matrix[row][row] = 1.0
+		matrix[row][0] += denominator[row + 1]
+
+		if (row + 1 < order) {
+
+			matrix[row][row + 1] -= 1.0
+
+		}
+
+		vector[row] = numerator[row + 1] - denominator[row + 1]*numerator[0]
+
+	}
+
+	return solveLinear(matrix, vector)
+
+}
+
+func (p *Processor) initialise(x float64) {
+
+	p.Reset()
+
+	if (p.Structure == SecondOrderSections) {
+
+		scale := x
+
+		for index, section := range p.Sections {
+
+			zi := steadyState(section.Numerator[:], section.Denominator[:])
+			p.state[2*index] = zi[0]*scale
+			p.state[2*index + 1] = zi[1]*scale
+			scale *= (section.Numerator[0] + section.Numerator[1] + section.Numerator[2]) /
+					 (section.Denominator[0] + section.Denominator[1] + section.Denominator[2])
+
+		}
+
+	} else if (p.Structure == TransposedDirectFormII) {
+
+		for index, value := range steadyState(p.Numerator, p.Denominator) {
+
+			p.state[index] = value*x
+
+		}
+
+	}
+
+}
+
+func padSignal(signal []float64, padding Padding, length int) []float64 {
+
+	if (length == 0) {
+
+		return append([]float64{}, signal...)
+
+	}
+
+	first := signal[0]
+	last := signal[len(signal) - 1]
+	extended := make([]float64, 0, len(signal) + 2*length)
+
+	for index := length; index > 0; index-- {
+
+		switch padding {
+
+			case Even:
+
+				extended = append(extended, signal[index])
+
+			case Constant:
+
+				extended = append(extended, first)
+
+			default:
+
+				extended = append(extended, 2.0*first - signal[index])
+
+		}
+
+	}
+
+	extended = append(extended, signal...)
+
+	for index := 1; index <= length; index++ {
+
+		mirror := signal[len(signal) - 1 - index]
+
+		switch padding {
+
+			case Even:
+
+				extended = append(extended, mirror)
+
+			case Constant:
+
+				extended = append(extended, last)
+
+			default:
+
+				extended = append(extended, 2.0*last - mirror)
+
+		}
+
+	}
+
+	return extended
+
+}
+
+func forwardBackward(p *Processor, signal []float64, padding Padding, length int) []float64 {
+
+	if (len(signal) == 0) {
+
+		return []float64{}
+
+	}
+
+	if (!padding.exists()) {
+
+		padding = Odd
+
+	}
+
+	length = min(length, len(signal) - 1)
+
+	if (padding == None) {
+
+		length = 0
+
+	}
+
+	extended := padSignal(signal, padding, length)
+	filtered := make([]float64, len(extended))
+
+	p.initialise(extended[0])
+	p.ProcessBlock(extended, filtered)
+	slices.Reverse(filtered)
+
+	p.initialise(filtered[0])
+	p.ProcessBlock(filtered, filtered)
+	slices.Reverse(filtered)
+
+	p.Reset()
+	return filtered[length:len(filtered) - length]
+
+}
+
+func filtfilt(filter Polynomial, signal []float64, padding ...Padding) []float64 {
+
+	form := Odd
+
+	if (len(padding) > 0) {
+
+		form = padding[0]
+
+	}
+
+	p := newProcessor(filter, TransposedDirectFormII)
+	return forwardBackward(p, signal, form, 3*len(p.Denominator))
+
+}
+
+func filtfiltSections(sections []Section, signal []float64, padding ...Padding) []float64 {
+
+	form := Odd
+
+	if (len(padding) > 0) {
+
+		form = padding[0]
+
+	}
+
+	p := newCascadeProcessor(sections)
+	return forwardBackward(p, signal, form, 3*(2*len(sections) + 1))
+
+}
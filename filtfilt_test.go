@@ -0,0 +1,134 @@
+package main
+
+import ( "math"
+		 "testing" )
+
+
+// reference vectors follow scipy.signal.lfilter_zi and scipy.signal.filtfilt with the default
+// padlen of 3*max(len(a), len(b)), evaluated in exact rational arithmetic and rounded
+var (
+
+	referenceNumerator = []float64{ 0.2, 0.3, 0.1 }
+	referenceDenominator = []float64{ 1.0, -0.6, 0.25 }
+	referenceSignal = []float64{ 1, 3, -2, 4, 0, 5, -1, 2, 6, -3, 1, 2, 0, 4, -2, 3 }
+
+	referenceOutputs = map[Padding][]float64{
+
+		Odd: { 0.8501337771766571, 0.8408386780027477, 0.9456805495110433, 1.3008845635713897, 1.6741136208279193, 1.7765106662929322, 1.760798637571062, 1.8155065204187697,
+			   1.5229936915278435, 0.8246342198209852, 0.4937894783547726, 0.7026742784476734, 0.9292696714394758, 1.0063265851944987, 1.4256686820811262, 2.556142512357435 },
+
+		Even: { 1.2138347572932848, 1.083826450797333, 1.000410852498614, 1.273043516820695, 1.6444373948436863, 1.7671006805198126, 1.7631729628623338, 1.8149846841396324,
+				1.5093645404479683, 0.803248976656586, 0.5032437843773818, 0.8123536131604433, 1.153986095895817, 1.1061486052800886, 0.7660819852970143, 0.573865066374347 },
+
+		Constant: { 1.0319842672349708, 0.9623325644000404, 0.9730457010048286, 1.2869640401960425, 1.6592755078358028, 1.7718056734063723, 1.761985800216698, 1.815245602279201,
+					1.516179115987906, 0.8139415982387856, 0.4985166313660772, 0.7575139458040583, 1.0416278836676465, 1.0562375952372935, 1.0958753336890703, 1.565003789365891 },
+
+		None: { 1.0319847612827888, 0.9622973114338078, 0.9729591176945991, 1.2868972521164221, 1.659461549685632, 1.7725193261644439, 1.7629543994367527, 1.8147156293750468,
+				1.5110327841377165, 0.8037102934149479, 0.49454682718962456, 0.7889116350759229, 1.132861554625932, 1.1496076484497206, 0.9550287775657531, 0.8534918418202219 },
+
+	}
+
+)
+
+func compareSignals(t *testing.T, name string, got []float64, want []float64, tolerance float64) {
+
+	t.Helper()
+
+	if (len(got) != len(want)) {
+
+		t.Fatalf("%s: got %d samples, want %d", name, len(got), len(want))
+
+	}
+
+	for index := range want {
+
+		if (math.Abs(got[index] - want[index]) > tolerance) {
+
+			t.Errorf("%s: sample %d = %.16g, want %.16g", name, index, got[index], want[index])
+
+		}
+
+	}
+
+}
+
+func TestSteadyState(t *testing.T) {
+
+	compareSignals(t, "lfilter_zi", steadyState(referenceNumerator, referenceDenominator), []float64{ 0.7230769230769231, -0.13076923076923078 }, 1e-14)
+
+	// first order closed form: zi = (b1 - a1*b0) / (1 + a1)
+	compareSignals(t, "first order", steadyState([]float64{ 0.5, 0.25 }, []float64{ 1.0, -0.5 }), []float64{ 1.0 }, 1e-14)
+
+}
+
+func TestFiltfiltPadding(t *testing.T) {
+
+	filter := digitalPolynomial(referenceNumerator, referenceDenominator)
+
+	for _, padding := range []Padding{ Odd, Even, Constant, None } {
+
+		compareSignals(t, string(padding), filtfilt(filter, referenceSignal, padding), referenceOutputs[padding], 1e-12)
+
+	}
+
+	compareSignals(t, "default", filtfilt(filter, referenceSignal), referenceOutputs[Odd], 1e-12)
+
+}
+
+func TestFiltfiltSections(t *testing.T) {
+
+	section := Section{
+
+		Numerator: [3]float64{ referenceNumerator[0], referenceNumerator[1], referenceNumerator[2] },
+		Denominator: [3]float64{ referenceDenominator[0], referenceDenominator[1], referenceDenominator[2] },
+
+	}
+
+	for _, padding := range []Padding{ Odd, Even, Constant, None } {
+
+		compareSignals(t, string(padding), filtfiltSections([]Section{ section }, referenceSignal, padding), referenceOutputs[padding], 1e-12)
+
+	}
+
+}
+
+func TestFiltfiltConstantSignal(t *testing.T) {
+
+	// steady-state initial conditions leave no transient, so a constant is scaled by |H(1)|^2
+	filter := digitalPolynomial(referenceNumerator, referenceDenominator)
+	gain := (0.2 + 0.3 + 0.1) / (1.0 - 0.6 + 0.25)
+	signal := make([]float64, 40)
+	want := make([]float64, len(signal))
+
+	for index := range signal {
+
+		signal[index] = 2.5
+		want[index] = 2.5*gain*gain
+
+	}
+
+	for _, padding := range []Padding{ Odd, Even, Constant, None } {
+
+		compareSignals(t, string(padding), filtfilt(filter, signal, padding), want, 1e-12)
+
+	}
+
+}
+
+func TestFiltfiltShortSignal(t *testing.T) {
+
+	filter := digitalPolynomial(referenceNumerator, referenceDenominator)
+
+	if output := filtfilt(filter, []float64{}); (len(output) != 0) {
+
+		t.Errorf("empty signal: got %d samples", len(output))
+
+	}
+
+	if output := filtfilt(filter, referenceSignal[:4]); (len(output) != 4) {
+
+		t.Errorf("short signal: got %d samples, want 4", len(output))
+
+	}
+
+}
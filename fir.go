@@ -0,0 +1,106 @@
+package main
+
+import ( "math" )
+
+
+func besselZero(x float64) float64 {
+
+	sum := 1.0
+	term := 1.0
+	precision := 1e-12
+
+	for index := 1; term > precision*sum; index++ {
+
+		term *= math.Pow(x / (2.0*float64(index)), 2)
+		sum += term
+
+	}
+
+	return sum
+
+}
+
+func kaiserBeta(attenuation float64) float64 {
+
+	beta := 0.0
+
+	if (attenuation > 50.0) {
+
+		beta = 0.1102*(attenuation - 8.7)
+
+	} else if (attenuation >= 21.0) {
+
+		beta = 0.5842*math.Pow(attenuation - 21.0, 0.4) + 0.07886*(attenuation - 21.0)
+
+	}
+
+	return beta
+
+}
+
+func kaiserLength(attenuation float64, transitionWidth float64, samplingFrequency float64) int {
+
+	omega := 2.0*math.Pi*transitionWidth / samplingFrequency
+	length := int(math.Ceil((attenuation - 7.95) / (2.285*omega))) + 1
+
+	if (length%2 == 0) {
+
+		length++
+
+	}
+
+	return max(length, 3)
+
+}
+
+func kaiserWindow(length int, beta float64) []float64 {
+
+	window := make([]float64, length)
+	denominator := besselZero(beta)
+	centre := float64(length - 1) / 2.0
+
+	for index := range window {
+
+		ratio := 0.0
+
+		if (centre > 0.0) {
+
+			ratio = (float64(index) - centre) / centre
+
+		}
+
+		window[index] = besselZero(beta*math.Sqrt(math.Max(1.0 - math.Pow(ratio, 2), 0.0))) / denominator
+
+	}
+
+	return window
+
+}
+
+func sinc(x float64) float64 {
+
+	if (x == 0.0) {
+
+		return 1.0
+
+	}
+
+	return math.Sin(math.Pi*x) / (math.Pi*x)
+
+}
+
+func windowedSinc(cutoffFrequency float64, samplingFrequency float64, window []float64) []float64 {
+
+	taps := make([]float64, len(window))
+	centre := float64(len(window) - 1) / 2.0
+	normalized := 2.0*cutoffFrequency / samplingFrequency
+
+	for index := range taps {
+
+		taps[index] = normalized*sinc(normalized*(float64(index) - centre))*window[index]
+
+	}
+
+	return taps
+
+}
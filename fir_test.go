@@ -0,0 +1,87 @@
+package main
+
+import ( "math"
+		 "testing" )
+
+
+func TestBesselZero(t *testing.T) {
+
+	for x, want := range map[float64]float64{ 0.0: 1.0, 1.0: 1.2660658777520082, 5.0: 27.239871823604442 } {
+
+		if got := besselZero(x); (math.Abs(got - want) > 1e-10*want) {
+
+			t.Errorf("I0(%g) = %.16g, want %.16g", x, got, want)
+
+		}
+
+	}
+
+}
+
+func TestKaiserDesign(t *testing.T) {
+
+	for attenuation, want := range map[float64]float64{ 10.0: 0.0, 30.0: 0.5842*math.Pow(9.0, 0.4) + 0.07886*9.0, 60.0: 0.1102*51.3 } {
+
+		if got := kaiserBeta(attenuation); (math.Abs(got - want) > 1e-12) {
+
+			t.Errorf("beta for %g dB = %g, want %g", attenuation, got, want)
+
+		}
+
+	}
+
+	// (80 - 7.95) / (2.285*2π*0.05) = 100.37, rounded up and made odd
+	if got := kaiserLength(80.0, 0.05, 1.0); (got != 103) {
+
+		t.Errorf("length = %d, want 103", got)
+
+	}
+
+	window := kaiserWindow(31, kaiserBeta(60.0))
+
+	if (math.Abs(window[15] - 1.0) > 1e-12) {
+
+		t.Errorf("centre of the window = %g, want 1", window[15])
+
+	}
+
+	for index := range window {
+
+		if (math.Abs(window[index] - window[len(window) - index - 1]) > 1e-15) {
+
+			t.Errorf("window is not symmetric at %d", index)
+
+		}
+
+	}
+
+}
+
+func TestWindowedSinc(t *testing.T) {
+
+	taps := windowedSinc(1000.0, 8000.0, kaiserWindow(kaiserLength(60.0, 500.0, 8000.0), kaiserBeta(60.0)))
+	filter := digitalPolynomial(taps, []float64{ 1.0 })
+
+	if got := magnitudeResponse(filter, 0.0, 8000.0); (math.Abs(got - 1.0) > 1e-3) {
+
+		t.Errorf("DC gain = %g, want 1", got)
+
+	}
+
+	if got := decibels(magnitudeResponse(filter, 1000.0, 8000.0)); (math.Abs(got - decibels(0.5)) > 0.1) {
+
+		t.Errorf("gain at the cutoff = %g dB, want -6 dB", got)
+
+	}
+
+	for _, frequency := range []float64{ 1250.0, 2000.0, 3900.0 } {
+
+		if got := decibels(magnitudeResponse(filter, frequency, 8000.0)); (got > -59.0) {
+
+			t.Errorf("stopband gain at %g Hz = %g dB, want below -60 dB", frequency, got)
+
+		}
+
+	}
+
+}
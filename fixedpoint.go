@@ -0,0 +1,380 @@
+package main
+
+import ( "math" )
+
+
+type FixedPoint struct {
+
+	Datapath	 Datapath
+	Sections	 [][2][3]int64
+	Overflows	 int
+	Saturations	 int
+	input		 [][2]int64
+	output		 [][2]int64
+
+}
+
+type Comparison struct {
+
+	Float		[]float64
+	Fixed		[]float64
+	SNR			float64
+	Overflows	int
+	Saturations int
+
+}
+
+func shiftRound(value int64, shift uint16, rounding Rounding) int64 {
+
+	if (shift == 0) {
+
+		return value
+
+	}
+
+	switch rounding {
+
+		case Truncate:
+
+			return value >> shift
+
+		case TowardZero:
+
+			if (value < 0) {
+
+				return -((-value) >> shift)
+
+			}
+
+			return value >> shift
+
+		case Convergent:
+
+			half := int64(1) << (shift - 1)
+			remainder := value & ((int64(1) << shift) - 1)
+			quotient := value >> shift
+
+			if ((remainder > half) ||
+				((remainder == half) && (quotient%2 != 0))) {
+
+				quotient++
+
+			}
+
+			return quotient
+
+		default:
+
+			return (value + (int64(1) << (shift - 1))) >> shift
+
+	}
+
+}
+
+func (f *FixedPoint) constrain(value int64, width uint16) int64 {
+
+	width = min(max(width, 2), 63)
+	maximum := int64(1) << (width - 1) - 1
+	minimum := -(int64(1) << (width - 1))
+
+	if ((value <= maximum) && (value >= minimum)) {
+
+		return value
+
+	}
+
+	f.Overflows++
+
+	if (f.Datapath.Overflow == Wrap) {
+
+		shift := 64 - width
+		return (value << shift) >> shift
+
+	}
+
+	if (value > maximum) {
+
+		return maximum
+
+	}
+
+	return minimum
+
+}
+
+func newFixedPoint(sections []Section, datapath Datapath) *FixedPoint {
+
+	if (!datapath.Rounding.exists()) {
+
+		datapath.Rounding = Nearest
+
+	}
+
+	if (!datapath.Overflow.exists()) {
+
+		datapath.Overflow = Saturate
+
+	}
+
+	if (datapath.Accumulator == 0) {
+
+		datapath.Accumulator = datapath.Data.bits() + datapath.Coefficient.bits() + 2
+
+	}
+
+	datapath.Accumulator = min(datapath.Accumulator, 62)
+	quantized := make([][2][3]int64, len(sections))
+	saturations := 0
+
+	for index, section := range sections {
+
+		scale := section.Denominator[0]
+
+		if (scale == 0.0) {
+
+			scale = 1.0
+
+		}
+
+		for k := 0; k < 3; k++ {
+
+			numerator, clipped := datapath.Coefficient.quantize(section.Numerator[k] / scale, datapath.Rounding)
+			quantized[index][0][k] = numerator
+
+			if clipped {
+
+				saturations++
+
+			}
+
+			// the leading denominator coefficient is implicit in the recursion, so only a1 and a2 can clip
+			denominator, clipped := datapath.Coefficient.quantize(section.Denominator[k] / scale, datapath.Rounding)
+			quantized[index][1][k] = denominator
+
+			if (clipped && (k > 0)) {
+
+				saturations++
+
+			}
+
+		}
+
+	}
+
+	return &FixedPoint{
+
+		Datapath: datapath,
+		Sections: quantized,
+		Saturations: saturations,
+		input: make([][2]int64, len(sections)),
+		output: make([][2]int64, len(sections)),
+
+	}
+
+}
+
+func (f *FixedPoint) Reset() {
+
+	clear(f.input)
+	clear(f.output)
+	f.Overflows = 0
+
+}
+
+func (f *FixedPoint) multiply(coefficient int64, sample int64) int64 {
+
+	product := coefficient*sample
+
+	if f.Datapath.RoundProducts {
+
+		shift := f.Datapath.Coefficient.Fraction
+		product = shiftRound(product, shift, f.Datapath.Rounding) << shift
+
+	}
+
+	return product
+
+}
+
+func (f *FixedPoint) processInteger(x int64) int64 {
+
+	width := f.Datapath.Accumulator
+	shift := f.Datapath.Coefficient.Fraction
+
+	for index, section := range f.Sections {
+
+		b := section[0]
+		a := section[1]
+		accumulator := f.constrain(f.multiply(b[0], x), width)
+		accumulator = f.constrain(accumulator + f.multiply(b[1], f.input[index][0]), width)
+		accumulator = f.constrain(accumulator + f.multiply(b[2], f.input[index][1]), width)
+		accumulator = f.constrain(accumulator - f.multiply(a[1], f.output[index][0]), width)
+		accumulator = f.constrain(accumulator - f.multiply(a[2], f.output[index][1]), width)
+		y := f.constrain(shiftRound(accumulator, shift, f.Datapath.Rounding), f.Datapath.Data.bits())
+
+		f.input[index] = [2]int64{ x, f.input[index][0] }
+		f.output[index] = [2]int64{ y, f.output[index][0] }
+		x = y
+
+	}
+
+	return x
+
+}
+
+func (f *FixedPoint) Process(x float64) float64 {
+
+	sample, saturated := f.Datapath.Data.quantize(x, f.Datapath.Rounding)
+
+	if saturated {
+
+		f.Overflows++
+
+	}
+
+	return f.Datapath.Data.value(f.processInteger(sample))
+
+}
+
+func (f *FixedPoint) ProcessBlock(in []float64, out []float64) {
+
+	for index := 0; index < min(len(in), len(out)); index++ {
+
+		out[index] = f.Process(in[index])
+
+	}
+
+}
+
+func (f *FixedPoint) limitCycle(samples int) (bool, int, float64) {
+
+	seen := map[string]int{}
+	state := func() string {
+
+		key := make([]byte, 0, 32*len(f.Sections))
+
+		for index := range f.Sections {
+
+			for _, value := range []int64{ f.input[index][0], f.input[index][1], f.output[index][0], f.output[index][1] } {
+
+				for shift := 0; shift < 64; shift += 8 {
+
+					key = append(key, byte(value >> shift))
+
+				}
+
+			}
+
+		}
+
+		return string(key)
+
+	}
+
+	outputs := make([]float64, 0, samples)
+
+	for sample := 0; sample < samples; sample++ {
+
+		y := f.processInteger(0)
+		key := state()
+		outputs = append(outputs, f.Datapath.Data.value(y))
+
+		if previous, exists := seen[key]; exists {
+
+			silent := true
+
+			for index := range f.Sections {
+
+				if ((f.output[index] != [2]int64{}) ||
+					(f.input[index] != [2]int64{})) {
+
+					silent = false
+
+				}
+
+			}
+
+			if silent {
+
+				return false, 0, 0.0
+
+			}
+
+			amplitude := 0.0
+
+			for _, value := range outputs[previous + 1:] {
+
+				amplitude = math.Max(amplitude, math.Abs(value))
+
+			}
+
+			return true, sample - previous, amplitude
+
+		}
+
+		seen[key] = sample
+
+	}
+
+	return false, 0, 0.0
+
+}
+
+func detectLimitCycles(sections []Section, datapath Datapath, samples ...int) (bool, int, float64) {
+
+	length := 4096
+
+	if (len(samples) > 0) {
+
+		length = samples[0]
+
+	}
+
+	f := newFixedPoint(sections, datapath)
+
+	for _, x := range []float64{ 0.5, -0.5, 0.25, 0.75, -0.125 } {
+
+		f.Process(x)
+
+	}
+
+	return f.limitCycle(length)
+
+}
+
+func compareFixedPoint(sections []Section, datapath Datapath, signal []float64) Comparison {
+
+	reference := newCascadeProcessor(sections)
+	fixed := newFixedPoint(sections, datapath)
+	comparison := Comparison{
+
+		Float: make([]float64, len(signal)),
+		Fixed: make([]float64, len(signal)),
+
+	}
+
+	reference.ProcessBlock(signal, comparison.Float)
+	fixed.ProcessBlock(signal, comparison.Fixed)
+	power := 0.0
+	noise := 0.0
+
+	for index := range signal {
+
+		power += math.Pow(comparison.Float[index], 2)
+		noise += math.Pow(comparison.Float[index] - comparison.Fixed[index], 2)
+
+	}
+
+	comparison.SNR = math.Inf(1)
+
+	if (noise > 0.0) {
+
+		comparison.SNR = 10.0*math.Log10(power / noise)
+
+	}
+
+	comparison.Overflows = fixed.Overflows
+	comparison.Saturations = fixed.Saturations
+	return comparison
+
+}
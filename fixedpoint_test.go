@@ -0,0 +1,129 @@
+package main
+
+import ( "math"
+		 "testing" )
+
+
+// a lightly damped resonator, poles at radius √0.95
+var resonatorSection = Section{ Numerator: [3]float64{ 0.1, 0.0, 0.0 }, Denominator: [3]float64{ 1.0, -1.8, 0.95 } }
+
+func TestShiftRound(t *testing.T) {
+
+	tests := []struct {
+
+		value	 int64
+		rounding Rounding
+		want	 int64
+
+	}{
+
+		{ 10, Nearest, 3 },
+		{ -10, Nearest, -2 },
+		{ 10, Convergent, 2 },
+		{ 14, Convergent, 4 },
+		{ -11, Truncate, -3 },
+		{ -11, TowardZero, -2 },
+		{ 11, TowardZero, 2 },
+
+	}
+
+	for _, test := range tests {
+
+		if got := shiftRound(test.value, 2, test.rounding); (got != test.want) {
+
+			t.Errorf("shiftRound(%d, 2, %s) = %d, want %d", test.value, test.rounding, got, test.want)
+
+		}
+
+	}
+
+}
+
+func TestFixedPointOverflow(t *testing.T) {
+
+	f := newFixedPoint([]Section{ resonatorSection }, Datapath{ Coefficient: Format{ Integer: 1, Fraction: 14 }, Data: Format{ Integer: 0, Fraction: 7 }, Overflow: Wrap })
+
+	if ((f.constrain(130, 8) != -126) || (f.constrain(-129, 8) != 127)) {
+
+		t.Errorf("wrap-around gave %d and %d, want -126 and 127", f.constrain(130, 8), f.constrain(-129, 8))
+
+	}
+
+	f.Datapath.Overflow = Saturate
+
+	if ((f.constrain(130, 8) != 127) || (f.constrain(-129, 8) != -128)) {
+
+		t.Errorf("saturation gave %d and %d, want 127 and -128", f.constrain(130, 8), f.constrain(-129, 8))
+
+	}
+
+	if (f.Overflows != 4) {
+
+		t.Errorf("counted %d overflows, want 4", f.Overflows)
+
+	}
+
+	// b = [1 2 1] and a1 = -1.8 all lie outside Q0.15, while Q1.14 only clips b1
+	section := Section{ Numerator: [3]float64{ 1.0, 2.0, 1.0 }, Denominator: [3]float64{ 1.0, -1.8, 0.95 } }
+
+	for format, want := range map[Format]int{ { Integer: 0, Fraction: 15 }: 4, { Integer: 1, Fraction: 14 }: 1 } {
+
+		if got := newFixedPoint([]Section{ section }, Datapath{ Coefficient: format, Data: Format{ Integer: 0, Fraction: 15 } }).Saturations; (got != want) {
+
+			t.Errorf("Q%d.%d coefficients: %d saturations, want %d", format.Integer, format.Fraction, got, want)
+
+		}
+
+	}
+
+}
+
+func TestCompareFixedPoint(t *testing.T) {
+
+	filter := digitalPolynomial(equalizerNumerator, equalizerDenominator)
+	sections := filter.sections()
+	signal := make([]float64, 2000)
+
+	for index := range signal {
+
+		signal[index] = 0.4*math.Sin(0.05*float64(index)) + 0.2*math.Sin(1.3*float64(index))
+
+	}
+
+	narrow := compareFixedPoint(sections, Datapath{ Coefficient: Format{ Integer: 1, Fraction: 14 }, Data: Format{ Integer: 0, Fraction: 15 } }, signal)
+	wide := compareFixedPoint(sections, Datapath{ Coefficient: Format{ Integer: 1, Fraction: 30 }, Data: Format{ Integer: 0, Fraction: 31 } }, signal)
+
+	if ((narrow.SNR < 55.0) || (wide.SNR < narrow.SNR + 60.0)) {
+
+		t.Errorf("SNR = %g dB on a 16 bit datapath and %g dB on a 32 bit one", narrow.SNR, wide.SNR)
+
+	}
+
+	if ((narrow.Overflows != 0) || (wide.Overflows != 0)) {
+
+		t.Errorf("got %d and %d datapath overflows, want none", narrow.Overflows, wide.Overflows)
+
+	}
+
+}
+
+func TestLimitCycles(t *testing.T) {
+
+	datapath := Datapath{ Coefficient: Format{ Integer: 1, Fraction: 14 }, Data: Format{ Integer: 0, Fraction: 15 } }
+
+	// rounding to nearest sustains a zero-input oscillation that magnitude truncation removes
+	if found, period, amplitude := detectLimitCycles([]Section{ resonatorSection }, datapath); (!found || (period != 16) || (amplitude <= 0.0)) {
+
+		t.Errorf("rounding: found %t with period %d and amplitude %g, want a period of 16", found, period, amplitude)
+
+	}
+
+	datapath.Rounding = TowardZero
+
+	if found, _, _ := detectLimitCycles([]Section{ resonatorSection }, datapath); found {
+
+		t.Errorf("magnitude truncation still produced a limit cycle")
+
+	}
+
+}
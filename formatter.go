@@ -0,0 +1,717 @@
+package main
+
+import ( "cmp"
+		 "fmt"
+		 "math"
+		 "math/cmplx"
+		 "slices"
+		 "strconv"
+		 "strings" )
+
+
+func parseFormatting(config Formatting, variable string) (Markup, int, string) {
+
+	markup := Unicode
+
+	if config.Markup.exists() {
+
+		markup = config.Markup
+
+	}
+
+	digits := -1
+
+	if ((config.Digits != nil) && (*config.Digits > 0)) {
+
+		digits = *config.Digits
+
+	}
+
+	if (config.Variable != "") {
+
+		variable = config.Variable
+
+	}
+
+	if (variable == "") {
+
+		variable = "s"
+
+	}
+
+	return markup, digits, variable
+
+}
+
+func formatNumber(value float64, digits int, markup Markup) string {
+
+	number := strconv.FormatFloat(value, 'f', -1, 64)
+
+	if (digits > 0) {
+
+		number = strconv.FormatFloat(value, 'g', digits, 64)
+
+	}
+
+	mantissa, exponent, scientific := strings.Cut(number, "e")
+
+	if scientific {
+
+		exponent = strings.TrimLeft(strings.TrimPrefix(exponent, "+"), "0")
+		exponent = strings.Replace(exponent, "-0", "-", 1)
+
+	}
+
+	switch markup {
+
+		case LaTeX:
+
+			if scientific {
+
+				return mantissa + " \\times 10^{" + exponent + "}"
+
+			}
+
+		case MathML:
+
+			if scientific {
+
+				return "<mn>" + mantissa + "</mn><mo>&#xD7;</mo><msup><mn>10</mn><mn>" + exponent + "</mn></msup>"
+
+			}
+
+			return "<mn>" + number + "</mn>"
+
+		case Unicode:
+
+			if scientific {
+
+				return mantissa + "×10" + getSuperscript(exponent)
+
+			}
+
+	}
+
+	return number
+
+}
+
+func formatPower(variable string, exponent int64, markup Markup) string {
+
+	power := strconv.FormatInt(exponent, 10)
+
+	switch markup {
+
+		case ASCII:
+
+			if (exponent < 0) {
+
+				return variable + "^(" + power + ")"
+
+			} else if (exponent != 1) {
+
+				return variable + "^" + power
+
+			}
+
+		case LaTeX:
+
+			if (exponent != 1) {
+
+				return variable + "^{" + power + "}"
+
+			}
+
+		case MathML:
+
+			if (exponent < 0) {
+
+				return "<msup><mi>" + variable + "</mi><mrow><mo>-</mo><mn>" + strconv.FormatInt(-exponent, 10) + "</mn></mrow></msup>"
+
+			} else if (exponent != 1) {
+
+				return "<msup><mi>" + variable + "</mi><mn>" + power + "</mn></msup>"
+
+			}
+
+			return "<mi>" + variable + "</mi>"
+
+		default:
+
+			if (exponent != 1) {
+
+				return variable + getSuperscript(power)
+
+			}
+
+	}
+
+	return variable
+
+}
+
+func formatRaised(base string, exponent int64, markup Markup) string {
+
+	if (exponent == 1) {
+
+		return base
+
+	}
+
+	power := strconv.FormatInt(exponent, 10)
+
+	switch markup {
+
+		case ASCII:
+
+			return base + "^" + power
+
+		case LaTeX:
+
+			return base + "^{" + power + "}"
+
+		case MathML:
+
+			return "<msup>" + base + "<mn>" + power + "</mn></msup>"
+
+	}
+
+	return base + getSuperscript(power)
+
+}
+
+func formatOperator(operator string, markup Markup) string {
+
+	if (markup == MathML) {
+
+		return "<mo>" + operator + "</mo>"
+
+	}
+
+	if ((operator == "(") || (operator == ")")) {
+
+		return operator
+
+	}
+
+	return " " + operator + " "
+
+}
+
+func formatSum(terms []Term, variable string, digits int, markup Markup) string {
+
+	builder := strings.Builder{}
+	written := 0
+
+	for _, term := range terms {
+
+		if (term.Coefficient == 0.0) {
+
+			continue
+
+		}
+
+		magnitude := math.Abs(term.Coefficient)
+
+		if ((written == 0) && (term.Coefficient < 0.0)) {
+
+			builder.WriteString(strings.TrimSpace(formatOperator("-", markup)))
+
+		} else if ((written > 0) && (term.Coefficient < 0.0)) {
+
+			builder.WriteString(formatOperator("-", markup))
+
+		} else if (written > 0) {
+
+			builder.WriteString(formatOperator("+", markup))
+
+		}
+
+		number := formatNumber(magnitude, digits, markup)
+
+		if (term.Exponent == 0) {
+
+			builder.WriteString(number)
+
+		} else if (number == formatNumber(1.0, digits, markup)) {
+
+			builder.WriteString(formatPower(variable, term.Exponent, markup))
+
+		} else if (markup == MathML) {
+
+			builder.WriteString(number + "<mo>&#x2062;</mo>" + formatPower(variable, term.Exponent, markup))
+
+		} else if ((markup == ASCII) && strings.Contains(number, "e")) {
+
+			builder.WriteString(number + "*" + formatPower(variable, term.Exponent, markup))
+
+		} else {
+
+			builder.WriteString(number + formatPower(variable, term.Exponent, markup))
+
+		}
+
+		written++
+
+	}
+
+	if (written == 0) {
+
+		return formatNumber(0.0, digits, markup)
+
+	}
+
+	return builder.String()
+
+}
+
+func formatGroup(content string, markup Markup) string {
+
+	if (markup == MathML) {
+
+		return "<mrow><mo>(</mo>" + content + "<mo>)</mo></mrow>"
+
+	}
+
+	return "(" + content + ")"
+
+}
+
+func formatFraction(numerator string, denominator string, markup Markup) string {
+
+	switch markup {
+
+		case LaTeX:
+
+			return "\\frac{" + numerator + "}{" + denominator + "}"
+
+		case MathML:
+
+			return "<mfrac><mrow>" + numerator + "</mrow><mrow>" + denominator + "</mrow></mfrac>"
+
+		case ASCII:
+
+			return numerator + "/" + denominator
+
+	}
+
+	return stackedFraction(numerator, denominator)
+
+}
+
+func formatEquation(label string, variable string, content string, markup Markup) string {
+
+	if (markup == MathML) {
+
+		prefix := ""
+
+		if (label != "") {
+
+			prefix = fmt.Sprintf("<mi>%s</mi><mo>(</mo><mi>%s</mi><mo>)</mo><mo>=</mo>", label, variable)
+
+		}
+
+		return "<math xmlns=\"http://www.w3.org/1998/Math/MathML\"><mrow>" + prefix + content + "</mrow></math>"
+
+	}
+
+	if (label == "") {
+
+		return content
+
+	}
+
+	equation := fmt.Sprintf("%s(%s) = ", label, variable)
+
+	if ((markup == Unicode) && strings.Contains(content, "\n")) {
+
+		lines := strings.Split(content, "\n")
+		padding := strings.Repeat(" ", len([]rune(equation)))
+		return padding + lines[0] + "\n" + equation + lines[1] + "\n" + padding + lines[2]
+
+	}
+
+	return equation + content
+
+}
+
+func orderedTerms(e Expression) []Term {
+
+	terms := []Term{}
+
+	for exponent, coefficient := range accumulate(e.Terms, []Term{}) {
+
+		terms = append(terms, Term{ Coefficient: coefficient, Exponent: exponent })
+
+	}
+
+	slices.SortFunc(terms, func(p Term, q Term) int {
+
+		return cmp.Compare(q.Exponent, p.Exponent)
+
+	})
+
+	return terms
+
+}
+
+func descendingTerms(coefficients []float64) []Term {
+
+	terms := []Term{}
+
+	for index, coefficient := range coefficients {
+
+		terms = append(terms, Term{ Coefficient: coefficient, Exponent: int64(len(coefficients) - index - 1) })
+
+	}
+
+	return terms
+
+}
+
+func ascendingTerms(coefficients []float64) []Term {
+
+	terms := []Term{}
+
+	for index, coefficient := range coefficients {
+
+		terms = append(terms, Term{ Coefficient: coefficient, Exponent: -int64(index) })
+
+	}
+
+	return terms
+
+}
+
+func analogueFactors(coefficients []float64) (float64, int, [][]float64) {
+
+	for ((len(coefficients) > 1) && (coefficients[0] == 0.0)) {
+
+		coefficients = coefficients[1:]
+
+	}
+
+	origin := 0
+
+	for ((len(coefficients) > 1) && (coefficients[len(coefficients) - 1] == 0.0)) {
+
+		coefficients = coefficients[:len(coefficients) - 1]
+		origin++
+
+	}
+
+	factors := [][]float64{}
+	upper := []complex128{}
+	lower := []complex128{}
+
+	for _, root := range polynomialRoots(coefficients) {
+
+		if (math.Abs(imag(root)) <= 1e-9*cmplx.Abs(root)) {
+
+			factors = append(factors, []float64{ 1.0, -real(root) })
+
+		} else if (imag(root) > 0.0) {
+
+			upper = append(upper, root)
+
+		} else {
+
+			lower = append(lower, root)
+
+		}
+
+	}
+
+	for _, root := range upper {
+
+		if (len(lower) == 0) {
+
+			break
+
+		}
+
+		nearest := 0
+
+		for index := range lower {
+
+			if (cmplx.Abs(lower[index] - cmplx.Conj(root)) < cmplx.Abs(lower[nearest] - cmplx.Conj(root))) {
+
+				nearest = index
+
+			}
+
+		}
+
+		partner := lower[nearest]
+		lower = slices.Delete(lower, nearest, nearest + 1)
+		factors = append(factors, []float64{ 1.0, -real(root + partner), real(root*partner) })
+
+	}
+
+	degree := 0
+
+	for _, factor := range factors {
+
+		degree += len(factor) - 1
+
+	}
+
+	if (degree != len(coefficients) - 1) {
+
+		monic := make([]float64, len(coefficients))
+
+		for index, coefficient := range coefficients {
+
+			monic[index] = coefficient / coefficients[0]
+
+		}
+
+		return coefficients[0], origin, [][]float64{ monic }
+
+	}
+
+	slices.SortStableFunc(factors, func(p []float64, q []float64) int {
+
+		return cmp.Or(cmp.Compare(len(p), len(q)), cmp.Compare(math.Abs(p[len(p) - 1]), math.Abs(q[len(q) - 1])))
+
+	})
+
+	return coefficients[0], origin, factors
+
+}
+
+func formatProduct(gain float64, origin int, factors [][]Term, variable string, digits int, markup Markup) string {
+
+	builder := strings.Builder{}
+	unity := formatNumber(1.0, digits, markup)
+	number := formatNumber(math.Abs(gain), digits, markup)
+
+	if (gain < 0.0) {
+
+		builder.WriteString(strings.TrimSpace(formatOperator("-", markup)))
+
+	}
+
+	if ((number != unity) || ((origin == 0) && (len(factors) == 0))) {
+
+		builder.WriteString(number)
+
+	}
+
+	if (origin != 0) {
+
+		if ((markup == MathML) && (builder.Len() > 0)) {
+
+			builder.WriteString("<mo>&#x2062;</mo>")
+
+		}
+
+		builder.WriteString(formatPower(variable, int64(origin), markup))
+
+	}
+
+	// repeated factors are written once and raised to their multiplicity
+	sums := []string{}
+	multiplicity := map[string]int64{}
+
+	for _, factor := range factors {
+
+		sum := formatSum(factor, variable, digits, markup)
+
+		if (multiplicity[sum] == 0) {
+
+			sums = append(sums, sum)
+
+		}
+
+		multiplicity[sum]++
+
+	}
+
+	if ((builder.Len() == 0) && (len(sums) == 1) && (multiplicity[sums[0]] == 1)) {
+
+		return sums[0]
+
+	}
+
+	for _, sum := range sums {
+
+		builder.WriteString(formatRaised(formatGroup(sum, markup), multiplicity[sum], markup))
+
+	}
+
+	return builder.String()
+
+}
+
+func (e *Expression) format(config Formatting) string {
+
+	variable := ""
+
+	if (len(e.Terms) > 0) {
+
+		variable = e.Terms[0].Variable
+
+	}
+
+	markup, digits, variable := parseFormatting(config, variable)
+	return formatEquation(config.Label, variable, formatSum(orderedTerms(*e), variable, digits, markup), markup)
+
+}
+
+func (p *Polynomial) format(config Formatting) string {
+
+	variable := ""
+
+	if (len(p.Denominator.Terms) > 0) {
+
+		variable = p.Denominator.Terms[0].Variable
+
+	}
+
+	markup, digits, variable := parseFormatting(config, variable)
+	numerator := formatSum(orderedTerms(p.Numerator), variable, digits, markup)
+	denominator := formatSum(orderedTerms(p.Denominator), variable, digits, markup)
+
+	if config.Factored {
+
+		numerator, denominator = p.factoredFormat(variable, digits, markup)
+
+	}
+
+	if (denominator == formatNumber(1.0, digits, markup)) {
+
+		return formatEquation(config.Label, variable, numerator, markup)
+
+	}
+
+	if (markup == ASCII) {
+
+		depth := 0
+
+		for _, character := range numerator {
+
+			if (character == '(') {
+
+				depth++
+
+			} else if (character == ')') {
+
+				depth--
+
+			} else if ((character == ' ') && (depth == 0)) {
+
+				numerator = formatGroup(numerator, markup)
+				break
+
+			}
+
+		}
+
+		depth = 0
+
+		// a single group raised to a power binds tighter than '/', anything else needs brackets
+		for index, character := range denominator {
+
+			if (character == '(') {
+
+				if ((depth == 0) && (index > 0)) {
+
+					denominator = formatGroup(denominator, markup)
+					break
+
+				}
+
+				depth++
+
+			} else if (character == ')') {
+
+				depth--
+
+			} else if ((character == ' ') && (depth == 0)) {
+
+				denominator = formatGroup(denominator, markup)
+				break
+
+			}
+
+		}
+
+	}
+
+	return formatEquation(config.Label, variable, formatFraction(numerator, denominator, markup), markup)
+
+}
+
+func (p *Polynomial) factoredFormat(variable string, digits int, markup Markup) (string, string) {
+
+	// factor coefficients come from root finding, so round away the last few bits by default
+	if (digits <= 0) {
+
+		digits = 12
+
+	}
+
+	if (p.domain() == Digital) {
+
+		// leading zeros are pure delays, carried as a single power of the variable like the analogue origin
+		numerator, denominator := p.digitalCoefficients()
+		zeroDelay, poleDelay := 0, 0
+
+		for ((len(numerator) > 1) && (numerator[0] == 0.0)) {
+
+			numerator = numerator[1:]
+			zeroDelay++
+
+		}
+
+		for ((len(denominator) > 1) && (denominator[0] == 0.0)) {
+
+			denominator = denominator[1:]
+			poleDelay++
+
+		}
+
+		top, _, zeroFactors := analogueFactors(numerator)
+		bottom, _, poleFactors := analogueFactors(denominator)
+		zeros := [][]Term{}
+		poles := [][]Term{}
+
+		for _, factor := range zeroFactors {
+
+			zeros = append(zeros, ascendingTerms(factor))
+
+		}
+
+		for _, factor := range poleFactors {
+
+			poles = append(poles, ascendingTerms(factor))
+
+		}
+
+		return formatProduct(top / bottom, poleDelay - zeroDelay, zeros, variable, digits, markup), formatProduct(1.0, 0, poles, variable, digits, markup)
+
+	}
+
+	numerator, denominator := p.analogueCoefficients()
+	top, zeroOrigin, zeroFactors := analogueFactors(numerator)
+	bottom, poleOrigin, poleFactors := analogueFactors(denominator)
+	zeros := [][]Term{}
+	poles := [][]Term{}
+
+	for _, factor := range zeroFactors {
+
+		zeros = append(zeros, descendingTerms(factor))
+
+	}
+
+	for _, factor := range poleFactors {
+
+		poles = append(poles, descendingTerms(factor))
+
+	}
+
+	common := min(zeroOrigin, poleOrigin)
+	return formatProduct(top / bottom, zeroOrigin - common, zeros, variable, digits, markup), formatProduct(1.0, poleOrigin - common, poles, variable, digits, markup)
+
+}
@@ -0,0 +1,139 @@
+package main
+
+import ( "strings"
+		 "testing" )
+
+
+func TestFormatNumber(t *testing.T) {
+
+	tests := []struct {
+
+		value  float64
+		digits int
+		markup Markup
+		want   string
+
+	}{
+
+		{ 0.5, -1, ASCII, "0.5" },
+		{ 3.14159, 3, ASCII, "3.14" },
+		{ 1.5e-7, 3, ASCII, "1.5e-07" },
+		{ 1.5e-7, 3, LaTeX, "1.5 \\times 10^{-7}" },
+		{ 1.5e-7, 3, Unicode, "1.5×10⁻⁷" },
+		{ 1.5e-7, 3, MathML, "<mn>1.5</mn><mo>&#xD7;</mo><msup><mn>10</mn><mn>-7</mn></msup>" },
+
+	}
+
+	for _, test := range tests {
+
+		if got := formatNumber(test.value, test.digits, test.markup); (got != test.want) {
+
+			t.Errorf("formatNumber(%g, %d, %s) = %q, want %q", test.value, test.digits, test.markup, got, test.want)
+
+		}
+
+	}
+
+}
+
+func TestFormatPolynomial(t *testing.T) {
+
+	three := 3
+	laurent := constructPolynomial(map[string]interface{}{ "variable": "s", "numerator": map[int64]float64{ -1: -0.83, 0: 3.72, 2: -4.09 }, "denominator": map[int64]float64{ 1: -1.23, 2: 16.24 } })
+	lowPass := analoguePolynomial([]float64{ 2.0, 0.0, 0.0 }, []float64{ 1.0, 3.2, 5.1, 4.2, 1.5 })
+	digital := digitalPolynomial([]float64{ 0.02, 0.04, 0.02 }, []float64{ 1.0, -1.56, 0.64 })
+
+	tests := []struct {
+
+		polynomial Polynomial
+		config	   Formatting
+		want	   string
+
+	}{
+
+		{ laurent, Formatting{ Markup: ASCII, Label: "H", Digits: &three }, "H(s) = (-4.09s^2 + 3.72 - 0.83s^(-1))/(16.2s^2 - 1.23s)" },
+		{ laurent, Formatting{ Markup: ASCII, Label: "H", Digits: &three, Factored: true }, "H(s) = -0.252(s - 0.238)(s - 0.812)(s + 1.05)/(s^2(s - 0.0757))" },
+		{ laurent, Formatting{ Markup: LaTeX, Label: "H", Digits: &three }, "H(s) = \\frac{-4.09s^{2} + 3.72 - 0.83s^{-1}}{16.2s^{2} - 1.23s}" },
+		{ laurent, Formatting{ Markup: LaTeX, Label: "H", Digits: &three, Factored: true }, "H(s) = \\frac{-0.252(s - 0.238)(s - 0.812)(s + 1.05)}{s^{2}(s - 0.0757)}" },
+		{ lowPass, Formatting{ Markup: ASCII, Digits: &three, Variable: "p" }, "2p^2/(p^4 + 3.2p^3 + 5.1p^2 + 4.2p + 1.5)" },
+		{ lowPass, Formatting{ Markup: ASCII, Digits: &three, Label: "G", Factored: true }, "G(s) = 2s^2/((s^2 + 1.74s + 0.908)(s^2 + 1.46s + 1.65))" },
+		{ digital, Formatting{ Markup: ASCII }, "(0.02 + 0.04z^(-1) + 0.02z^(-2))/(1 - 1.56z^(-1) + 0.64z^(-2))" },
+		{ digital, Formatting{ Markup: ASCII, Digits: &three, Factored: true }, "0.02(1 + z^(-1))^2/(1 - 1.56z^(-1) + 0.64z^(-2))" },
+		{ digital, Formatting{ Markup: LaTeX, Digits: &three, Factored: true }, "\\frac{0.02(1 + z^{-1})^{2}}{1 - 1.56z^{-1} + 0.64z^{-2}}" },
+		{ analoguePolynomial([]float64{ 1.5e-7 }, []float64{ 2.2e-9, 1e-4, 1.0 }), Formatting{ Markup: ASCII, Digits: &three }, "1.5e-07/(2.2e-09*s^2 + 0.0001s + 1)" },
+		{ analoguePolynomial([]float64{ 1.0, 0.0, 1.0 }, []float64{ 1.0, 1.0, 0.0 }), Formatting{ Markup: ASCII, Factored: true }, "(s^2 + 1)/(s(s + 1))" },
+		{ analoguePolynomial([]float64{ 1.0, 4.0, 6.0, 4.0, 1.0 }, []float64{ 1.0, 0.0, 2.0, 0.0, 1.0 }), Formatting{ Markup: ASCII, Factored: true }, "(s + 1)^4/(s^2 + 1)^2" },
+
+	}
+
+	for _, test := range tests {
+
+		if got := test.polynomial.format(test.config); (got != test.want) {
+
+			t.Errorf("format(%+v) = %q, want %q", test.config, got, test.want)
+
+		}
+
+	}
+
+}
+
+func TestFormatDelays(t *testing.T) {
+
+	// parsed z⁻¹ expressions keep their delays as single powers of z in every markup
+	tests := []struct {
+
+		text   string
+		markup Markup
+		want   string
+
+	}{
+
+		{ "(z^-1)^-1/(1+z^-1)", ASCII, "z/(1 + z^(-1))" },
+		{ "(z^-1)^-1/(1+z^-1)", LaTeX, "\\frac{z}{1 + z^{-1}}" },
+		{ "z^-2/(1 - 0.5z^-1)", ASCII, "z^(-2)/(1 - 0.5z^(-1))" },
+		{ "z^-2/(1 - 0.5z^-1)", LaTeX, "\\frac{z^{-2}}{1 - 0.5z^{-1}}" },
+		{ "(1+z^-1)^4/(1 - 0.5z^-1)^2", ASCII, "(1 + z^(-1))^4/(1 - 0.5z^(-1))^2" },
+		{ "(1+z^-1)^4/(1 - 0.5z^-1)^2", LaTeX, "\\frac{(1 + z^{-1})^{4}}{(1 - 0.5z^{-1})^{2}}" },
+		{ "(1+z^-1)^4/(1 - 0.5z^-1)^2", Unicode, "   (1 + z⁻¹)⁴\n---------------------\n  (1 - 0.5z⁻¹)²" },
+		{ "(s+1)^4/(s^2+s+1)", ASCII, "(s + 1)^4/(s^2 + s + 1)" },
+		{ "(s+1)^4/(s^2+s+1)", MathML, "<math xmlns=\"http://www.w3.org/1998/Math/MathML\"><mrow><mfrac><mrow><msup><mrow><mo>(</mo><mi>s</mi><mo>+</mo><mn>1</mn><mo>)</mo></mrow><mn>4</mn></msup></mrow><mrow><msup><mi>s</mi><mn>2</mn></msup><mo>+</mo><mi>s</mi><mo>+</mo><mn>1</mn></mrow></mfrac></mrow></math>" },
+
+	}
+
+	for _, test := range tests {
+
+		filter, err := parseTransferFunction(test.text)
+
+		if (err != nil) {
+
+			t.Fatalf("%q: %v", test.text, err)
+
+		}
+
+		if got := filter.format(Formatting{ Markup: test.markup, Factored: true }); (got != test.want) {
+
+			t.Errorf("%q in %s = %q, want %q", test.text, test.markup, got, test.want)
+
+		}
+
+	}
+
+}
+
+func TestFormatMathML(t *testing.T) {
+
+	filter := digitalPolynomial([]float64{ 0.02, 0.04, 0.02 }, []float64{ 1.0, -1.56, 0.64 })
+	got := filter.format(Formatting{ Markup: MathML, Label: "H" })
+
+	for _, fragment := range []string{ "<math xmlns=\"http://www.w3.org/1998/Math/MathML\">", "<mi>H</mi><mo>(</mo><mi>z</mi><mo>)</mo><mo>=</mo><mfrac>", "<msup><mi>z</mi><mrow><mo>-</mo><mn>2</mn></mrow></msup>", "</mfrac></mrow></math>" } {
+
+		if (!strings.Contains(got, fragment)) {
+
+			t.Errorf("%s does not contain %s", got, fragment)
+
+		}
+
+	}
+
+}
@@ -0,0 +1,410 @@
+package main
+
+import ( "fmt"
+		 "go/format"
+		 "strings" )
+
+
+func exportedIdentifier(name string) string {
+
+	builder := strings.Builder{}
+
+	for _, word := range strings.Split(identifier(name), "_") {
+
+		if (len(word) > 0) {
+
+			builder.WriteString(strings.ToUpper(word[:1]) + word[1:])
+
+		}
+
+	}
+
+	if (builder.Len() == 0) {
+
+		return "Filter"
+
+	}
+
+	return builder.String()
+
+}
+
+func packageName(config Codegen) string {
+
+	name := config.Package
+
+	if (name == "") {
+
+		name = config.Name
+
+	}
+
+	return strings.ReplaceAll(identifier(name), "_", "")
+
+}
+
+func goType(precision Precision, bits uint16) string {
+
+	switch precision {
+
+		case Single:
+
+			return "float32"
+
+		case Fixed:
+
+			return strings.TrimSuffix(integerType(bits), "_t")
+
+	}
+
+	return "float64"
+
+}
+
+func goLiteral(value float64, precision Precision) string {
+
+	if (precision == Single) {
+
+		return singleLiteral(value)
+
+	}
+
+	text := fmt.Sprintf("%.17g", value)
+
+	if (!strings.ContainsAny(text, ".eE")) {
+
+		text += ".0"
+
+	}
+
+	return text
+
+}
+
+func roundingGo(rounding Rounding) string {
+
+	switch rounding {
+
+		case Truncate:
+
+			return "\treturn value >> shift\n"
+
+		case TowardZero:
+
+			return "\tif value < 0 {\n\t\treturn -((-value) >> shift)\n\t}\n\treturn value >> shift\n"
+
+		case Convergent:
+
+			return "\thalf := int64(1) << (shift - 1)\n" +
+				   "\tremainder := value & (int64(1)<<shift - 1)\n" +
+				   "\tquotient := value >> shift\n" +
+				   "\tif remainder > half || (remainder == half && quotient&1 != 0) {\n" +
+				   "\t\tquotient++\n" +
+				   "\t}\n" +
+				   "\treturn quotient\n"
+
+		default:
+
+			return "\treturn (value + int64(1)<<(shift-1)) >> shift\n"
+
+	}
+
+}
+
+func goTable(builder *strings.Builder, name string, sampleType string, values []string) {
+
+	fmt.Fprintf(builder, "var %s = [...]%s{\n", name, sampleType)
+
+	for index := 0; index < len(values); index += 4 {
+
+		end := min(index + 4, len(values))
+		fmt.Fprintf(builder, "\t%s,\n", strings.Join(values[index:end], ", "))
+
+	}
+
+	builder.WriteString("}\n\n")
+
+}
+
+func formatGo(source string) string {
+
+	formatted, err := format.Source([]byte(source))
+
+	if (err != nil) {
+
+		return source
+
+	}
+
+	return string(formatted)
+
+}
+
+func generateGo(filter Polynomial, config Codegen) (string, string) {
+
+	name, precision, structure, datapath, samples := parseCodegen(config)
+	typeName := exportedIdentifier(config.Name)
+	prefix := strings.ToLower(typeName[:1]) + typeName[1:]
+	sampleType := goType(precision, 0)
+	sections := filter.sections()
+	numerator, denominator := filter.digitalCoefficients()
+	order := max(len(numerator), len(denominator))
+	numerator = append(numerator, make([]float64, order - len(numerator))...)
+	denominator = append(denominator, make([]float64, order - len(denominator))...)
+	stimulus := testStimulus(samples)
+	expected := make([]float64, samples)
+
+	if (structure != SecondOrderSections) {
+
+		structure = TransposedDirectFormII
+
+	}
+
+	if (precision == Fixed) {
+
+		fixed := newFixedPoint(sections, datapath)
+		datapath = fixed.Datapath
+		sampleType = goType(precision, datapath.Data.bits())
+		fixed.ProcessBlock(stimulus, expected)
+
+		for index := range stimulus {
+
+			q, _ := datapath.Data.quantize(stimulus[index], datapath.Rounding)
+			stimulus[index] = float64(q)
+			q, _ = datapath.Data.quantize(expected[index], datapath.Rounding)
+			expected[index] = float64(q)
+
+		}
+
+	} else if (structure == SecondOrderSections) {
+
+		newCascadeProcessor(sections).ProcessBlock(stimulus, expected)
+
+	} else {
+
+		newProcessor(filter, TransposedDirectFormII).ProcessBlock(stimulus, expected)
+
+	}
+
+	source := strings.Builder{}
+	fmt.Fprintf(&source, "// Code generated by splinter from design %q. DO NOT EDIT.\n\n", name)
+	fmt.Fprintf(&source, "package %s\n\n", packageName(config))
+	labels := []string{ "B0", "B1", "B2", "A1", "A2" }
+
+	constant := func(index int, label string) string {
+
+		return fmt.Sprintf("%sSection%d%s", prefix, index, label)
+
+	}
+
+	if (precision == Fixed) {
+
+		fixed := newFixedPoint(sections, datapath)
+		count := len(fixed.Sections)
+		fraction := datapath.Coefficient.Fraction
+		source.WriteString("const (\n")
+
+		for index, section := range fixed.Sections {
+
+			for k, value := range []int64{ section[0][0], section[0][1], section[0][2], section[1][1], section[1][2] } {
+
+				fmt.Fprintf(&source, "\t%s = %d\n", constant(index, labels[k]), value)
+
+			}
+
+		}
+
+		source.WriteString(")\n\n")
+		fmt.Fprintf(&source, "// %s is a %d-section fixed-point biquad cascade with Q%d.%d data and Q%d.%d coefficients.\n",
+					typeName, count, datapath.Data.Integer, datapath.Data.Fraction, datapath.Coefficient.Integer, fraction)
+		fmt.Fprintf(&source, "type %s struct {\n\tx [%d][2]%s\n\ty [%d][2]%s\n}\n\n", typeName, count, sampleType, count, sampleType)
+		fmt.Fprintf(&source, "func %sRoundShift(value int64, shift uint) int64 {\n%s}\n\n", prefix, roundingGo(datapath.Rounding))
+		fmt.Fprintf(&source, "func %sConstrain(value int64, width uint) int64 {\n", prefix)
+		source.WriteString("\tmaximum := int64(1)<<(width-1) - 1\n\tminimum := -(int64(1) << (width - 1))\n")
+
+		if (datapath.Overflow == Wrap) {
+
+			source.WriteString("\tif value > maximum || value < minimum {\n\t\treturn (value << (64 - width)) >> (64 - width)\n\t}\n")
+
+		} else {
+
+			source.WriteString("\tif value > maximum {\n\t\treturn maximum\n\t}\n\tif value < minimum {\n\t\treturn minimum\n\t}\n")
+
+		}
+
+		source.WriteString("\treturn value\n}\n\n")
+		product := "%s*%s"
+
+		if datapath.RoundProducts {
+
+			product = fmt.Sprintf("%sRoundShift(%%s*%%s, %d)<<%d", prefix, fraction, fraction)
+
+		}
+
+		fmt.Fprintf(&source, "// Process filters one sample.\nfunc (f *%s) Process(x %s) %s {\n", typeName, sampleType, sampleType)
+		source.WriteString("\tsample := int64(x)\n\tvar acc int64\n")
+
+		for index := 0; index < count; index++ {
+
+			terms := []string{
+				fmt.Sprintf(product, constant(index, "B0"), "sample"),
+				"acc + " + fmt.Sprintf(product, constant(index, "B1"), fmt.Sprintf("int64(f.x[%d][0])", index)),
+				"acc + " + fmt.Sprintf(product, constant(index, "B2"), fmt.Sprintf("int64(f.x[%d][1])", index)),
+				"acc - " + fmt.Sprintf(product, constant(index, "A1"), fmt.Sprintf("int64(f.y[%d][0])", index)),
+				"acc - " + fmt.Sprintf(product, constant(index, "A2"), fmt.Sprintf("int64(f.y[%d][1])", index)),
+			}
+
+			for _, term := range terms {
+
+				fmt.Fprintf(&source, "\tacc = %sConstrain(%s, %d)\n", prefix, term, datapath.Accumulator)
+
+			}
+
+			fmt.Fprintf(&source, "\ty%d := %sConstrain(%sRoundShift(acc, %d), %d)\n", index, prefix, prefix, fraction, datapath.Data.bits())
+			fmt.Fprintf(&source, "\tf.x[%d][1] = f.x[%d][0]\n\tf.x[%d][0] = %s(sample)\n", index, index, index, sampleType)
+			fmt.Fprintf(&source, "\tf.y[%d][1] = f.y[%d][0]\n\tf.y[%d][0] = %s(y%d)\n", index, index, index, sampleType, index)
+			fmt.Fprintf(&source, "\tsample = y%d\n", index)
+
+		}
+
+		fmt.Fprintf(&source, "\treturn %s(sample)\n}\n\n", sampleType)
+
+	} else if (structure == SecondOrderSections) {
+
+		count := len(sections)
+		source.WriteString("const (\n")
+
+		for index, section := range sections {
+
+			for k, value := range []float64{ section.Numerator[0], section.Numerator[1], section.Numerator[2], section.Denominator[1], section.Denominator[2] } {
+
+				fmt.Fprintf(&source, "\t%s = %s\n", constant(index, labels[k]), goLiteral(value, precision))
+
+			}
+
+		}
+
+		source.WriteString(")\n\n")
+		fmt.Fprintf(&source, "// %s is a %d-section biquad cascade in transposed direct form II.\n", typeName, count)
+		fmt.Fprintf(&source, "type %s struct {\n\tstate [%d][2]%s\n}\n\n", typeName, count, sampleType)
+		fmt.Fprintf(&source, "// Process filters one sample.\nfunc (f *%s) Process(x %s) %s {\n", typeName, sampleType, sampleType)
+
+		for index := 0; index < count; index++ {
+
+			fmt.Fprintf(&source, "\ty%d := %s*x + f.state[%d][0]\n", index, constant(index, "B0"), index)
+			fmt.Fprintf(&source, "\tf.state[%d][0] = %s*x - %s*y%d + f.state[%d][1]\n", index, constant(index, "B1"), constant(index, "A1"), index, index)
+			fmt.Fprintf(&source, "\tf.state[%d][1] = %s*x - %s*y%d\n", index, constant(index, "B2"), constant(index, "A2"), index)
+			fmt.Fprintf(&source, "\tx = y%d\n", index)
+
+		}
+
+		source.WriteString("\treturn x\n}\n\n")
+
+	} else {
+
+		source.WriteString("const (\n")
+
+		for index := range numerator {
+
+			fmt.Fprintf(&source, "\t%sNumerator%d = %s\n", prefix, index, goLiteral(numerator[index], precision))
+
+		}
+
+		for index := 1; index < order; index++ {
+
+			fmt.Fprintf(&source, "\t%sDenominator%d = %s\n", prefix, index, goLiteral(denominator[index], precision))
+
+		}
+
+		source.WriteString(")\n\n")
+		fmt.Fprintf(&source, "// %s is an order %d filter in transposed direct form II.\n", typeName, order - 1)
+		fmt.Fprintf(&source, "type %s struct {\n\tstate [%d]%s\n}\n\n", typeName, order - 1, sampleType)
+		fmt.Fprintf(&source, "// Process filters one sample.\nfunc (f *%s) Process(x %s) %s {\n", typeName, sampleType, sampleType)
+
+		if (order == 1) {
+
+			fmt.Fprintf(&source, "\treturn %sNumerator0*x\n}\n\n", prefix)
+
+		} else {
+
+			fmt.Fprintf(&source, "\ty := %sNumerator0*x + f.state[0]\n", prefix)
+
+			for index := 0; index < order - 1; index++ {
+
+				next := ""
+
+				if (index + 1 < order - 1) {
+
+					next = fmt.Sprintf(" + f.state[%d]", index + 1)
+
+				}
+
+				fmt.Fprintf(&source, "\tf.state[%d] = %sNumerator%d*x - %sDenominator%d*y%s\n", index, prefix, index + 1, prefix, index + 1, next)
+
+			}
+
+			source.WriteString("\treturn y\n}\n\n")
+
+		}
+
+	}
+
+	fmt.Fprintf(&source, "// ProcessBlock filters in into out, which must be at least as long as in.\n")
+	fmt.Fprintf(&source, "func (f *%s) ProcessBlock(in []%s, out []%s) {\n", typeName, sampleType, sampleType)
+	fmt.Fprintf(&source, "\tfor n, x := range in {\n\t\tout[n] = f.Process(x)\n\t}\n}\n\n")
+	fmt.Fprintf(&source, "// Reset clears the filter state.\nfunc (f *%s) Reset() {\n\t*f = %s{}\n}\n", typeName, typeName)
+
+	input := []string{}
+	output := []string{}
+
+	for index := range stimulus {
+
+		if (precision == Fixed) {
+
+			input = append(input, fmt.Sprintf("%d", int64(stimulus[index])))
+			output = append(output, fmt.Sprintf("%d", int64(expected[index])))
+
+		} else {
+
+			input = append(input, goLiteral(stimulus[index], precision))
+			output = append(output, goLiteral(expected[index], precision))
+
+		}
+
+	}
+
+	tolerance := "0"
+
+	if (precision == Single) {
+
+		tolerance = "1e-4"
+
+	} else if (precision == Double) {
+
+		tolerance = "1e-9"
+
+	}
+
+	test := strings.Builder{}
+	fmt.Fprintf(&test, "// Code generated by splinter from design %q. DO NOT EDIT.\n\n", name)
+	fmt.Fprintf(&test, "package %s\n\nimport \"testing\"\n\n", packageName(config))
+	goTable(&test, prefix + "TestInput", sampleType, input)
+	goTable(&test, prefix + "TestOutput", sampleType, output)
+	fmt.Fprintf(&test, "func Test%sReference(t *testing.T) {\n\tvar f %s\n", typeName, typeName)
+	fmt.Fprintf(&test, "\tfor n, x := range %sTestInput {\n\t\ty := f.Process(x)\n", prefix)
+	fmt.Fprintf(&test, "\t\tdifference := float64(y) - float64(%sTestOutput[n])\n", prefix)
+	fmt.Fprintf(&test, "\t\tif difference > %s || difference < -%s {\n", tolerance, tolerance)
+	fmt.Fprintf(&test, "\t\t\tt.Fatalf(\"sample %%d: got %%v, want %%v\", n, y, %sTestOutput[n])\n\t\t}\n\t}\n}\n\n", prefix)
+	fmt.Fprintf(&test, "func Test%sReset(t *testing.T) {\n\tvar f %s\n", typeName, typeName)
+	fmt.Fprintf(&test, "\tfirst := make([]%s, len(%sTestInput))\n\tsecond := make([]%s, len(%sTestInput))\n", sampleType, prefix, sampleType, prefix)
+	fmt.Fprintf(&test, "\tf.ProcessBlock(%sTestInput[:], first)\n\tf.Reset()\n\tf.ProcessBlock(%sTestInput[:], second)\n", prefix, prefix)
+	fmt.Fprintf(&test, "\tfor n := range first {\n\t\tif first[n] != second[n] {\n")
+	fmt.Fprintf(&test, "\t\t\tt.Fatalf(\"sample %%d differs after reset: %%v != %%v\", n, first[n], second[n])\n\t\t}\n\t}\n}\n\n")
+	fmt.Fprintf(&test, "func Test%sAllocations(t *testing.T) {\n\tvar f %s\n", typeName, typeName)
+	fmt.Fprintf(&test, "\tout := make([]%s, len(%sTestInput))\n", sampleType, prefix)
+	fmt.Fprintf(&test, "\tallocations := testing.AllocsPerRun(100, func() {\n\t\tf.ProcessBlock(%sTestInput[:], out)\n\t})\n", prefix)
+	fmt.Fprintf(&test, "\tif allocations != 0 {\n\t\tt.Fatalf(\"ProcessBlock allocated %%v times per run\", allocations)\n\t}\n}\n")
+
+	return formatGo(source.String()), formatGo(test.String())
+
+}
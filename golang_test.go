@@ -0,0 +1,90 @@
+package main
+
+import ( "os"
+		 "os/exec"
+		 "path/filepath"
+		 "testing" )
+
+
+func TestGoNames(t *testing.T) {
+
+	for name, want := range map[string]string{ "low pass": "LowPass", "band-stop 2": "BandStop2", "": "Filter" } {
+
+		if got := exportedIdentifier(name); (got != want) {
+
+			t.Errorf("exportedIdentifier(%q) = %q, want %q", name, got, want)
+
+		}
+
+	}
+
+	if got := packageName(Codegen{ Name: "low pass" }); (got != "lowpass") {
+
+		t.Errorf("package = %q, want lowpass", got)
+
+	}
+
+	if got := packageName(Codegen{ Name: "low pass", Package: "dsp" }); (got != "dsp") {
+
+		t.Errorf("package = %q, want dsp", got)
+
+	}
+
+	for _, test := range []struct{ precision Precision; bits uint16; want string }{ { Double, 0, "float64" }, { Single, 0, "float32" }, { Fixed, 16, "int16" }, { Fixed, 32, "int32" } } {
+
+		if got := goType(test.precision, test.bits); (got != test.want) {
+
+			t.Errorf("goType(%s, %d) = %s, want %s", test.precision, test.bits, got, test.want)
+
+		}
+
+	}
+
+}
+
+func TestGenerateGo(t *testing.T) {
+
+	if testing.Short() {
+
+		t.Skip("builds a separate module for every generated file")
+
+	}
+
+	filter := digitalPolynomial(equalizerNumerator, equalizerDenominator)
+
+	configs := []Codegen{
+
+		{ Name: "sections", Precision: Double },
+		{ Name: "single", Package: "dsp", Precision: Single },
+		{ Name: "transposed", Structure: TransposedDirectFormII },
+		{ Name: "fixed", Precision: Fixed },
+		{ Name: "wrapped", Precision: Fixed, Datapath: Datapath{ Overflow: Wrap, Rounding: Convergent, RoundProducts: true, Accumulator: 32 } },
+		{ Name: "wide", Precision: Fixed, Datapath: Datapath{ Coefficient: Format{ Integer: 1, Fraction: 30 }, Data: Format{ Integer: 0, Fraction: 31 }, Rounding: TowardZero } },
+
+	}
+
+	for _, config := range configs {
+
+		// the generated test replays the reference outputs against the frozen filter
+		source, test := generateGo(filter, config)
+		directory := t.TempDir()
+		os.WriteFile(filepath.Join(directory, "go.mod"), []byte("module generated\n\ngo 1.21\n"), 0644)
+		os.WriteFile(filepath.Join(directory, "filter.go"), []byte(source), 0644)
+		os.WriteFile(filepath.Join(directory, "filter_test.go"), []byte(test), 0644)
+
+		for _, arguments := range [][]string{ { "vet", "." }, { "test", "-count=1", "." } } {
+
+			command := exec.Command("go", arguments...)
+			command.Dir = directory
+
+			if output, err := command.CombinedOutput(); (err != nil) {
+
+				t.Errorf("%s: go %s failed: %v\n%s", config.Name, arguments[0], err, output)
+
+			}
+
+		}
+
+	}
+
+}
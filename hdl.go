@@ -0,0 +1,951 @@
+package main
+
+import ( "fmt"
+		 "math"
+		 "strings" )
+
+
+type hdlWidths struct {
+
+	data		int
+	coefficient int
+	accumulator int
+	working		int
+	fraction	int
+
+}
+
+func verilogLiteral(value int64, width int) string {
+
+	mask := uint64(math.MaxUint64)
+
+	if (width < 64) {
+
+		mask = (uint64(1) << width) - 1
+
+	}
+
+	return fmt.Sprintf("%d'sh%X", width, uint64(value) & mask)
+
+}
+
+func vhdlLiteral(value int64, width int) string {
+
+	bits := make([]byte, width)
+
+	for index := range bits {
+
+		bits[width - index - 1] = '0' + byte((uint64(value) >> index) & 1)
+
+	}
+
+	return "\"" + string(bits) + "\""
+
+}
+
+func parseHDL(config Codegen, growth int) (string, Language, Datapath, int) {
+
+	language := Verilog
+
+	if config.Language.exists() {
+
+		language = config.Language
+
+	}
+
+	datapath := config.Datapath
+
+	if (datapath.Data.bits() == 1) {
+
+		datapath.Data = Format{ Integer: 0, Fraction: 15 }
+
+	}
+
+	if (datapath.Coefficient.bits() == 1) {
+
+		datapath.Coefficient = Format{ Integer: 1, Fraction: 14 }
+
+	}
+
+	if (!datapath.Rounding.exists()) {
+
+		datapath.Rounding = Nearest
+
+	}
+
+	if (!datapath.Overflow.exists()) {
+
+		datapath.Overflow = Saturate
+
+	}
+
+	if (datapath.Accumulator == 0) {
+
+		datapath.Accumulator = datapath.Data.bits() + datapath.Coefficient.bits() + uint16(growth)
+
+	}
+
+	datapath.Accumulator = min(datapath.Accumulator, 62)
+	samples := config.Samples
+
+	if (samples <= 0) {
+
+		samples = 256
+
+	}
+
+	return identifier(config.Name), language, datapath, samples
+
+}
+
+func widthsOf(datapath Datapath) hdlWidths {
+
+	data := int(datapath.Data.bits())
+	coefficient := int(datapath.Coefficient.bits())
+	accumulator := int(datapath.Accumulator)
+
+	return hdlWidths{
+
+		data: data,
+		coefficient: coefficient,
+		accumulator: accumulator,
+		working: max(accumulator, data + coefficient) + 2,
+		fraction: int(datapath.Coefficient.Fraction),
+
+	}
+
+}
+
+func quantizeStimulus(datapath Datapath, samples int) []int64 {
+
+	stimulus := []int64{}
+
+	for _, value := range testStimulus(samples) {
+
+		q, _ := datapath.Data.quantize(value, datapath.Rounding)
+		stimulus = append(stimulus, q)
+
+	}
+
+	return stimulus
+
+}
+
+func simulateFixedFIR(coefficients []int64, datapath Datapath, input []int64, structure Structure) []int64 {
+
+	f := &FixedPoint{ Datapath: datapath }
+	count := len(coefficients)
+	history := make([]int64, count)
+	output := make([]int64, len(input))
+
+	for n, x := range input {
+
+		copy(history[1:], history)
+		history[0] = x
+		accumulator := int64(0)
+
+		switch structure {
+
+			case TransposedDirectFormII:
+
+				for k := count - 1; k >= 0; k-- {
+
+					accumulator = f.constrain(accumulator + coefficients[k]*history[k], datapath.Accumulator)
+
+				}
+
+			case SymmetricFolded:
+
+				for k := 0; k < count / 2; k++ {
+
+					accumulator = f.constrain(accumulator + coefficients[k]*(history[k] + history[count - k - 1]), datapath.Accumulator)
+
+				}
+
+				if (count%2 == 1) {
+
+					accumulator = f.constrain(accumulator + coefficients[count / 2]*history[count / 2], datapath.Accumulator)
+
+				}
+
+			default:
+
+				for k, coefficient := range coefficients {
+
+					accumulator = f.constrain(accumulator + coefficient*history[k], datapath.Accumulator)
+
+				}
+
+		}
+
+		rounded := shiftRound(accumulator, datapath.Coefficient.Fraction, datapath.Rounding)
+		output[n] = f.constrain(rounded, datapath.Data.bits())
+
+	}
+
+	return output
+
+}
+
+func verilogFunctions(builder *strings.Builder, widths hdlWidths, datapath Datapath) {
+
+	ww := widths.working
+	fmt.Fprintf(builder, "    localparam signed [%d:0] ACC_MAX = %s;\n", ww - 1, verilogLiteral(int64(1) << (widths.accumulator - 1) - 1, ww))
+	fmt.Fprintf(builder, "    localparam signed [%d:0] ACC_MIN = %s;\n", ww - 1, verilogLiteral(-(int64(1) << (widths.accumulator - 1)), ww))
+	fmt.Fprintf(builder, "    localparam signed [%d:0] DATA_MAX = %s;\n", ww - 1, verilogLiteral(int64(1) << (widths.data - 1) - 1, ww))
+	fmt.Fprintf(builder, "    localparam signed [%d:0] DATA_MIN = %s;\n\n", ww - 1, verilogLiteral(-(int64(1) << (widths.data - 1)), ww))
+
+	for _, target := range []string{ "acc", "data" } {
+
+		width := widths.accumulator
+		bound := "ACC"
+
+		if (target == "data") {
+
+			width = widths.data
+			bound = "DATA"
+
+		}
+
+		fmt.Fprintf(builder, "    function signed [%d:0] fit_%s;\n        input signed [%d:0] v;\n        begin\n", ww - 1, target, ww - 1)
+
+		if (datapath.Overflow == Wrap) {
+
+			fmt.Fprintf(builder, "            fit_%s = {{%d{v[%d]}}, v[%d:0]};\n", target, ww - width, width - 1, width - 1)
+
+		} else {
+
+			fmt.Fprintf(builder, "            if (v > %s_MAX)\n                fit_%s = %s_MAX;\n", bound, target, bound)
+			fmt.Fprintf(builder, "            else if (v < %s_MIN)\n                fit_%s = %s_MIN;\n", bound, target, bound)
+			fmt.Fprintf(builder, "            else\n                fit_%s = v;\n", target)
+
+		}
+
+		fmt.Fprintf(builder, "        end\n    endfunction\n\n")
+
+	}
+
+	fmt.Fprintf(builder, "    function signed [%d:0] round_shift;\n        input signed [%d:0] v;\n        reg signed [%d:0] q;\n        begin\n", ww - 1, ww - 1, ww - 1)
+	f := widths.fraction
+
+	if (f == 0) {
+
+		fmt.Fprintf(builder, "            q = v;\n")
+
+	} else {
+
+		switch datapath.Rounding {
+
+			case Truncate:
+
+				fmt.Fprintf(builder, "            q = v >>> %d;\n", f)
+
+			case TowardZero:
+
+				fmt.Fprintf(builder, "            q = (v < 0) ? -((-v) >>> %d) : (v >>> %d);\n", f, f)
+
+			case Convergent:
+
+				fmt.Fprintf(builder, "            q = v >>> %d;\n", f)
+				fmt.Fprintf(builder, "            if ((v[%d:0] > %d'd%d) || ((v[%d:0] == %d'd%d) && q[0]))\n", f - 1, f, int64(1) << (f - 1), f - 1, f, int64(1) << (f - 1))
+				fmt.Fprintf(builder, "                q = q + 1;\n")
+
+			default:
+
+				fmt.Fprintf(builder, "            q = (v + %s) >>> %d;\n", verilogLiteral(int64(1) << (f - 1), ww), f)
+
+		}
+
+	}
+
+	fmt.Fprintf(builder, "            round_shift = q;\n        end\n    endfunction\n\n")
+	fmt.Fprintf(builder, "    function signed [%d:0] product;\n        input signed [%d:0] c;\n        input signed [%d:0] x;\n        begin\n", ww - 1, widths.coefficient - 1, widths.data - 1)
+
+	if datapath.RoundProducts {
+
+		fmt.Fprintf(builder, "            product = c * x;\n            product = round_shift(product) <<< %d;\n", f)
+
+	} else {
+
+		fmt.Fprintf(builder, "            product = c * x;\n")
+
+	}
+
+	fmt.Fprintf(builder, "        end\n    endfunction\n\n")
+
+}
+
+func vhdlFunctions(builder *strings.Builder, widths hdlWidths, datapath Datapath) {
+
+	ww := widths.working
+	fmt.Fprintf(builder, "    constant ACC_MAX : signed(%d downto 0) := %s;\n", ww - 1, vhdlLiteral(int64(1) << (widths.accumulator - 1) - 1, ww))
+	fmt.Fprintf(builder, "    constant ACC_MIN : signed(%d downto 0) := %s;\n", ww - 1, vhdlLiteral(-(int64(1) << (widths.accumulator - 1)), ww))
+	fmt.Fprintf(builder, "    constant DATA_MAX : signed(%d downto 0) := %s;\n", ww - 1, vhdlLiteral(int64(1) << (widths.data - 1) - 1, ww))
+	fmt.Fprintf(builder, "    constant DATA_MIN : signed(%d downto 0) := %s;\n\n", ww - 1, vhdlLiteral(-(int64(1) << (widths.data - 1)), ww))
+
+	for _, target := range []string{ "acc", "data" } {
+
+		width := widths.accumulator
+		bound := "ACC"
+
+		if (target == "data") {
+
+			width = widths.data
+			bound = "DATA"
+
+		}
+
+		fmt.Fprintf(builder, "    function fit_%s(v : signed(%d downto 0)) return signed is\n    begin\n", target, ww - 1)
+
+		if (datapath.Overflow == Wrap) {
+
+			fmt.Fprintf(builder, "        return resize(v(%d downto 0), %d);\n", width - 1, ww)
+
+		} else {
+
+			fmt.Fprintf(builder, "        if v > %s_MAX then\n            return %s_MAX;\n", bound, bound)
+			fmt.Fprintf(builder, "        elsif v < %s_MIN then\n            return %s_MIN;\n", bound, bound)
+			fmt.Fprintf(builder, "        end if;\n        return v;\n")
+
+		}
+
+		fmt.Fprintf(builder, "    end function;\n\n")
+
+	}
+
+	f := widths.fraction
+	fmt.Fprintf(builder, "    function round_shift(v : signed(%d downto 0)) return signed is\n", ww - 1)
+	fmt.Fprintf(builder, "        variable q : signed(%d downto 0);\n    begin\n", ww - 1)
+
+	if (f == 0) {
+
+		fmt.Fprintf(builder, "        q := v;\n")
+
+	} else {
+
+		switch datapath.Rounding {
+
+			case Truncate:
+
+				fmt.Fprintf(builder, "        q := shift_right(v, %d);\n", f)
+
+			case TowardZero:
+
+				fmt.Fprintf(builder, "        if v < 0 then\n            q := -shift_right(-v, %d);\n", f)
+				fmt.Fprintf(builder, "        else\n            q := shift_right(v, %d);\n        end if;\n", f)
+
+			case Convergent:
+
+				half := vhdlLiteral(int64(1) << (f - 1), f)
+				fmt.Fprintf(builder, "        q := shift_right(v, %d);\n", f)
+				fmt.Fprintf(builder, "        if (unsigned(v(%d downto 0)) > unsigned'(%s)) or\n", f - 1, half)
+				fmt.Fprintf(builder, "           ((unsigned(v(%d downto 0)) = unsigned'(%s)) and (q(0) = '1')) then\n", f - 1, half)
+				fmt.Fprintf(builder, "            q := q + 1;\n        end if;\n")
+
+			default:
+
+				fmt.Fprintf(builder, "        q := shift_right(v + signed'(%s), %d);\n", vhdlLiteral(int64(1) << (f - 1), ww), f)
+
+		}
+
+	}
+
+	fmt.Fprintf(builder, "        return q;\n    end function;\n\n")
+	fmt.Fprintf(builder, "    function product(c : signed(%d downto 0); x : signed(%d downto 0)) return signed is\n", widths.coefficient - 1, widths.data - 1)
+	fmt.Fprintf(builder, "        variable p : signed(%d downto 0);\n    begin\n        p := resize(c * x, %d);\n", ww - 1, ww)
+
+	if datapath.RoundProducts {
+
+		fmt.Fprintf(builder, "        p := shift_left(round_shift(p), %d);\n", f)
+
+	}
+
+	fmt.Fprintf(builder, "        return p;\n    end function;\n\n")
+
+}
+
+func verilogTestbench(name string, widths hdlWidths, stimulus []int64, expected []int64) string {
+
+	builder := strings.Builder{}
+	dw := widths.data
+	fmt.Fprintf(&builder, "`timescale 1ns / 1ps\n\nmodule %s_tb;\n\n", name)
+	fmt.Fprintf(&builder, "    reg clk = 1'b0;\n    reg rst = 1'b1;\n    reg in_valid = 1'b0;\n    reg signed [%d:0] in_data = 0;\n", dw - 1)
+	fmt.Fprintf(&builder, "    wire out_valid;\n    wire signed [%d:0] out_data;\n\n", dw - 1)
+	fmt.Fprintf(&builder, "    reg signed [%d:0] stimulus [0:%d];\n    reg signed [%d:0] expected [0:%d];\n", dw - 1, len(stimulus) - 1, dw - 1, len(expected) - 1)
+	fmt.Fprintf(&builder, "    integer n;\n    integer errors = 0;\n    integer received = 0;\n\n")
+	fmt.Fprintf(&builder, "    %s dut (\n        .clk(clk),\n        .rst(rst),\n        .in_valid(in_valid),\n        .in_data(in_data),\n", name)
+	fmt.Fprintf(&builder, "        .out_valid(out_valid),\n        .out_data(out_data)\n    );\n\n    always #5 clk = ~clk;\n\n")
+	fmt.Fprintf(&builder, "    initial begin\n")
+
+	for index := range stimulus {
+
+		fmt.Fprintf(&builder, "        stimulus[%d] = %s; expected[%d] = %s;\n", index, verilogLiteral(stimulus[index], dw), index, verilogLiteral(expected[index], dw))
+
+	}
+
+	fmt.Fprintf(&builder, "        @(posedge clk);\n        @(posedge clk);\n        rst <= 1'b0;\n")
+	fmt.Fprintf(&builder, "        for (n = 0; n < %d; n = n + 1) begin\n            @(posedge clk);\n", len(stimulus))
+	fmt.Fprintf(&builder, "            in_valid <= 1'b1;\n            in_data <= stimulus[n];\n        end\n")
+	fmt.Fprintf(&builder, "        @(posedge clk);\n        in_valid <= 1'b0;\n        repeat (4) @(posedge clk);\n")
+	fmt.Fprintf(&builder, "        if ((errors == 0) && (received == %d))\n            $display(\"PASS\");\n", len(expected))
+	fmt.Fprintf(&builder, "        else\n            $display(\"FAIL: %%0d errors, %%0d samples\", errors, received);\n        $finish;\n    end\n\n")
+	fmt.Fprintf(&builder, "    always @(posedge clk) begin\n        if (out_valid === 1'b1) begin\n")
+	fmt.Fprintf(&builder, "            if (out_data !== expected[received]) begin\n                errors = errors + 1;\n")
+	fmt.Fprintf(&builder, "                $display(\"mismatch at %%0d: got %%0d expected %%0d\", received, out_data, expected[received]);\n")
+	fmt.Fprintf(&builder, "            end\n            received = received + 1;\n        end\n    end\n\nendmodule\n")
+	return builder.String()
+
+}
+
+func vhdlTestbench(name string, widths hdlWidths, stimulus []int64, expected []int64) string {
+
+	builder := strings.Builder{}
+	dw := widths.data
+	fmt.Fprintf(&builder, "library ieee;\nuse ieee.std_logic_1164.all;\nuse ieee.numeric_std.all;\n\n")
+	fmt.Fprintf(&builder, "entity %s_tb is\nend entity;\n\narchitecture sim of %s_tb is\n\n", name, name)
+	fmt.Fprintf(&builder, "    constant LENGTH : natural := %d;\n", len(stimulus))
+	fmt.Fprintf(&builder, "    type sample_array is array (0 to LENGTH - 1) of signed(%d downto 0);\n\n", dw - 1)
+
+	for _, table := range []struct{ label string; values []int64 }{ { "STIMULUS", stimulus }, { "EXPECTED", expected } } {
+
+		entries := []string{}
+
+		for index, value := range table.values {
+
+			entries = append(entries, fmt.Sprintf("%d => %s", index, vhdlLiteral(value, dw)))
+
+		}
+
+		fmt.Fprintf(&builder, "    constant %s : sample_array := (\n        %s\n    );\n\n", table.label, strings.Join(entries, ",\n        "))
+
+	}
+
+	fmt.Fprintf(&builder, "    signal clk : std_logic := '0';\n    signal rst : std_logic := '1';\n    signal in_valid : std_logic := '0';\n")
+	fmt.Fprintf(&builder, "    signal in_data : signed(%d downto 0) := (others => '0');\n    signal out_valid : std_logic;\n", dw - 1)
+	fmt.Fprintf(&builder, "    signal out_data : signed(%d downto 0);\n    signal done : boolean := false;\n\nbegin\n\n", dw - 1)
+	fmt.Fprintf(&builder, "    dut : entity work.%s\n        port map (\n            clk => clk,\n            rst => rst,\n            in_valid => in_valid,\n", name)
+	fmt.Fprintf(&builder, "            in_data => in_data,\n            out_valid => out_valid,\n            out_data => out_data\n        );\n\n")
+	fmt.Fprintf(&builder, "    clk <= not clk after 5 ns when not done else '0';\n\n")
+	fmt.Fprintf(&builder, "    drive : process\n    begin\n        wait until rising_edge(clk);\n        wait until rising_edge(clk);\n        rst <= '0';\n")
+	fmt.Fprintf(&builder, "        for n in 0 to LENGTH - 1 loop\n            wait until rising_edge(clk);\n            in_valid <= '1';\n            in_data <= STIMULUS(n);\n        end loop;\n")
+	fmt.Fprintf(&builder, "        wait until rising_edge(clk);\n        in_valid <= '0';\n        wait;\n    end process;\n\n")
+	fmt.Fprintf(&builder, "    check : process\n        variable received : natural := 0;\n        variable errors : natural := 0;\n    begin\n")
+	fmt.Fprintf(&builder, "        wait until rising_edge(clk);\n        if out_valid = '1' then\n")
+	fmt.Fprintf(&builder, "            if out_data /= EXPECTED(received) then\n                errors := errors + 1;\n")
+	fmt.Fprintf(&builder, "                report \"mismatch at \" & integer'image(received) severity error;\n            end if;\n")
+	fmt.Fprintf(&builder, "            received := received + 1;\n            if received = LENGTH then\n")
+	fmt.Fprintf(&builder, "                if errors = 0 then\n                    report \"PASS\" severity note;\n                else\n")
+	fmt.Fprintf(&builder, "                    report \"FAIL: \" & integer'image(errors) & \" errors\" severity failure;\n                end if;\n")
+	fmt.Fprintf(&builder, "                done <= true;\n                wait;\n            end if;\n        end if;\n    end process;\n\nend architecture;\n")
+	return builder.String()
+
+}
+
+func hdlHeader(builder *strings.Builder, name string, language Language, widths hdlWidths) {
+
+	if (language == VHDL) {
+
+		fmt.Fprintf(builder, "library ieee;\nuse ieee.std_logic_1164.all;\nuse ieee.numeric_std.all;\n\n")
+		fmt.Fprintf(builder, "entity %s is\n    port (\n        clk : in std_logic;\n        rst : in std_logic;\n        in_valid : in std_logic;\n", name)
+		fmt.Fprintf(builder, "        in_data : in signed(%d downto 0);\n        out_valid : out std_logic;\n", widths.data - 1)
+		fmt.Fprintf(builder, "        out_data : out signed(%d downto 0)\n    );\nend entity;\n\narchitecture rtl of %s is\n\n", widths.data - 1, name)
+		return
+
+	}
+
+	fmt.Fprintf(builder, "module %s (\n    input wire clk,\n    input wire rst,\n    input wire in_valid,\n", name)
+	fmt.Fprintf(builder, "    input wire signed [%d:0] in_data,\n    output reg out_valid,\n", widths.data - 1)
+	fmt.Fprintf(builder, "    output reg signed [%d:0] out_data\n);\n\n", widths.data - 1)
+
+}
+
+func generateFIRHDL(taps []float64, config Codegen) (string, string) {
+
+	structure := DirectFormI
+
+	if ((config.Structure == TransposedDirectFormII) ||
+		(config.Structure == SymmetricFolded)) {
+
+		structure = config.Structure
+
+	}
+
+	growth := int(math.Ceil(math.Log2(float64(max(len(taps), 2)))))
+	name, language, datapath, samples := parseHDL(config, growth)
+	widths := widthsOf(datapath)
+	coefficients := make([]int64, len(taps))
+
+	for index, tap := range taps {
+
+		coefficients[index], _ = datapath.Coefficient.quantize(tap, datapath.Rounding)
+
+	}
+
+	if (structure == SymmetricFolded) {
+
+		for index := range coefficients {
+
+			if (coefficients[index] != coefficients[len(coefficients) - index - 1]) {
+
+				structure = DirectFormI
+				break
+
+			}
+
+		}
+
+	}
+
+	stimulus := quantizeStimulus(datapath, samples)
+	expected := simulateFixedFIR(coefficients, datapath, stimulus, structure)
+	count := len(coefficients)
+	ww := widths.working
+	dw := widths.data
+	builder := strings.Builder{}
+	hdlHeader(&builder, name, language, widths)
+
+	sample := func(k int) string {
+
+		if (k == 0) {
+
+			return "in_data"
+
+		}
+
+		return fmt.Sprintf("delay_%d", k)
+
+	}
+
+	terms := []string{}
+
+	if (structure == SymmetricFolded) {
+
+		for k := 0; k < count / 2; k++ {
+
+			if (language == VHDL) {
+
+				terms = append(terms, fmt.Sprintf("resize(C_%d * (resize(%s, %d) + resize(%s, %d)), %d)", k, sample(k), dw + 1, sample(count - k - 1), dw + 1, ww))
+
+			} else {
+
+				terms = append(terms, fmt.Sprintf("C_%d * (%s + %s)", k, sample(k), sample(count - k - 1)))
+
+			}
+
+		}
+
+		if (count%2 == 1) {
+
+			terms = append(terms, fmt.Sprintf("C_%d * %s", count / 2, sample(count / 2)))
+
+			if (language == VHDL) {
+
+				terms[len(terms) - 1] = fmt.Sprintf("resize(%s, %d)", terms[len(terms) - 1], ww)
+
+			}
+
+		}
+
+	} else if (structure == DirectFormI) {
+
+		for k := 0; k < count; k++ {
+
+			if (language == VHDL) {
+
+				terms = append(terms, fmt.Sprintf("resize(C_%d * %s, %d)", k, sample(k), ww))
+
+			} else {
+
+				terms = append(terms, fmt.Sprintf("C_%d * %s", k, sample(k)))
+
+			}
+
+		}
+
+	} else {
+
+		if (language == VHDL) {
+
+			terms = append(terms, fmt.Sprintf("resize(C_0 * in_data, %d)", ww))
+
+		} else {
+
+			terms = append(terms, "C_0 * in_data")
+
+		}
+
+		if (count > 1) {
+
+			terms = append(terms, "partial_0")
+
+		}
+
+	}
+
+	if (language == VHDL) {
+
+		for k, coefficient := range coefficients {
+
+			fmt.Fprintf(&builder, "    constant C_%d : signed(%d downto 0) := %s;\n", k, widths.coefficient - 1, vhdlLiteral(coefficient, widths.coefficient))
+
+		}
+
+		builder.WriteString("\n")
+
+		if (structure == TransposedDirectFormII) {
+
+			for k := 0; k < count - 1; k++ {
+
+				fmt.Fprintf(&builder, "    signal partial_%d : signed(%d downto 0) := (others => '0');\n", k, ww - 1)
+
+			}
+
+		} else {
+
+			for k := 1; k < count; k++ {
+
+				fmt.Fprintf(&builder, "    signal delay_%d : signed(%d downto 0) := (others => '0');\n", k, dw - 1)
+
+			}
+
+		}
+
+		builder.WriteString("\n")
+		vhdlFunctions(&builder, widths, datapath)
+		fmt.Fprintf(&builder, "begin\n\n    process (clk)\n        variable acc : signed(%d downto 0);\n    begin\n", ww - 1)
+		fmt.Fprintf(&builder, "        if rising_edge(clk) then\n            if rst = '1' then\n")
+		fmt.Fprintf(&builder, "                out_valid <= '0';\n                out_data <= (others => '0');\n")
+
+		if (structure == TransposedDirectFormII) {
+
+			for k := 0; k < count - 1; k++ {
+
+				fmt.Fprintf(&builder, "                partial_%d <= (others => '0');\n", k)
+
+			}
+
+		} else {
+
+			for k := 1; k < count; k++ {
+
+				fmt.Fprintf(&builder, "                delay_%d <= (others => '0');\n", k)
+
+			}
+
+		}
+
+		fmt.Fprintf(&builder, "            else\n                out_valid <= in_valid;\n                if in_valid = '1' then\n")
+		fmt.Fprintf(&builder, "                    acc := (others => '0');\n")
+
+		for _, term := range terms {
+
+			fmt.Fprintf(&builder, "                    acc := fit_acc(acc + %s);\n", term)
+
+		}
+
+		fmt.Fprintf(&builder, "                    out_data <= resize(fit_data(round_shift(acc)), %d);\n", dw)
+
+		if (structure == TransposedDirectFormII) {
+
+			for k := 0; k < count - 1; k++ {
+
+				next := ""
+
+				if (k + 1 < count - 1) {
+
+					next = fmt.Sprintf(" + partial_%d", k + 1)
+
+				}
+
+				fmt.Fprintf(&builder, "                    partial_%d <= fit_acc(resize(C_%d * in_data, %d)%s);\n", k, k + 1, ww, next)
+
+			}
+
+		} else {
+
+			for k := count - 1; k >= 1; k-- {
+
+				fmt.Fprintf(&builder, "                    delay_%d <= %s;\n", k, sample(k - 1))
+
+			}
+
+		}
+
+		fmt.Fprintf(&builder, "                end if;\n            end if;\n        end if;\n    end process;\n\nend architecture;\n")
+		return builder.String(), vhdlTestbench(name, widths, stimulus, expected)
+
+	}
+
+	for k, coefficient := range coefficients {
+
+		fmt.Fprintf(&builder, "    localparam signed [%d:0] C_%d = %s;\n", widths.coefficient - 1, k, verilogLiteral(coefficient, widths.coefficient))
+
+	}
+
+	builder.WriteString("\n")
+	verilogFunctions(&builder, widths, datapath)
+
+	if (structure == TransposedDirectFormII) {
+
+		for k := 0; k < count - 1; k++ {
+
+			fmt.Fprintf(&builder, "    reg signed [%d:0] partial_%d;\n", ww - 1, k)
+
+		}
+
+	} else {
+
+		for k := 1; k < count; k++ {
+
+			fmt.Fprintf(&builder, "    reg signed [%d:0] delay_%d;\n", dw - 1, k)
+
+		}
+
+	}
+
+	fmt.Fprintf(&builder, "    reg signed [%d:0] acc;\n    reg signed [%d:0] result;\n\n", ww - 1, ww - 1)
+	fmt.Fprintf(&builder, "    always @* begin\n        acc = 0;\n")
+
+	for _, term := range terms {
+
+		fmt.Fprintf(&builder, "        acc = fit_acc(acc + %s);\n", term)
+
+	}
+
+	fmt.Fprintf(&builder, "        result = fit_data(round_shift(acc));\n    end\n\n")
+	fmt.Fprintf(&builder, "    always @(posedge clk) begin\n        if (rst) begin\n            out_valid <= 1'b0;\n            out_data <= 0;\n")
+
+	if (structure == TransposedDirectFormII) {
+
+		for k := 0; k < count - 1; k++ {
+
+			fmt.Fprintf(&builder, "            partial_%d <= 0;\n", k)
+
+		}
+
+	} else {
+
+		for k := 1; k < count; k++ {
+
+			fmt.Fprintf(&builder, "            delay_%d <= 0;\n", k)
+
+		}
+
+	}
+
+	fmt.Fprintf(&builder, "        end else begin\n            out_valid <= in_valid;\n            if (in_valid) begin\n")
+	fmt.Fprintf(&builder, "                out_data <= result[%d:0];\n", dw - 1)
+
+	if (structure == TransposedDirectFormII) {
+
+		for k := 0; k < count - 1; k++ {
+
+			next := ""
+
+			if (k + 1 < count - 1) {
+
+				next = fmt.Sprintf(" + partial_%d", k + 1)
+
+			}
+
+			fmt.Fprintf(&builder, "                partial_%d <= fit_acc(C_%d * in_data%s);\n", k, k + 1, next)
+
+		}
+
+	} else {
+
+		for k := count - 1; k >= 1; k-- {
+
+			fmt.Fprintf(&builder, "                delay_%d <= %s;\n", k, sample(k - 1))
+
+		}
+
+	}
+
+	fmt.Fprintf(&builder, "            end\n        end\n    end\n\nendmodule\n")
+	return builder.String(), verilogTestbench(name, widths, stimulus, expected)
+
+}
+
+func generateBiquadHDL(sections []Section, config Codegen) (string, string) {
+
+	name, language, datapath, samples := parseHDL(config, 2)
+	fixed := newFixedPoint(sections, datapath)
+	datapath = fixed.Datapath
+	widths := widthsOf(datapath)
+	stimulus := quantizeStimulus(datapath, samples)
+	expected := make([]int64, len(stimulus))
+
+	for index, x := range stimulus {
+
+		expected[index] = fixed.processInteger(x)
+
+	}
+
+	ww := widths.working
+	dw := widths.data
+	builder := strings.Builder{}
+	hdlHeader(&builder, name, language, widths)
+	labels := []string{ "B0", "B1", "B2", "A1", "A2" }
+	states := []string{ "x1", "x2", "y1", "y2" }
+	statements := []string{}
+	assignment := "="
+
+	if (language == VHDL) {
+
+		assignment = ":="
+
+	}
+
+	for index := range fixed.Sections {
+
+		input := fmt.Sprintf("stage_%d", index)
+		output := fmt.Sprintf("stage_%d", index + 1)
+		statements = append(statements,
+							fmt.Sprintf("acc %s fit_acc(product(B0_%d, %s));", assignment, index, input),
+							fmt.Sprintf("acc %s fit_acc(acc + product(B1_%d, x1_%d));", assignment, index, index),
+							fmt.Sprintf("acc %s fit_acc(acc + product(B2_%d, x2_%d));", assignment, index, index),
+							fmt.Sprintf("acc %s fit_acc(acc - product(A1_%d, y1_%d));", assignment, index, index),
+							fmt.Sprintf("acc %s fit_acc(acc - product(A2_%d, y2_%d));", assignment, index, index))
+
+		if (language == VHDL) {
+
+			statements = append(statements, fmt.Sprintf("%s := resize(fit_data(round_shift(acc)), %d);", output, dw))
+
+		} else {
+
+			statements = append(statements, fmt.Sprintf("%s = fit_data(round_shift(acc));", output))
+
+		}
+
+	}
+
+	if (language == VHDL) {
+
+		for index, section := range fixed.Sections {
+
+			values := []int64{ section[0][0], section[0][1], section[0][2], section[1][1], section[1][2] }
+
+			for k, label := range labels {
+
+				fmt.Fprintf(&builder, "    constant %s_%d : signed(%d downto 0) := %s;\n", label, index, widths.coefficient - 1, vhdlLiteral(values[k], widths.coefficient))
+
+			}
+
+			for _, state := range states {
+
+				fmt.Fprintf(&builder, "    signal %s_%d : signed(%d downto 0) := (others => '0');\n", state, index, dw - 1)
+
+			}
+
+			builder.WriteString("\n")
+
+		}
+
+		vhdlFunctions(&builder, widths, datapath)
+		fmt.Fprintf(&builder, "begin\n\n    process (clk)\n        variable acc : signed(%d downto 0);\n", ww - 1)
+
+		for index := 0; index <= len(fixed.Sections); index++ {
+
+			fmt.Fprintf(&builder, "        variable stage_%d : signed(%d downto 0);\n", index, dw - 1)
+
+		}
+
+		fmt.Fprintf(&builder, "    begin\n        if rising_edge(clk) then\n            if rst = '1' then\n")
+		fmt.Fprintf(&builder, "                out_valid <= '0';\n                out_data <= (others => '0');\n")
+
+		for index := range fixed.Sections {
+
+			for _, state := range states {
+
+				fmt.Fprintf(&builder, "                %s_%d <= (others => '0');\n", state, index)
+
+			}
+
+		}
+
+		fmt.Fprintf(&builder, "            else\n                out_valid <= in_valid;\n                if in_valid = '1' then\n")
+		fmt.Fprintf(&builder, "                    stage_0 := in_data;\n")
+
+		for _, statement := range statements {
+
+			fmt.Fprintf(&builder, "                    %s\n", statement)
+
+		}
+
+		for index := range fixed.Sections {
+
+			fmt.Fprintf(&builder, "                    x2_%d <= x1_%d;\n                    x1_%d <= stage_%d;\n", index, index, index, index)
+			fmt.Fprintf(&builder, "                    y2_%d <= y1_%d;\n                    y1_%d <= stage_%d;\n", index, index, index, index + 1)
+
+		}
+
+		fmt.Fprintf(&builder, "                    out_data <= stage_%d;\n", len(fixed.Sections))
+		fmt.Fprintf(&builder, "                end if;\n            end if;\n        end if;\n    end process;\n\nend architecture;\n")
+		return builder.String(), vhdlTestbench(name, widths, stimulus, expected)
+
+	}
+
+	for index, section := range fixed.Sections {
+
+		values := []int64{ section[0][0], section[0][1], section[0][2], section[1][1], section[1][2] }
+
+		for k, label := range labels {
+
+			fmt.Fprintf(&builder, "    localparam signed [%d:0] %s_%d = %s;\n", widths.coefficient - 1, label, index, verilogLiteral(values[k], widths.coefficient))
+
+		}
+
+		for _, state := range states {
+
+			fmt.Fprintf(&builder, "    reg signed [%d:0] %s_%d;\n", dw - 1, state, index)
+
+		}
+
+		builder.WriteString("\n")
+
+	}
+
+	verilogFunctions(&builder, widths, datapath)
+	fmt.Fprintf(&builder, "    reg signed [%d:0] acc;\n", ww - 1)
+	fmt.Fprintf(&builder, "    wire signed [%d:0] stage_0 = in_data;\n", dw - 1)
+
+	for index := 1; index <= len(fixed.Sections); index++ {
+
+		fmt.Fprintf(&builder, "    reg signed [%d:0] stage_%d;\n", dw - 1, index)
+
+	}
+
+	fmt.Fprintf(&builder, "\n    always @* begin\n")
+
+	for _, statement := range statements {
+
+		fmt.Fprintf(&builder, "        %s\n", statement)
+
+	}
+
+	fmt.Fprintf(&builder, "    end\n\n    always @(posedge clk) begin\n        if (rst) begin\n            out_valid <= 1'b0;\n            out_data <= 0;\n")
+
+	for index := range fixed.Sections {
+
+		for _, state := range states {
+
+			fmt.Fprintf(&builder, "            %s_%d <= 0;\n", state, index)
+
+		}
+
+	}
+
+	fmt.Fprintf(&builder, "        end else begin\n            out_valid <= in_valid;\n            if (in_valid) begin\n")
+
+	for index := range fixed.Sections {
+
+		fmt.Fprintf(&builder, "                x2_%d <= x1_%d;\n                x1_%d <= stage_%d;\n", index, index, index, index)
+		fmt.Fprintf(&builder, "                y2_%d <= y1_%d;\n                y1_%d <= stage_%d;\n", index, index, index, index + 1)
+
+	}
+
+	fmt.Fprintf(&builder, "                out_data <= stage_%d;\n            end\n        end\n    end\n\nendmodule\n", len(fixed.Sections))
+	return builder.String(), verilogTestbench(name, widths, stimulus, expected)
+
+}
@@ -0,0 +1,158 @@
+package main
+
+import ( "fmt"
+		 "math"
+		 "strings"
+		 "testing" )
+
+
+func TestHDLLiterals(t *testing.T) {
+
+	for _, test := range []struct{ got, want string }{
+
+		{ verilogLiteral(-1, 8), "8'shFF" },
+		{ verilogLiteral(300, 16), "16'sh12C" },
+		{ vhdlLiteral(-2, 4), "\"1110\"" },
+		{ vhdlLiteral(5, 6), "\"000101\"" },
+
+	} {
+
+		if (test.got != test.want) {
+
+			t.Errorf("literal = %s, want %s", test.got, test.want)
+
+		}
+
+	}
+
+}
+
+func TestParseHDL(t *testing.T) {
+
+	name, language, datapath, samples := parseHDL(Codegen{ Name: "FIR Low" }, 5)
+
+	if ((name != "fir_low") || (language != Verilog) || (samples != 256)) {
+
+		t.Errorf("defaults are %q, %s and %d samples", name, language, samples)
+
+	}
+
+	// a Q0.15 sample times a Q1.14 coefficient plus five guard bits
+	if ((datapath.Accumulator != 37) || (datapath.Rounding != Nearest) || (datapath.Overflow != Saturate)) {
+
+		t.Errorf("default datapath is %+v", datapath)
+
+	}
+
+}
+
+func TestSimulateFixedFIR(t *testing.T) {
+
+	taps := windowedSinc(1000.0, 8000.0, kaiserWindow(21, kaiserBeta(60.0)))
+	datapath := Datapath{ Coefficient: Format{ Integer: 1, Fraction: 14 }, Data: Format{ Integer: 0, Fraction: 15 }, Rounding: Nearest, Overflow: Saturate, Accumulator: 36 }
+	coefficients := make([]int64, len(taps))
+
+	for index, tap := range taps {
+
+		coefficients[index], _ = datapath.Coefficient.quantize(tap, Nearest)
+
+	}
+
+	input := quantizeStimulus(datapath, 256)
+	direct := simulateFixedFIR(coefficients, datapath, input, DirectFormI)
+
+	// every structure computes the same exact sums, so only the order of accumulation differs
+	for _, structure := range []Structure{ TransposedDirectFormII, SymmetricFolded } {
+
+		for index, value := range simulateFixedFIR(coefficients, datapath, input, structure) {
+
+			if (value != direct[index]) {
+
+				t.Errorf("%s sample %d = %d, direct form gave %d", structure, index, value, direct[index])
+				break
+
+			}
+
+		}
+
+	}
+
+	for n := range input {
+
+		want := 0.0
+
+		for k := range taps {
+
+			if (n - k >= 0) {
+
+				want += taps[k]*datapath.Data.value(input[n - k])
+
+			}
+
+		}
+
+		if got := datapath.Data.value(direct[n]); (math.Abs(got - want) > 1e-3) {
+
+			t.Errorf("sample %d = %g, floating point gave %g", n, got, want)
+
+		}
+
+	}
+
+}
+
+func TestGenerateHDL(t *testing.T) {
+
+	taps := windowedSinc(1000.0, 8000.0, kaiserWindow(21, kaiserBeta(60.0)))
+
+	for _, language := range []Language{ Verilog, VHDL } {
+
+		declaration, bench := "module %s (", "module %s_tb;"
+
+		if (language == VHDL) {
+
+			declaration, bench = "entity %s is", "entity %s_tb is"
+
+		}
+
+		for _, structure := range []Structure{ DirectFormI, TransposedDirectFormII, SymmetricFolded } {
+
+			name := identifier("fir " + string(structure))
+			module, testbench := generateFIRHDL(taps, Codegen{ Name: name, Language: language, Structure: structure, Samples: 8 })
+
+			if (!strings.Contains(module, fmt.Sprintf(declaration, name))) {
+
+				t.Errorf("%s %s: module does not declare %s", language, structure, name)
+
+			}
+
+			if (!strings.Contains(testbench, fmt.Sprintf(bench, name))) {
+
+				t.Errorf("%s %s: testbench does not declare %s_tb", language, structure, name)
+
+			}
+
+		}
+
+	}
+
+	// the biquad testbench expects exactly the bit-accurate simulation
+	filter := digitalPolynomial(equalizerNumerator, equalizerDenominator)
+	sections := filter.sections()
+	datapath := Datapath{ Coefficient: Format{ Integer: 1, Fraction: 14 }, Data: Format{ Integer: 0, Fraction: 15 }, Rounding: Convergent, RoundProducts: true, Overflow: Wrap }
+	_, testbench := generateBiquadHDL(sections, Codegen{ Name: "biquad", Samples: 16, Datapath: datapath })
+	fixed := newFixedPoint(sections, datapath)
+
+	for index, x := range quantizeStimulus(fixed.Datapath, 16) {
+
+		line := fmt.Sprintf("expected[%d] = %s;", index, verilogLiteral(fixed.processInteger(x), 16))
+
+		if (!strings.Contains(testbench, line)) {
+
+			t.Errorf("testbench does not contain %q", line)
+
+		}
+
+	}
+
+}
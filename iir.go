@@ -1,19 +1,36 @@
 package main
 
-import ( "strings"
-		 "math" )
+import ( "errors"
+		 "fmt"
+		 "math"
+		 "math/cmplx"
+		 "strings" )
 
 
-func designFilter(config Specs) Polynomial {
+func designFilter(config Specs) (Polynomial, error) {
 
-	domain, configuration,
-	response, approximation,
+	if ((config.Response == Notch) || (config.Response == Peak)) {
+
+		return designResonator(config)
+
+	}
+
+	domain, response,
+	approximation, _,
 	order, ripplePassband,
 	rippleStopband, attenuationPassband,
 	attenuationStopband, cutOffFrequency,
 	lowerPassbandEdgeFrequency, upperPassbandEdgeFrequency,
 	lowerStopbandEdgeFrequency, upperStopbandEdgeFrequency,
-	bandwidth, centerFrequency, samplingPeriod = parseDesign(config)
+	bandwidth, centerFrequency, samplingPeriod, err := parseDesign(config)
+
+	if (err != nil) {
+
+		return constructPolynomial(), err
+
+	}
+
+	epsilonPass, epsilonStop := designEpsilons(ripplePassband, rippleStopband, attenuationPassband, attenuationStopband)
 
 	if (order == 0) {
 
@@ -25,12 +42,102 @@ func designFilter(config Specs) Polynomial {
 							   upperStopbandEdgeFrequency, bandwidth,
 							   centerFrequency, samplingPeriod)
 
+		if (order == 0) {
+
+			return constructPolynomial(), errors.New("order is required when the stopband edges and attenuations are not given")
+
+		}
+
+	}
+
+	prototype := analogueLowPassFilterPrototype(approximation, order, epsilonPass, epsilonStop)
+
+	if (len(prototype.Denominator.Terms) == 0) {
+
+		return prototype, fmt.Errorf("%s approximation is not supported", approximation)
+
+	}
+
+	// digital designs are prewarped so the bilinear transform lands the edges where they were asked for
+	warp := func(frequency float64) float64 {
+
+		if (samplingPeriod == 0.0) {
+
+			return frequency
+
+		}
+
+		return math.Tan(math.Pi*frequency*samplingPeriod) / (math.Pi*samplingPeriod)
+
+	}
+
+	analogue := prototype
+
+	switch {
+
+		case (response == string(HPF)):
+
+			analogue = lowPassToHighPass(prototype, warp(cutOffFrequency))
+
+		case ((response == string(BPF)) || contains(bsf, response)):
+
+			// geometric band edges around the center, warped separately
+			lower := math.Sqrt(bandwidth*bandwidth / 4.0 + centerFrequency*centerFrequency) - bandwidth / 2.0
+			upper := warp(lower + bandwidth)
+			lower = warp(lower)
+
+			if (response == string(BPF)) {
+
+				analogue = lowPassToBandPass(prototype, math.Sqrt(lower*upper), upper - lower)
+
+			} else {
+
+				analogue = lowPassToBandStop(prototype, math.Sqrt(lower*upper), upper - lower)
+
+			}
+
+		default:
+
+			analogue = lowPassToLowPass(prototype, warp(cutOffFrequency))
+
+	}
+
+	if (domain == string(Digital)) {
+
+		return bilinear(analogue, 1.0 / samplingPeriod), nil
+
+	}
+
+	return analogue, nil
+
+}
+
+func designEpsilons(ripplePassband float64, rippleStopband float64, attenuationPassband float64, attenuationStopband float64) (float64, float64) {
+
+	epsilonPass := 1.0
+	epsilonStop := math.Sqrt(math.Pow(10, 4.0) - 1.0)
+
+	if (ripplePassband > 0.0) {
+
+		epsilonPass = ripplePassband
+
+	} else if (attenuationPassband > 0.0) {
+
+		epsilonPass = math.Sqrt(math.Pow(10, 0.1*attenuationPassband) - 1.0)
+
+	}
+
+	if (rippleStopband > 0.0) {
+
+		epsilonStop = rippleStopband
+
+	} else if (attenuationStopband > 0.0) {
+
+		epsilonStop = math.Sqrt(math.Pow(10, 0.1*attenuationStopband) - 1.0)
+
 	}
 
-	ltf = analogueLowPassFilterPrototype(approximation, order, epsilonPass, epsilonStop)
-	// laplaceTransform
-	tf := bilinearTransform(approximation, response, order)
-	return tf
+	return epsilonPass, epsilonStop
 
 }
 
@@ -42,14 +149,14 @@ func analogueLowPassFilterPrototype(approximation string, order uint16, epsilonP
 
 		polynomial = butterworthTransferFunction(order)
 
-	} else if contains(chebyshev1, approximation) {
-
-		polynomial = chebyshevTransferFunction(order, epsilonPass)
-
 	} else if contains(chebyshev2, approximation) {
 
 		polynomial = inverseChebyshevTransferFunction(order, epsilonStop)
 
+	} else if contains(chebyshev1, approximation) {
+
+		polynomial = chebyshevTransferFunction(order, epsilonPass)
+
 	} else if contains(cauer, approximation) {
 
 		polynomial = ellipticTransferFunction(order, epsilonPass, epsilonStop)
@@ -60,156 +167,213 @@ func analogueLowPassFilterPrototype(approximation string, order uint16, epsilonP
 
 }
 
-func butterworthTransferFunction(order uint16) Polynomial {
+func rootPolynomial(roots []complex128) []float64 {
 
-	tf := constructPolynomial()
-	initial := 1
+	coefficients := []float64{ 1.0 }
 
-	if (order%2 != 0) {
+	for _, root := range roots {
+
+		if (math.Abs(imag(root)) <= 1e-12*math.Max(cmplx.Abs(root), 1.0)) {
+
+			coefficients = convolve(coefficients, []float64{ 1.0, -real(root) })
 
-		term := map[string]interface{}{
+		} else if (imag(root) > 0.0) {
 
-			"variable": "s",
-			"numerator": map[int64]float64{ 0: 1.0 },
-			"denominator": map[int64]float64{ 0: 1.0, 1: 1.0 },
+			coefficients = convolve(coefficients, []float64{ 1.0, -2.0*real(root), real(root)*real(root) + imag(root)*imag(root) })
 
 		}
 
-		tf = constructPolynomial(term)
-		initial = 2
+	}
+
+	return coefficients
+
+}
+
+func prototypePolynomial(gain float64, zeros []complex128, poles []complex128) Polynomial {
+
+	numerator := rootPolynomial(zeros)
+	denominator := rootPolynomial(poles)
+	scale := gain*denominator[len(denominator) - 1] / numerator[len(numerator) - 1]
+
+	for index := range numerator {
+
+		numerator[index] *= scale
 
 	}
 
-	for index := initial; index <= order - 1; index++ {
+	return analoguePolynomial(numerator, denominator)
 
-		angle := float64(index)*math.Pi / 6.0
-		c := 2.0*math.Cos(angle)
+}
 
-		term := map[string]interface{}{
+func chebyshevPoles(order uint16, alpha float64, beta float64) []complex128 {
 
-			"variable": "s",
-			"numerator": map[int64]float64{ 0: 1.0 },
-			"denominator": map[int64]float64{ 0: 1.0, 1: c, 2: 1.0 },
+	poles := []complex128{}
 
-		}
+	for index := 0; index < int(order); index++ {
 
-		polynomial := constructPolynomial(term)
-		tf.multiply(polynomial)
+		angle := float64(2*index + 1)*math.Pi / (2.0*float64(order))
+		poles = append(poles, complex(-alpha*math.Sin(angle), beta*math.Cos(angle)))
 
 	}
 
-	return tf
+	return poles
+
+}
+
+func butterworthTransferFunction(order uint16) Polynomial {
+
+	return prototypePolynomial(1.0, []complex128{}, chebyshevPoles(order, 1.0, 1.0))
 
 }
 
 func chebyshevTransferFunction(order uint16, epsilonPass float64) Polynomial {
 
-	tf := constructPolynomial()
-	initial := 1
-	d := math.Asinh(1.0 / epsilonPass) / float64(order)
-	alpha := math.Sinh(d)
-	beta := math.Cosh(d)
+	gain := 1.0
 
-	if (order%2 != 0) {
+	if (order%2 == 0) {
+
+		gain = 1.0 / math.Sqrt(1.0 + epsilonPass*epsilonPass)
+
+	}
+
+	mu := math.Asinh(1.0 / epsilonPass) / float64(order)
+	return prototypePolynomial(gain, []complex128{}, chebyshevPoles(order, math.Sinh(mu), math.Cosh(mu)))
+
+}
+
+func inverseChebyshevTransferFunction(order uint16, epsilonStop float64) Polynomial {
+
+	// the stopband edge sits at 1 rad/s, poles are the reciprocals of a type I set with epsilon = 1/epsilonStop
+	mu := math.Asinh(epsilonStop) / float64(order)
+	poles := chebyshevPoles(order, math.Sinh(mu), math.Cosh(mu))
+	zeros := []complex128{}
 
-		term := map[string]interface{}{
+	for index := range poles {
 
-			"variable": "s"
-			"numerator": map[int64]float64{ 0: 1.0 },
-			"denominator": map[int64]float64{ 0: alpha, 1: 1.0 },
+		poles[index] = 1.0 / poles[index]
+		angle := float64(2*index + 1)*math.Pi / (2.0*float64(order))
+
+		if (math.Abs(math.Cos(angle)) > 1e-12) {
+
+			zeros = append(zeros, complex(0.0, 1.0 / math.Cos(angle)))
 
 		}
 
-		tf = constructPolynomial(term)
-		initial = 2
+	}
+
+	return prototypePolynomial(1.0, zeros, poles)
+
+}
+
+func ellipticTransferFunction(order uint16, epsilonPass float64, epsilonStop float64) Polynomial {
+
+	// passband edge at 1 rad/s, stopband edge at 1/k with k from the degree equation
+	selectivity := epsilonPass / epsilonStop
+	k := ellipticDegree(order, selectivity)
+	v0 := real(-1i*inverseEllipticSN(complex(0, 1.0 / epsilonPass), selectivity)) / float64(order)
+	zeros := []complex128{}
+	poles := []complex128{}
+
+	for index := 1; index <= int(order) / 2; index++ {
+
+		u := float64(2*index - 1) / float64(order)
+		zeta := ellipticCD(complex(u, 0), k)
+		zeros = append(zeros, 1i / (complex(k, 0)*zeta))
+		poles = append(poles, 1i*ellipticCD(complex(u, -v0), k))
 
 	}
 
-	for index := initial; index <= order - 1; index++ {
+	gain := 1.0 / math.Sqrt(1.0 + epsilonPass*epsilonPass)
 
-		angle := float64(2*index + 1)*math.Pi / 2.0*float64(order)
-		a := -alpha*math.Sin(angle)
-		b := beta*math.Cos(angle)
-		c := math.Pow(a, 2) + math.Pow(b, 2)
-		m := -2.0*a
+	if (order%2 != 0) {
 
-		term := map[string]interface{}{
+		poles = append(poles, 1i*ellipticSN(complex(0, v0), k))
+		gain = 1.0
 
-			"variable": "s",
-			"numerator": map[int64]float64{ 0: 1.0 },
-			"denominator": map[int64]float64{ 0: c, 1: m, 2: 1.0 },
+	}
 
-		}
+	return prototypePolynomial(gain, zeros, poles)
 
-		polynomial := constructPolynomial(term)
-		tf.multiply(polynomial)
+}
+
+func landenSequence(k float64) []float64 {
+
+	sequence := []float64{}
+
+	for ((k > 1e-15) && (len(sequence) < 16)) {
+
+		k = math.Pow(k / (1.0 + math.Sqrt(1.0 - k*k)), 2)
+		sequence = append(sequence, k)
 
 	}
 
-	return tf
+	return sequence
 
 }
 
-func inverseChebyshevTransferFunction(order uint16, epsilonStop float64) Polynomial {
+func ellipticCD(u complex128, k float64) complex128 {
 
-	tf := constructPolynomial()
-	initial := 1
-	d := math.Asinh(1.0 / epsilonStop) / float64(order)
-	alpha := math.Sinh(d)
-	beta := math.Cosh(d)
+	// cd(uK, k) through the descending Landen transformation
+	sequence := landenSequence(k)
+	w := cmplx.Cos(u*math.Pi / 2.0)
 
-	if (order%2 != 0) {
+	for index := len(sequence) - 1; index >= 0; index-- {
 
-		c := 1.0 / alpha
+		modulus := complex(sequence[index], 0)
+		w = (1.0 + modulus)*w / (1.0 + modulus*w*w)
 
-		term := map[string]interface{}{
+	}
 
-			"variable": "s",
-			"numerator": map[int64]float64{ 0: 1.0 },
-			"denominator": map[int64]float64{ 0: c, 1: 1.0 },
+	return w
 
-		}
+}
+
+func ellipticSN(u complex128, k float64) complex128 {
+
+	sequence := landenSequence(k)
+	w := cmplx.Sin(u*math.Pi / 2.0)
 
-		tf = constructPolynomial(term)
-		initial = 2
+	for index := len(sequence) - 1; index >= 0; index-- {
+
+		modulus := complex(sequence[index], 0)
+		w = (1.0 + modulus)*w / (1.0 + modulus*w*w)
 
 	}
 
-	for index := initial; index <= order - 1; index++ {
+	return w
 
-		angle := (2.0*float64(index) + 1.0)*math.Pi / 2.0*float64(order)
-		zero := 1.0 / math.Cos(angle)
-		zero = math.Pow(zero, 2)
-		a := -alpha*math.Sin(angle)
-		b := beta*math.Cos(angle)
-		c := math.Pow(a, 2) + math.Pow(b, 2)
-		p := -2.0*a / c
-		m := 1.0 / c
+}
 
-		term := map[int64]float64{
+func inverseEllipticSN(w complex128, k float64) complex128 {
 
-			"variable": "s",
-			"numerator": map[int64]float64{ 0: zero, 1: 1.0 },
-			"denominator": map[int64]float64{ 0: m, 1: p, 2: 1.0 },
+	// u such that sn(uK, k) = w, through the ascending Landen transformation
+	sequence := landenSequence(k)
+	previous := k
 
-		}
+	for _, modulus := range sequence {
 
-		polynomial := constructPolynomial(term)
-		tf.multiply(polynomial)
+		w = w / (1.0 + cmplx.Sqrt(1.0 - w*w*complex(previous*previous, 0)))*complex(2.0 / (1.0 + modulus), 0)
+		previous = modulus
 
 	}
 
-	return tf
+	return 2.0*cmplx.Asin(w) / math.Pi
 
 }
 
-func ellipticTransferFunction(order uint16, epsilonPass float64, epsilonStop float64) Polynomial {
+func ellipticDegree(order uint16, selectivity float64) float64 {
+
+	complement := math.Sqrt(1.0 - selectivity*selectivity)
+	product := 1.0
 
-	tf := constructPolynomial()
+	for index := 1; index <= int(order) / 2; index++ {
 
-	sn, cn, dn, dSn, dCn, dDn := landenLoop()
+		product *= real(ellipticSN(complex(float64(2*index - 1) / float64(order), 0), complement))
+
+	}
 
-	return tf
+	modulus := math.Pow(complement, float64(order))*math.Pow(product, 4)
+	return math.Sqrt(1.0 - modulus*modulus)
 
 }
 
@@ -220,11 +384,11 @@ func butterworthOrder(epsilonPass float64, epsilonStop float64, normalizedFreque
 	denominator = math.Abs(denominator)
 	order := numerator / denominator
 	order = math.Ceil(order)
-	return uint64(order)
+	return uint16(order)
 
 }
 
-func chebyshevOrder(epsilonPass float64, epsilonStop float64, normalizedFrequency float64) uint64 {
+func chebyshevOrder(epsilonPass float64, epsilonStop float64, normalizedFrequency float64) uint16 {
 
 	epsilon := epsilonStop / epsilonPass
 	epsilon = math.Sqrt(epsilon)
@@ -232,11 +396,11 @@ func chebyshevOrder(epsilonPass float64, epsilonStop float64, normalizedFrequenc
 	denominator := math.Acosh(normalizedFrequency)
 	order := numerator / denominator
 	order = math.Ceil(order)
-	return uint64(order)
+	return uint16(order)
 
 }
 
-func ellipticOrder(epsilonPass float64, epsilonStop float64, normalizedFrequency float64, response string) uint64 {
+func ellipticOrder(epsilonPass float64, epsilonStop float64, normalizedFrequency float64) uint16 {
 
 	epsilon := epsilonPass / epsilonStop
 	epsilon = math.Sqrt(epsilon)
@@ -257,7 +421,7 @@ func ellipticOrder(epsilonPass float64, epsilonStop float64, normalizedFrequency
 	denominator := a*d
 	order := numerator / denominator
 	order = math.Ceil(order)
-	return uint64(order)
+	return uint16(order)
 
 }
 
@@ -284,59 +448,15 @@ func arithmeticGeometricMean(k float64, tolerance float64) float64 {
 
 }
 
-func landenIterations(a float64, b float64, tolerance float64) (float64, float64, float64,float64, float64, float64) {
-
-	sn := 0.0
-	cn := 0.0
-	dn := 0.0
-	dSn := 0.0
-	dCn := 0.0
-	dDn := 0.0
-	u := a
-	k := b
-	epsilon := math.Abs(tolerance)
-	error := 2.0*epsilon
-	kVector := []float64{}
-	pVector := []float64{}
-
-	for (error > epsilon) {
-
-		c := math.Pow(k, 2)
-		c = math.Sqrt(1.0 - k)
-		k := (1.0 - c) / (1.0 + c)
-		u := u*(1.0 + c) / 2.0
-		kVector = append([]float64{ k }, kVector...)
-		pVector = append([]float64{ c }, pVector...)
-
-	}
-
-	sn = math.Sin(u)
-	cn = math.cos(u)
-	dn = 1.0
-
-	for (index := 0; index < len(kVector); index++) {
-
-		c := kVector[index + 1]
-		d := pVector[index + 1]
-		s := math.Pow(sn, 2)
-		sn = sn*(1 + d) / (1 + c*s)
-		cn = s*(1 - d) / (1 + c*s)
-
-	}
-
-	return sn, cn, dn, dSn, dCn, dDn
-
-}
-
 func calculateOrder(approximation string, response string,
 					ripplePassband float64, rippleStopband float64,
 					attenuationPassband float64, attenuationStopband float64,
 					cutOffFrequency float64, lowerPassbandEdgeFrequency float64,
 					upperPassbandEdgeFrequency float64, lowerStopbandEdgeFrequency float64,
 					upperStopbandEdgeFrequency float64, bandwidth float64,
-					centerFrequency float64, samplingPeriod float64) uint64 {
+					centerFrequency float64, samplingPeriod float64) uint16 {
 
-	order := 0.0
+	order := uint16(0)
 	epsilonPass := math.Pow(ripplePassband, 2)
 	epsilonStop := math.Pow(rippleStopband, 2)
 	normalizedFrequency := 0.0
@@ -355,14 +475,44 @@ func calculateOrder(approximation string, response string,
 
 	}
 
+	if ((epsilonPass == 0.0) || (epsilonStop <= epsilonPass)) {
+
+		return 0
+
+	}
+
+	warp := func(frequency float64) float64 {
+
+		if (samplingPeriod == 0.0) {
+
+			return 2.0*math.Pi*frequency
+
+		}
+
+		return 2.0*math.Tan(math.Pi*frequency*samplingPeriod) / samplingPeriod
+
+	}
+
 	if ((response == "lpf") ||
 		(response == "hpf")) {
 
-		frequencyDesignPass := 2.0*math.Pi*cutOffFrequency
-		frequencyWarpedPass := 2.0*math.Tan(frequencyDesignPass*samplingPeriod / 2.0) / samplingPeriod
-		frequencyDesignStop := 2.0*math.Pi*cutOffFrequency
-		frequencyWarpedStop := 2.0*math.Tan(frequencyDesignStop*samplingPeriod / 2.0) / samplingPeriod
-	
+		stopband := upperStopbandEdgeFrequency
+
+		if ((response == "hpf") || (stopband == 0.0)) {
+
+			stopband = lowerStopbandEdgeFrequency
+
+		}
+
+		if ((cutOffFrequency == 0.0) || (stopband == 0.0)) {
+
+			return 0
+
+		}
+
+		frequencyWarpedPass := warp(cutOffFrequency)
+		frequencyWarpedStop := warp(stopband)
+
 		if (response == "lpf") {
 
 			normalizedFrequency = frequencyWarpedStop / frequencyWarpedPass
@@ -376,54 +526,40 @@ func calculateOrder(approximation string, response string,
 	} else if (contains(bsf, response) ||
 			   (response == "bpf")) {
 
-		frequencyDesignCenter := 2.0*math.Pi*centerFrequency
-		frequencyWarpedCenter := 2.0*math.Tan(frequencyDesignCenter*samplingPeriod / 2.0) / samplingPeriod
-
-		frequencyDesignPassLower := 2.0*math.Pi*lowerPassbandEdgeFrequency
-		frequencyWarpedPassLower := 2.0*math.Tan(frequencyDesignPassLower*samplingPeriod / 2.0) / samplingPreiod
-		frequencyAnaloguePassUpper := math.Pow(frequencyWarpedCenter, 2) / frequencyWarpedPassLower
-		bandwidthWarpedPass := frequencyAnaloguePassUpper - frequencyWarpedPassLower
+		if ((lowerPassbandEdgeFrequency == 0.0) || (upperPassbandEdgeFrequency == 0.0) ||
+			(lowerStopbandEdgeFrequency == 0.0) || (upperStopbandEdgeFrequency == 0.0)) {
 
-		frequencyDesignStopLower := 2.0*math.Pi*lowerStopbandEdgeFrequency
-		frequencyWarpedStopLower := 2.0*math.Tan(frequencyDesignStopLower*samplingPeriod / 2.0) / samplingPreiod
-		frequencyAnalogueStopUpper := math.Pow(frequencyWarpedCenter, 2) / frequencyWarpedStopLower
-		bandwidthWarpedStop := frequencyAnalogueStopUpper - frequencyWarpedStopLower
+			return 0
 
-		frequencyDesignPassUpper := 2.0*math.Pi*upperPassbandEdgeFrequency
-		frequencyWarpedPassUpper := 2.0*math.Tan(frequencyDesignPassUpper*samplingPeriod / 2.0) / samplingPreiod
-		frequencyAnaloguePassLower := math.Pow(frequencyWarpedCenter, 2) / frequencyWarpedPassUpper
-		bandwidthWarpedPassband := frequencyAnaloguePassLower - frequencyWarpedPassUpper
-
-		frequencyDesignStopUpper := 2.0*math.Pi*upperStopbandEdgeFrequency
-		frequencyWarpedStopUpper := 2.0*math.Tan(frequencyDesignStopUpper*samplingPeriod / 2.0) / samplingPreiod
-		frequencyAnalogueStopLower := math.Pow(frequencyWarpedCenter, 2) / frequencyWarpedStopUpper
-		bandwidthWarpedStopband := frequencyAnalogueStopLower - frequencyWarpedStopUpper
+		}
 
-		if (response == "bpf") {
+		frequencyWarpedPassLower := warp(lowerPassbandEdgeFrequency)
+		frequencyWarpedPassUpper := warp(upperPassbandEdgeFrequency)
+		centerSquared := frequencyWarpedPassLower*frequencyWarpedPassUpper
+		bandwidthWarpedPass := frequencyWarpedPassUpper - frequencyWarpedPassLower
+		normalizedFrequency = math.Inf(1)
 
-			if (bandwidthWarpedPass < bandwidthWarpedPassband) {
+		// the stopband edge that maps closest to the prototype passband sets the order
+		for _, stopband := range []float64{ lowerStopbandEdgeFrequency, upperStopbandEdgeFrequency } {
 
-				normalizedFrequency = bandwidthWarpedStop / bandwidthWarpedPass
+			frequencyWarpedStop := warp(stopband)
+			frequency := math.Abs(frequencyWarpedStop*frequencyWarpedStop - centerSquared) / (frequencyWarpedStop*bandwidthWarpedPass)
 
-			} else {
+			if (response != "bpf") {
 
-				normalizedFrequency = bandwidthWarpedStopband / bandwidthWarpedPassband
+				frequency = 1.0 / frequency
 
 			}
 
-		} else {
-
-			if (bandwidthWarpedStop > bandwidthWarpedStopband) {
+			normalizedFrequency = math.Min(normalizedFrequency, frequency)
 
-				normalizedFrequency = bandwidthWarpedPass / bandwidthWarpedStop
-
-			} else {
+		}
 
-				normalizedFrequency = bandwidthWarpedPassband / bandwidthWarpedStopband
+	}
 
-			}
+	if (normalizedFrequency <= 1.0) {
 
-		}
+		return 0
 
 	}
 
@@ -437,7 +573,7 @@ func calculateOrder(approximation string, response string,
 
 	} else if contains(cauer, approximation) {
 
-		order = ellipticOrder(epsilonPass, epsilonStop, normalizedFrequency, response)
+		order = ellipticOrder(epsilonPass, epsilonStop, normalizedFrequency)
 
 	}
 
@@ -453,9 +589,9 @@ func parseDesign(config Specs) (string, string,
 							 	float64, float64,
 							 	float64, float64,
 							 	float64, float64,
-							 	float64) {
+							 	float64, error) {
 
-	domain := ""
+	domain := string(Analogue)
 
 	if config.Domain.exists() {
 
@@ -463,7 +599,7 @@ func parseDesign(config Specs) (string, string,
 
 	}
 
-	response := ""
+	response := string(LPF)
 
 	if config.Response.exists() {
 
@@ -471,7 +607,7 @@ func parseDesign(config Specs) (string, string,
 
 	}
 
-	approximation := ""
+	approximation := string(Butterworth)
 
 	if config.Approximation.exists() {
 
@@ -487,11 +623,11 @@ func parseDesign(config Specs) (string, string,
 
 	}
 
-	order := 0
+	order := uint16(0)
 
 	if (config.Order != nil) {
 
-		order = uint16(math.Abs(*config.Order))
+		order = *config.Order
 
 	}
 
@@ -696,31 +832,6 @@ func parseDesign(config Specs) (string, string,
 
 	}
 
-	if ((bandwidth > 0.0) &&
-		(centerFrequency > 0.0)) {
-
-		if (lowerPassbandEdgeFrequency == 0.0) {
-
-			lowerPassbandEdgeFrequency = centerFrequency - bandwidth / 2.0
-
-		} else if (lowerStopbandEdgeFrequency == 0.0) {
-
-			lowerStopbandEdgeFrequency = centerFrequency - bandwidth / 2.0
-
-		}
-
-		if (upperPassbandEdgeFrequency == 0.0) {
-
-			upperPassbandEdgeFrequency = centerFrequency + bandwidth / 2.0
-
-		} else if (upperStopbandEdgeFrequency == 0.0) {
-
-			upperStopbandEdgeFrequency = centerFrequency + bandwidth / 2.0
-
-		}
-
-	}
-
 	transitionWidth := 0.0
 
 	if (config.TransitionWidth != nil) {
@@ -735,48 +846,31 @@ func parseDesign(config Specs) (string, string,
 
 	}
 
-	if ((transitionWidth > 0.0) && (lowerPassbandEdgeFrequency == 0.0) ||
-		(transitionWidth > 0.0) && (lowerStopbandEdgeFrequency == 0.0) ||
-		(transitionWidth > 0.0) && (upperPassbandEdgeFrequency == 0.0) ||
-		(transitionWidth > 0.0) && (upperStopbandEdgeFrequency == 0.0)) {
-
-		if (lowerPassbandEdgeFrequency == 0.0) {
-
-			lowerPassbandEdgeFrequency = lowerStopbandEdgeFrequency + transitionWidth
-
-		} else if (lowerStopbandEdgeFrequency == 0.0) {
-
-			lowerStopbandEdgeFrequency = lowerPassbandEdgeFrequency + transitionWidth
-
-		}
+	if (transitionWidth > 0.0) {
 
-		if (upperPassbandEdgeFrequency == 0.0) {
+		if ((lowerStopbandEdgeFrequency == 0.0) && (lowerPassbandEdgeFrequency > 0.0)) {
 
-			upperPassbandEdgeFrequency = upperStopbandEdgeFrequency - transitionWidth
+			lowerStopbandEdgeFrequency = lowerPassbandEdgeFrequency - transitionWidth
 
-		} else if (upperStopbandEdgeFrequency == 0.0) {
+		} else if ((lowerPassbandEdgeFrequency == 0.0) && (lowerStopbandEdgeFrequency > 0.0)) {
 
-			upperStopbandEdgeFrequency = upperPassbandEdgeFrequency - transitionWidth
+			lowerPassbandEdgeFrequency = lowerStopbandEdgeFrequency + transitionWidth
 
 		}
 
-		if (lowerStopbandEdgeFrequency == 0.0) {
+		if ((upperStopbandEdgeFrequency == 0.0) && (upperPassbandEdgeFrequency > 0.0)) {
 
-			lowerStopbandEdgeFrequency = lowerPassbandEdgeFrequency - transitionWidth
+			upperStopbandEdgeFrequency = upperPassbandEdgeFrequency + transitionWidth
 
-		} else if (lowerPassbandEdgeFrequency == 0.0) {
+		} else if ((upperPassbandEdgeFrequency == 0.0) && (upperStopbandEdgeFrequency > 0.0)) {
 
-			lowerPassbandEdgeFrequency = lowerStopbandEdgeFrequency - transitionWidth
+			upperPassbandEdgeFrequency = upperStopbandEdgeFrequency - transitionWidth
 
 		}
 
-		if (upperStopbandEdgeFrequency == 0.0) {
+		if (contains(bsf, response)) {
 
-			upperStopbandEdgeFrequency = upperPassbandEdgeFrequency + transitionWidth
-
-		} else if (upperPassbandEdgeFrequency == 0.0) {
-
-			upperPassbandEdgeFrequency = upperStopbandEdgeFrequency + transitionWidth
+			lowerStopbandEdgeFrequency, upperStopbandEdgeFrequency = lowerPassbandEdgeFrequency + transitionWidth, upperPassbandEdgeFrequency - transitionWidth
 
 		}
 
@@ -804,42 +898,33 @@ func parseDesign(config Specs) (string, string,
 
 	}
 
-	if (domain == "digital") {
+	var err error
 
-		if (samplingPeriod == 0.0) {
+	if (domain == string(Digital)) {
 
+		if (samplingPeriod == 0.0) {
 
+			err = errors.New("digital design requires a sampling frequency")
 
-		} else if (order == 0) {
-
-			if ((ripplePassband == 0.0) ||
-				(attenuationPassband == 0.0)) {
-
-				if ((rippleStopband == 0.0) ||
-				 	(attenuationStopband == 0.0)) {
-
-
-
-				 	}
-
-			} else if ((rippleStopband == 0.0) ||
-					   (attenuationStopband == 0.0)) {
-
-				if ((ripplePassband == 0.0) ||
-				 	(attenuationPassband == 0.0)) {
+		} else if (math.Max(cutOffFrequency, math.Sqrt(bandwidth*bandwidth / 4.0 + centerFrequency*centerFrequency) + bandwidth / 2.0) >= samplingFrequency / 2.0) {
 
+			err = fmt.Errorf("band edges must lie below the Nyquist frequency of %.6g Hz", samplingFrequency / 2.0)
 
+		}
 
-				 	}
+	} else {
 
-			}
+		samplingPeriod = 0.0
 
+	}
 
-		}
+	if ((err == nil) && ((response == string(LPF)) || (response == string(HPF))) && (cutOffFrequency == 0.0)) {
 
-	} else {
+		err = fmt.Errorf("%s design requires a cutoff frequency", response)
 
+	} else if ((err == nil) && ((response == string(BPF)) || contains(bsf, response)) && ((centerFrequency == 0.0) || (bandwidth == 0.0))) {
 
+		err = fmt.Errorf("%s design requires a center frequency and a bandwidth", response)
 
 	}
 
@@ -850,6 +935,6 @@ func parseDesign(config Specs) (string, string,
 		   attenuationStopband, cutOffFrequency,
 		   lowerPassbandEdgeFrequency, upperPassbandEdgeFrequency,
 		   lowerStopbandEdgeFrequency, upperStopbandEdgeFrequency,
-		   bandwidth, centerFrequency, samplingPeriod
+		   bandwidth, centerFrequency, samplingPeriod, err
 
 }
@@ -0,0 +1,650 @@
+package main
+
+import ( "errors"
+		 "math"
+		 "math/cmplx"
+		 "slices" )
+
+
+type Element struct {
+
+	Shunt		bool
+	Inductance	float64
+	Capacitance float64
+	Parallel	bool
+
+}
+
+type Ladder struct {
+
+	Elements []Element
+	Source	 float64
+	Load	 float64
+
+}
+
+func (e Element) impedance(s complex128, quality ...float64) complex128 {
+
+	inductance := s*complex(e.Inductance, 0)
+	capacitance := s*complex(e.Capacitance, 0)
+
+	if ((len(quality) > 0) && (quality[0] > 0.0)) {
+
+		inductance += complex(cmplx.Abs(s)*e.Inductance / quality[0], 0)
+
+	}
+
+	if ((len(quality) > 1) && (quality[1] > 0.0)) {
+
+		capacitance += complex(cmplx.Abs(s)*e.Capacitance / quality[1], 0)
+
+	}
+
+	if e.Parallel {
+
+		admittance := complex(0, 0)
+
+		if (e.Inductance > 0.0) {
+
+			admittance += 1.0 / inductance
+
+		}
+
+		if (e.Capacitance > 0.0) {
+
+			admittance += capacitance
+
+		}
+
+		return 1.0 / admittance
+
+	}
+
+	impedance := complex(0, 0)
+
+	if (e.Inductance > 0.0) {
+
+		impedance += inductance
+
+	}
+
+	if (e.Capacitance > 0.0) {
+
+		impedance += 1.0 / capacitance
+
+	}
+
+	return impedance
+
+}
+
+func (l Ladder) chain(s complex128, quality ...float64) (complex128, complex128, complex128, complex128) {
+
+	a, b, c, d := complex(1, 0), complex(0, 0), complex(0, 0), complex(1, 0)
+
+	for _, element := range l.Elements {
+
+		impedance := element.impedance(s, quality...)
+
+		if element.Shunt {
+
+			admittance := 1.0 / impedance
+			a, c = a + b*admittance, c + d*admittance
+
+		} else {
+
+			b, d = a*impedance + b, c*impedance + d
+
+		}
+
+	}
+
+	return a, b, c, d
+
+}
+
+func (l Ladder) transfer(s complex128) complex128 {
+
+	a, b, c, d := l.chain(s)
+	source := complex(l.Source, 0)
+	load := complex(l.Load, 0)
+	return load / (a*load + b + source*(c*load + d))
+
+}
+
+func (l Ladder) response(frequency float64) complex128 {
+
+	return 2.0*complex(math.Sqrt(l.Source / l.Load), 0)*l.transfer(complex(0, 2.0*math.Pi*frequency))
+
+}
+
+func reflectionPolynomial(numerator []float64, denominator []float64, scale float64, mirror bool) []float64 {
+
+	order := len(denominator) - 1
+	mirrored := slices.Clone(denominator)
+
+	for index := range mirrored {
+
+		if ((order - index)%2 == 1) {
+
+			mirrored[index] = -mirrored[index]
+
+		}
+
+	}
+
+	product := convolve(denominator, mirrored)
+	constant := numerator[len(numerator) - 1]*scale
+	product[len(product) - 1] -= constant*constant
+	origin := 0
+
+	for ((len(product) > 1) && (math.Abs(product[len(product) - 1]) < 1e-12*math.Abs(product[0]))) {
+
+		product = product[:len(product) - 1]
+		origin++
+
+	}
+
+	roots := []complex128{}
+	axis := []complex128{}
+
+	for _, root := range polynomialRoots(product) {
+
+		if (math.Abs(real(root)) < 1e-6*math.Max(cmplx.Abs(root), 1.0)) {
+
+			if (imag(root) > 0.0) {
+
+				axis = append(axis, complex(0, imag(root)))
+
+			}
+
+		} else if ((real(root) < 0.0) != mirror) {
+
+			roots = append(roots, root)
+
+		}
+
+	}
+
+	slices.SortFunc(axis, func(p complex128, q complex128) int {
+
+		if (imag(p) < imag(q)) {
+
+			return -1
+
+		} else if (imag(p) > imag(q)) {
+
+			return 1
+
+		}
+
+		return 0
+
+	})
+
+	slope := make([]float64, len(product) - 1)
+	curvature := make([]float64, max(len(product) - 2, 0))
+
+	for index := range slope {
+
+		slope[index] = float64(len(product) - index - 1)*product[index]
+
+	}
+
+	for index := range curvature {
+
+		curvature[index] = float64(len(slope) - index - 1)*slope[index]
+
+	}
+
+	for index := 0; index + 1 < len(axis); index += 2 {
+
+		root := complex(0, 0.5*(imag(axis[index]) + imag(axis[index + 1])))
+
+		for iteration := 0; iteration < 8; iteration++ {
+
+			step := hornerComplex(curvature, root)
+
+			if (step == 0) {
+
+				break
+
+			}
+
+			root = complex(0, imag(root - hornerComplex(slope, root) / step))
+
+		}
+
+		roots = append(roots, root, cmplx.Conj(root))
+
+	}
+
+	for index := 0; index < origin / 2; index++ {
+
+		roots = append(roots, 0)
+
+	}
+
+	reflection := expandRoots(roots)
+
+	for index := range reflection {
+
+		reflection[index] *= denominator[0]
+
+	}
+
+	return append(make([]float64, len(denominator) - len(reflection)), reflection...)
+
+}
+
+func cauerExpansion(top []float64, bottom []float64, shunt bool) Ladder {
+
+	ladder := Ladder{ Elements: []Element{}, Source: 1.0, Load: 1.0 }
+	bottom = bottom[1:]
+
+	for (len(bottom) > 0) {
+
+		for ((len(bottom) > 1) && (math.Abs(bottom[0]) < 1e-8*math.Abs(top[0]))) {
+
+			bottom = bottom[1:]
+
+		}
+
+		if (len(top) <= len(bottom)) {
+
+			break
+
+		}
+
+		value := top[0] / bottom[0]
+		remainder := slices.Clone(top[1:])
+
+		for index := range bottom[1:] {
+
+			remainder[index] -= value*bottom[index + 1]
+
+		}
+
+		element := Element{ Shunt: shunt }
+
+		if shunt {
+
+			element.Capacitance = value
+
+		} else {
+
+			element.Inductance = value
+
+		}
+
+		ladder.Elements = append(ladder.Elements, element)
+		top, bottom = bottom, remainder
+		shunt = !shunt
+
+		if (len(top) == 1) {
+
+			break
+
+		}
+
+	}
+
+	if ((len(top) == 1) && (len(bottom) == 1) && (bottom[0] != 0.0)) {
+
+		ratio := top[0] / bottom[0]
+
+		if shunt {
+
+			ladder.Load = 1.0 / ratio
+
+		} else {
+
+			ladder.Load = ratio
+
+		}
+
+	}
+
+	return ladder
+
+}
+
+func synthesizeLadder(prototype Polynomial, expansion Expansion, load ...float64) (Ladder, error) {
+
+	ratio := 1.0
+
+	if ((len(load) > 0) && (load[0] > 0.0)) {
+
+		ratio = load[0]
+
+	}
+
+	numerator, denominator := prototype.analogueCoefficients()
+
+	if ((len(denominator) < 2) || (denominator[0] == 0.0)) {
+
+		return Ladder{ Elements: []Element{}, Source: 1.0, Load: ratio }, errors.New("ladder synthesis requires a prototype with poles")
+
+	}
+
+	if (len(numerator) != 1) {
+
+		return Ladder{ Elements: []Element{}, Source: 1.0, Load: ratio }, errors.New("ladder synthesis only realizes all-pole prototypes, this one has finite transmission zeros")
+
+	}
+
+	peak := 0.0
+
+	for _, frequency := range linearSpace(0.0, 2.0, 2001) {
+
+		peak = math.Max(peak, cmplx.Abs(prototype.response(complex(0, frequency))))
+
+	}
+
+	transmission := 2.0*math.Sqrt(ratio) / (1.0 + ratio)
+	scale := math.Min(1.0 / peak, transmission / cmplx.Abs(prototype.response(0)))
+	best := Ladder{}
+	distance := math.Inf(1)
+	shunts := []bool{ true, false }
+
+	if expansion.exists() {
+
+		shunts = []bool{ (expansion == CauerI) }
+
+	}
+
+	for _, mirror := range []bool{ false, true } {
+
+		reflection := reflectionPolynomial(numerator, denominator, scale, mirror)
+		top := make([]float64, len(denominator))
+		bottom := make([]float64, len(denominator))
+
+		for index := range denominator {
+
+			top[index] = denominator[index] + reflection[index]
+			bottom[index] = denominator[index] - reflection[index]
+
+		}
+
+		for _, shunt := range shunts {
+
+			ladder := cauerExpansion(top, bottom, shunt)
+
+			if (len(ladder.Elements) == 0) {
+
+				continue
+
+			}
+
+			if mismatch := math.Abs(math.Log(ladder.Load / ratio)); (mismatch < distance - 1e-9) {
+
+				best = ladder
+				distance = mismatch
+
+			}
+
+		}
+
+	}
+
+	if (best.Elements == nil) {
+
+		return Ladder{ Elements: []Element{}, Source: 1.0, Load: ratio }, errors.New("continued fraction expansion of the prototype failed")
+
+	}
+
+	return best, nil
+
+}
+
+func (l Ladder) scale(frequency float64, impedance ...float64) Ladder {
+
+	level := 1.0
+
+	if ((len(impedance) > 0) && (impedance[0] > 0.0)) {
+
+		level = impedance[0]
+
+	}
+
+	omega := 2.0*math.Pi*frequency
+	scaled := Ladder{ Elements: slices.Clone(l.Elements), Source: l.Source*level, Load: l.Load*level }
+
+	for index := range scaled.Elements {
+
+		scaled.Elements[index].Inductance *= level / omega
+		scaled.Elements[index].Capacitance /= level*omega
+
+	}
+
+	return scaled
+
+}
+
+func (l Ladder) highPass() Ladder {
+
+	transformed := Ladder{ Elements: make([]Element, len(l.Elements)), Source: l.Source, Load: l.Load }
+
+	for index, element := range l.Elements {
+
+		transformed.Elements[index] = Element{ Shunt: element.Shunt, Parallel: element.Parallel }
+
+		if (element.Inductance > 0.0) {
+
+			transformed.Elements[index].Capacitance = 1.0 / element.Inductance
+
+		}
+
+		if (element.Capacitance > 0.0) {
+
+			transformed.Elements[index].Inductance = 1.0 / element.Capacitance
+
+		}
+
+	}
+
+	return transformed
+
+}
+
+func (l Ladder) bandPass(bandwidth float64) Ladder {
+
+	transformed := Ladder{ Elements: make([]Element, len(l.Elements)), Source: l.Source, Load: l.Load }
+
+	for index, element := range l.Elements {
+
+		transformed.Elements[index] = Element{ Shunt: element.Shunt }
+
+		if (element.Inductance > 0.0) {
+
+			transformed.Elements[index].Inductance = element.Inductance / bandwidth
+			transformed.Elements[index].Capacitance = bandwidth / element.Inductance
+
+		} else if (element.Capacitance > 0.0) {
+
+			transformed.Elements[index].Capacitance = element.Capacitance / bandwidth
+			transformed.Elements[index].Inductance = bandwidth / element.Capacitance
+			transformed.Elements[index].Parallel = true
+
+		}
+
+	}
+
+	return transformed
+
+}
+
+func (l Ladder) bandStop(bandwidth float64) Ladder {
+
+	transformed := Ladder{ Elements: make([]Element, len(l.Elements)), Source: l.Source, Load: l.Load }
+
+	for index, element := range l.Elements {
+
+		transformed.Elements[index] = Element{ Shunt: element.Shunt }
+
+		if (element.Inductance > 0.0) {
+
+			transformed.Elements[index].Inductance = element.Inductance*bandwidth
+			transformed.Elements[index].Capacitance = 1.0 / (element.Inductance*bandwidth)
+			transformed.Elements[index].Parallel = true
+
+		} else if (element.Capacitance > 0.0) {
+
+			transformed.Elements[index].Capacitance = element.Capacitance*bandwidth
+			transformed.Elements[index].Inductance = 1.0 / (element.Capacitance*bandwidth)
+
+		}
+
+	}
+
+	return transformed
+
+}
+
+func sumPolynomials(p []float64, q []float64) []float64 {
+
+	sum := make([]float64, max(len(p), len(q)))
+
+	for index, coefficient := range p {
+
+		sum[len(sum) - len(p) + index] += coefficient
+
+	}
+
+	for index, coefficient := range q {
+
+		sum[len(sum) - len(q) + index] += coefficient
+
+	}
+
+	return sum
+
+}
+
+func (e Element) polynomials() ([]float64, []float64) {
+
+	if (e.Inductance > 0.0) && (e.Capacitance > 0.0) {
+
+		if e.Parallel {
+
+			return []float64{ e.Inductance, 0.0 }, []float64{ e.Inductance*e.Capacitance, 0.0, 1.0 }
+
+		}
+
+		return []float64{ e.Inductance*e.Capacitance, 0.0, 1.0 }, []float64{ e.Capacitance, 0.0 }
+
+	}
+
+	if (e.Capacitance > 0.0) {
+
+		return []float64{ 1.0 }, []float64{ e.Capacitance, 0.0 }
+
+	}
+
+	return []float64{ e.Inductance, 0.0 }, []float64{ 1.0 }
+
+}
+
+func (l Ladder) coefficients() ([]float64, []float64) {
+
+	a, b, c, d := []float64{ 1.0 }, []float64{ 0.0 }, []float64{ 0.0 }, []float64{ 1.0 }
+	common := []float64{ l.Load }
+
+	for _, element := range l.Elements {
+
+		if ((element.Inductance <= 0.0) && (element.Capacitance <= 0.0)) {
+
+			continue
+
+		}
+
+		numerator, denominator := element.polynomials()
+
+		if element.Shunt {
+
+			a, b = sumPolynomials(convolve(a, numerator), convolve(b, denominator)), convolve(b, numerator)
+			c, d = sumPolynomials(convolve(c, numerator), convolve(d, denominator)), convolve(d, numerator)
+			common = convolve(common, numerator)
+
+		} else {
+
+			a, b = convolve(a, denominator), sumPolynomials(convolve(a, numerator), convolve(b, denominator))
+			c, d = convolve(c, denominator), sumPolynomials(convolve(c, numerator), convolve(d, denominator))
+			common = convolve(common, denominator)
+
+		}
+
+	}
+
+	scale := func(p []float64, factor float64) []float64 {
+
+		scaled := make([]float64, len(p))
+
+		for index, coefficient := range p {
+
+			scaled[index] = coefficient*factor
+
+		}
+
+		return scaled
+
+	}
+
+	denominator := sumPolynomials(sumPolynomials(scale(a, l.Load), b), scale(sumPolynomials(scale(c, l.Load), d), l.Source))
+	return common, denominator
+
+}
+
+func (l Ladder) poles() []complex128 {
+
+	_, denominator := l.coefficients()
+
+	for ((len(denominator) > 1) && (denominator[0] == 0.0)) {
+
+		denominator = denominator[1:]
+
+	}
+
+	order := len(denominator) - 1
+
+	if ((order < 1) || (denominator[order] == 0.0)) {
+
+		return polynomialRoots(denominator)
+
+	}
+
+	frequency := math.Pow(math.Abs(denominator[order] / denominator[0]), 1.0 / float64(order))
+	normalized := make([]float64, len(denominator))
+
+	for index, coefficient := range denominator {
+
+		normalized[index] = coefficient*math.Pow(frequency, float64(order - index))
+
+	}
+
+	roots := polynomialRoots(normalized)
+
+	for index := range roots {
+
+		roots[index] *= complex(frequency, 0)
+
+	}
+
+	return roots
+
+}
+
+func (l Ladder) band() (float64, float64) {
+
+	low, high := math.Inf(1), 0.0
+
+	for _, pole := range l.poles() {
+
+		low = math.Min(low, cmplx.Abs(pole) / (2.0*math.Pi))
+		high = math.Max(high, cmplx.Abs(pole) / (2.0*math.Pi))
+
+	}
+
+	return low, high
+
+}
@@ -0,0 +1,306 @@
+package main
+
+import ( "math"
+		 "slices" )
+
+
+type Lattice struct {
+
+	Reflection []float64
+	Ladder	   []float64
+	Gain	   float64
+	Stable	   bool
+	forward	   []float64
+	backward   []float64
+
+}
+
+func stepDown(coefficients []float64) ([]float64, [][]float64, bool) {
+
+	order := len(coefficients) - 1
+	reflection := make([]float64, order)
+	polynomials := make([][]float64, order + 1)
+	polynomials[order] = slices.Clone(coefficients)
+	stable := true
+
+	for m := order; m >= 1; m-- {
+
+		current := polynomials[m]
+		k := current[m]
+		reflection[m - 1] = k
+
+		if (math.Abs(k) >= 1.0) {
+
+			stable = false
+
+		}
+
+		previous := make([]float64, m)
+		denominator := 1.0 - k*k
+
+		if (denominator == 0.0) {
+
+			for index := m - 1; index >= 0; index-- {
+
+				polynomials[index] = make([]float64, index + 1)
+				polynomials[index][0] = 1.0
+
+			}
+
+			return reflection, polynomials, false
+
+		}
+
+		for index := 0; index < m; index++ {
+
+			previous[index] = (current[index] - k*current[m - index]) / denominator
+
+		}
+
+		polynomials[m - 1] = previous
+
+	}
+
+	return reflection, polynomials, stable
+
+}
+
+func reflectionCoefficients(coefficients []float64) []float64 {
+
+	if ((len(coefficients) == 0) || (coefficients[0] == 0.0)) {
+
+		return []float64{}
+
+	}
+
+	monic := make([]float64, len(coefficients))
+
+	for index, coefficient := range coefficients {
+
+		monic[index] = coefficient / coefficients[0]
+
+	}
+
+	reflection, _, _ := stepDown(monic)
+	return reflection
+
+}
+
+func latticeCoefficients(reflection []float64) []float64 {
+
+	coefficients := []float64{ 1.0 }
+
+	for m, k := range reflection {
+
+		next := make([]float64, m + 2)
+
+		for index := 0; index <= m; index++ {
+
+			next[index] += coefficients[index]
+			next[m + 1 - index] += k*coefficients[index]
+
+		}
+
+		coefficients = next
+
+	}
+
+	return coefficients
+
+}
+
+func newLattice(filter Polynomial) *Lattice {
+
+	numerator, denominator := filter.digitalCoefficients()
+
+	reflection := reflectionCoefficients(numerator)
+
+	if ((len(denominator) == 1) &&
+		(len(reflection) + 1 == len(numerator)) &&
+		!slices.ContainsFunc(reflection, func(k float64) bool { return (math.Abs(k) == 1.0) })) {
+
+		gain := numerator[0] / denominator[0]
+
+		return &Lattice{
+
+			Reflection: reflection,
+			Gain: gain,
+			Stable: stableReflection(reflection),
+			forward: make([]float64, len(reflection) + 1),
+			backward: make([]float64, len(reflection) + 1),
+
+		}
+
+	}
+
+	order := max(len(numerator), len(denominator)) - 1
+	numerator = append(numerator, make([]float64, order + 1 - len(numerator))...)
+	denominator = append(denominator, make([]float64, order + 1 - len(denominator))...)
+	reflection, polynomials, stable := stepDown(denominator)
+	ladder := make([]float64, order + 1)
+	remainder := slices.Clone(numerator)
+
+	for m := order; m >= 0; m-- {
+
+		ladder[m] = remainder[m]
+
+		for index := 0; index <= m; index++ {
+
+			remainder[index] -= ladder[m]*polynomials[m][m - index]
+
+		}
+
+	}
+
+	return &Lattice{
+
+		Reflection: reflection,
+		Ladder: ladder,
+		Gain: 1.0,
+		Stable: stable,
+		forward: make([]float64, order + 1),
+		backward: make([]float64, order + 1),
+
+	}
+
+}
+
+func stableReflection(reflection []float64) bool {
+
+	for _, k := range reflection {
+
+		if (math.Abs(k) >= 1.0) {
+
+			return false
+
+		}
+
+	}
+
+	return true
+
+}
+
+func newReflectionLattice(reflection []float64, ladder ...float64) *Lattice {
+
+	lattice := &Lattice{
+
+		Reflection: slices.Clone(reflection),
+		Gain: 1.0,
+		Stable: stableReflection(reflection),
+		forward: make([]float64, len(reflection) + 1),
+		backward: make([]float64, len(reflection) + 1),
+
+	}
+
+	if (len(ladder) > 0) {
+
+		lattice.Ladder = make([]float64, len(reflection) + 1)
+		copy(lattice.Ladder, ladder)
+
+	}
+
+	return lattice
+
+}
+
+func (l *Lattice) polynomial() Polynomial {
+
+	denominator := latticeCoefficients(l.Reflection)
+
+	if (l.Ladder == nil) {
+
+		for index := range denominator {
+
+			denominator[index] *= l.Gain
+
+		}
+
+		return digitalPolynomial(denominator, []float64{ 1.0 })
+
+	}
+
+	numerator := make([]float64, len(l.Reflection) + 1)
+
+	for m := range l.Ladder {
+
+		reversed := latticeCoefficients(l.Reflection[:m])
+		slices.Reverse(reversed)
+
+		for index, coefficient := range reversed {
+
+			numerator[index] += l.Ladder[m]*coefficient
+
+		}
+
+	}
+
+	return digitalPolynomial(numerator, denominator)
+
+}
+
+func (l *Lattice) Reset() {
+
+	clear(l.forward)
+	clear(l.backward)
+
+}
+
+func (l *Lattice) Process(x float64) float64 {
+
+	order := len(l.Reflection)
+
+	if (l.Ladder == nil) {
+
+		forward := x
+		delayed := l.backward[0]
+		l.backward[0] = x
+
+		for m := 1; m <= order; m++ {
+
+			k := l.Reflection[m - 1]
+			next := forward + k*delayed
+			backward := k*forward + delayed
+			delayed = l.backward[m]
+			l.backward[m] = backward
+			forward = next
+
+		}
+
+		return l.Gain*forward
+
+	}
+
+	forward := x
+
+	for m := order; m >= 1; m-- {
+
+		k := l.Reflection[m - 1]
+		forward -= k*l.backward[m - 1]
+		l.forward[m] = k*forward + l.backward[m - 1]
+
+	}
+
+	l.forward[0] = forward
+	y := 0.0
+
+	for m := 0; m <= order; m++ {
+
+		l.backward[m] = l.forward[m]
+		y += l.Ladder[m]*l.forward[m]
+
+	}
+
+	return y
+
+}
+
+func (l *Lattice) ProcessBlock(in []float64, out []float64) {
+
+	for index := 0; index < min(len(in), len(out)); index++ {
+
+		out[index] = l.Process(in[index])
+
+	}
+
+}
@@ -0,0 +1,86 @@
+package main
+
+import ( "math/cmplx"
+		 "testing" )
+
+
+func TestReflectionCoefficients(t *testing.T) {
+
+	// levinson step-down of 1 + 0.5z⁻¹ + 0.25z⁻² gives k2 = 0.25 and k1 = 0.5 / 1.25
+	reflection := reflectionCoefficients([]float64{ 2.0, 1.0, 0.5 })
+	compareSignals(t, "reflection", reflection, []float64{ 0.4, 0.25 }, 1e-15)
+	compareSignals(t, "step-up", latticeCoefficients(reflection), []float64{ 1.0, 0.5, 0.25 }, 1e-15)
+
+	if (stableReflection([]float64{ 0.4, -0.99 }) == stableReflection([]float64{ 0.4, 1.0 })) {
+
+		t.Errorf("|k| < 1 does not separate minimum phase from the unit circle")
+
+	}
+
+}
+
+func TestLatticeFIR(t *testing.T) {
+
+	taps := []float64{ 1.5, 0.5, 0.3, -0.2 }
+	filter := digitalPolynomial(taps, []float64{ 1.0 })
+	lattice := newLattice(filter)
+
+	if ((lattice.Ladder != nil) || (len(lattice.Reflection) != 3) || (lattice.Gain != 1.5)) {
+
+		t.Fatalf("got %d reflection coefficients, gain %g and ladder %v", len(lattice.Reflection), lattice.Gain, lattice.Ladder)
+
+	}
+
+	signal := testSignal(200)
+	got := make([]float64, len(signal))
+	lattice.ProcessBlock(signal, got)
+	compareSignals(t, "fir lattice", got, referenceFilter(taps, []float64{ 1.0 }, signal), 1e-12)
+
+	equivalent := lattice.polynomial()
+	numerator, _ := equivalent.digitalCoefficients()
+	compareSignals(t, "fir round trip", numerator, taps, 1e-12)
+
+}
+
+func TestLatticeLadder(t *testing.T) {
+
+	filter := digitalPolynomial(equalizerNumerator, equalizerDenominator)
+	lattice := newLattice(filter)
+
+	if ((lattice.Ladder == nil) || !lattice.Stable) {
+
+		t.Fatalf("butterworth lattice has ladder %v and stable %t", lattice.Ladder, lattice.Stable)
+
+	}
+
+	signal := testSignal(300)
+	got := make([]float64, len(signal))
+	lattice.ProcessBlock(signal, got)
+	compareSignals(t, "lattice-ladder", got, referenceFilter(equalizerNumerator, equalizerDenominator, signal), 1e-10)
+
+	equivalent := lattice.polynomial()
+
+	for _, frequency := range []float64{ 0.0, 100.0, 400.0 } {
+
+		want := frequencyResponse(filter, frequency, 1000.0)
+
+		if got := frequencyResponse(equivalent, frequency, 1000.0); (cmplx.Abs(got - want) > 1e-10) {
+
+			t.Errorf("lattice-ladder response at %g Hz = %v, want %v", frequency, got, want)
+
+		}
+
+	}
+
+	lattice.Reset()
+	lattice.ProcessBlock(signal, got)
+	compareSignals(t, "after reset", got, referenceFilter(equalizerNumerator, equalizerDenominator, signal), 1e-10)
+
+	// poles at 0.9 ± j0.6 lie outside the unit circle
+	if unstable := newLattice(digitalPolynomial([]float64{ 1.0 }, []float64{ 1.0, -1.8, 1.17 })); unstable.Stable {
+
+		t.Errorf("reflection coefficients %v were reported stable", unstable.Reflection)
+
+	}
+
+}
@@ -0,0 +1,638 @@
+package main
+
+import ( "math"
+		 "math/cmplx"
+		 "slices" )
+
+
+func zeroMatrix(rows int, columns int) [][]float64 {
+
+	matrix := make([][]float64, rows)
+
+	for row := range matrix {
+
+		matrix[row] = make([]float64, columns)
+
+	}
+
+	return matrix
+
+}
+
+func identityMatrix(size int) [][]float64 {
+
+	matrix := zeroMatrix(size, size)
+
+	for index := range matrix {
+
+		matrix[index][index] = 1.0
+
+	}
+
+	return matrix
+
+}
+
+func copyMatrix(matrix [][]float64) [][]float64 {
+
+	duplicate := make([][]float64, len(matrix))
+
+	for row := range matrix {
+
+		duplicate[row] = slices.Clone(matrix[row])
+
+	}
+
+	return duplicate
+
+}
+
+func transposeMatrix(matrix [][]float64) [][]float64 {
+
+	if (len(matrix) == 0) {
+
+		return [][]float64{}
+
+	}
+
+	transpose := zeroMatrix(len(matrix[0]), len(matrix))
+
+	for row := range matrix {
+
+		for column := range matrix[row] {
+
+			transpose[column][row] = matrix[row][column]
+
+		}
+
+	}
+
+	return transpose
+
+}
+
+func multiplyMatrices(p [][]float64, q [][]float64) [][]float64 {
+
+	if ((len(p) == 0) || (len(q) == 0)) {
+
+		return zeroMatrix(len(p), 0)
+
+	}
+
+	product := zeroMatrix(len(p), len(q[0]))
+
+	for row := range p {
+
+		for k := range q {
+
+			if (p[row][k] == 0.0) {
+
+				continue
+
+			}
+
+			for column := range q[k] {
+
+				product[row][column] += p[row][k]*q[k][column]
+
+			}
+
+		}
+
+	}
+
+	return product
+
+}
+
+func multiplyVector(matrix [][]float64, vector []float64) []float64 {
+
+	product := make([]float64, len(matrix))
+
+	for row := range matrix {
+
+		for column, value := range vector {
+
+			product[row] += matrix[row][column]*value
+
+		}
+
+	}
+
+	return product
+
+}
+
+func outerProduct(p []float64, q []float64) [][]float64 {
+
+	product := zeroMatrix(len(p), len(q))
+
+	for row := range p {
+
+		for column := range q {
+
+			product[row][column] = p[row]*q[column]
+
+		}
+
+	}
+
+	return product
+
+}
+
+func invertMatrix(matrix [][]float64) [][]float64 {
+
+	size := len(matrix)
+	inverse := zeroMatrix(size, size)
+	unit := make([]float64, size)
+
+	for column := 0; column < size; column++ {
+
+		clear(unit)
+		unit[column] = 1.0
+		solution := solveLinear(matrix, unit)
+
+		for row := range solution {
+
+			inverse[row][column] = solution[row]
+
+		}
+
+	}
+
+	return inverse
+
+}
+
+func matrixNorm(matrix [][]float64) float64 {
+
+	norm := 0.0
+
+	for row := range matrix {
+
+		sum := 0.0
+
+		for _, value := range matrix[row] {
+
+			sum += math.Abs(value)
+
+		}
+
+		norm = math.Max(norm, sum)
+
+	}
+
+	return norm
+
+}
+
+func matrixExponential(matrix [][]float64) [][]float64 {
+
+	size := len(matrix)
+	squarings := 0
+	norm := matrixNorm(matrix)
+
+	if (norm > 0.5) {
+
+		squarings = int(math.Ceil(math.Log2(norm / 0.5)))
+
+	}
+
+	scaled := copyMatrix(matrix)
+	factor := math.Ldexp(1.0, -squarings)
+
+	for row := range scaled {
+
+		for column := range scaled[row] {
+
+			scaled[row][column] *= factor
+
+		}
+
+	}
+
+	exponential := identityMatrix(size)
+	term := identityMatrix(size)
+
+	for order := 1; order <= 20; order++ {
+
+		term = multiplyMatrices(term, scaled)
+
+		for row := range term {
+
+			for column := range term[row] {
+
+				term[row][column] /= float64(order)
+				exponential[row][column] += term[row][column]
+
+			}
+
+		}
+
+	}
+
+	for index := 0; index < squarings; index++ {
+
+		exponential = multiplyMatrices(exponential, exponential)
+
+	}
+
+	return exponential
+
+}
+
+func choleskyFactor(matrix [][]float64) ([][]float64, bool) {
+
+	size := len(matrix)
+	factor := zeroMatrix(size, size)
+
+	for row := 0; row < size; row++ {
+
+		for column := 0; column <= row; column++ {
+
+			sum := matrix[row][column]
+
+			for k := 0; k < column; k++ {
+
+				sum -= factor[row][k]*factor[column][k]
+
+			}
+
+			if (row == column) {
+
+				if (sum <= 0.0) {
+
+					return factor, false
+
+				}
+
+				factor[row][row] = math.Sqrt(sum)
+
+			} else {
+
+				factor[row][column] = sum / factor[column][column]
+
+			}
+
+		}
+
+	}
+
+	return factor, true
+
+}
+
+func symmetricEigen(matrix [][]float64) ([]float64, [][]float64) {
+
+	size := len(matrix)
+	a := copyMatrix(matrix)
+	vectors := identityMatrix(size)
+
+	for sweep := 0; sweep < 100; sweep++ {
+
+		offDiagonal := 0.0
+
+		for row := 0; row < size; row++ {
+
+			for column := row + 1; column < size; column++ {
+
+				offDiagonal += a[row][column]*a[row][column]
+
+			}
+
+		}
+
+		if (offDiagonal < 1e-30) {
+
+			break
+
+		}
+
+		for p := 0; p < size; p++ {
+
+			for q := p + 1; q < size; q++ {
+
+				if (a[p][q] == 0.0) {
+
+					continue
+
+				}
+
+				theta := (a[q][q] - a[p][p]) / (2.0*a[p][q])
+				t := math.Copysign(1.0, theta) / (math.Abs(theta) + math.Sqrt(theta*theta + 1.0))
+				c := 1.0 / math.Sqrt(t*t + 1.0)
+				s := t*c
+
+				for k := 0; k < size; k++ {
+
+					akp := a[k][p]
+					akq := a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+
+				}
+
+				for k := 0; k < size; k++ {
+
+					apk := a[p][k]
+					aqk := a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+
+				}
+
+				for k := 0; k < size; k++ {
+
+					vkp := vectors[k][p]
+					vkq := vectors[k][q]
+					vectors[k][p] = c*vkp - s*vkq
+					vectors[k][q] = s*vkp + c*vkq
+
+				}
+
+			}
+
+		}
+
+	}
+
+	order := make([]int, size)
+
+	for index := range order {
+
+		order[index] = index
+
+	}
+
+	slices.SortFunc(order, func(p int, q int) int {
+
+		if (a[p][p] > a[q][q]) {
+
+			return -1
+
+		} else if (a[p][p] < a[q][q]) {
+
+			return 1
+
+		}
+
+		return 0
+
+	})
+
+	values := make([]float64, size)
+	sorted := zeroMatrix(size, size)
+
+	for index, source := range order {
+
+		values[index] = a[source][source]
+
+		for row := 0; row < size; row++ {
+
+			sorted[row][index] = vectors[row][source]
+
+		}
+
+	}
+
+	return values, sorted
+
+}
+
+func characteristicPolynomial(matrix [][]float64) []float64 {
+
+	size := len(matrix)
+	h := copyMatrix(matrix)
+
+	for m := 1; m < size - 1; m++ {
+
+		pivot := m
+
+		for row := m + 1; row < size; row++ {
+
+			if (math.Abs(h[row][m - 1]) > math.Abs(h[pivot][m - 1])) {
+
+				pivot = row
+
+			}
+
+		}
+
+		if (pivot != m) {
+
+			h[pivot], h[m] = h[m], h[pivot]
+
+			for row := range h {
+
+				h[row][pivot], h[row][m] = h[row][m], h[row][pivot]
+
+			}
+
+		}
+
+		if (h[m][m - 1] == 0.0) {
+
+			continue
+
+		}
+
+		for row := m + 1; row < size; row++ {
+
+			factor := h[row][m - 1] / h[m][m - 1]
+
+			if (factor == 0.0) {
+
+				continue
+
+			}
+
+			for column := m - 1; column < size; column++ {
+
+				h[row][column] -= factor*h[m][column]
+
+			}
+
+			for k := range h {
+
+				h[k][m] += factor*h[k][row]
+
+			}
+
+		}
+
+	}
+
+	ascending := make([][]float64, size + 1)
+	ascending[0] = []float64{ 1.0 }
+
+	for k := 1; k <= size; k++ {
+
+		next := make([]float64, k + 1)
+
+		for index, coefficient := range ascending[k - 1] {
+
+			next[index + 1] += coefficient
+			next[index] -= h[k - 1][k - 1]*coefficient
+
+		}
+
+		product := 1.0
+
+		for i := k - 1; i >= 1; i-- {
+
+			product *= h[i][i - 1]
+
+			if (product == 0.0) {
+
+				break
+
+			}
+
+			for index, coefficient := range ascending[i - 1] {
+
+				next[index] -= h[i - 1][k - 1]*product*coefficient
+
+			}
+
+		}
+
+		ascending[k] = next
+
+	}
+
+	descending := slices.Clone(ascending[size])
+	slices.Reverse(descending)
+	return descending
+
+}
+
+func lyapunov(a [][]float64, q [][]float64, domain Domain) [][]float64 {
+
+	size := len(a)
+	system := zeroMatrix(size*size, size*size)
+	vector := make([]float64, size*size)
+
+	for i := 0; i < size; i++ {
+
+		for j := 0; j < size; j++ {
+
+			row := i*size + j
+			vector[row] = -q[i][j]
+
+			if (domain == Digital) {
+
+				system[row][row] -= 1.0
+
+				for k := 0; k < size; k++ {
+
+					for l := 0; l < size; l++ {
+
+						system[row][k*size + l] += a[i][k]*a[j][l]
+
+					}
+
+				}
+
+			} else {
+
+				for k := 0; k < size; k++ {
+
+					system[row][k*size + j] += a[i][k]
+					system[row][i*size + k] += a[j][k]
+
+				}
+
+			}
+
+		}
+
+	}
+
+	solution := solveLinear(system, vector)
+	gramian := zeroMatrix(size, size)
+
+	for i := 0; i < size; i++ {
+
+		for j := 0; j < size; j++ {
+
+			gramian[i][j] = 0.5*(solution[i*size + j] + solution[j*size + i])
+
+		}
+
+	}
+
+	return gramian
+
+}
+
+func solveComplexLinear(matrix [][]complex128, vector []complex128) []complex128 {
+
+	size := len(vector)
+	a := make([][]complex128, size)
+
+	for row := range a {
+
+		a[row] = append(append([]complex128{}, matrix[row]...), vector[row])
+
+	}
+
+	for column := 0; column < size; column++ {
+
+		pivot := column
+
+		for row := column + 1; row < size; row++ {
+
+			if (cmplx.Abs(a[row][column]) > cmplx.Abs(a[pivot][column])) {
+
+				pivot = row
+
+			}
+
+		}
+
+		a[column], a[pivot] = a[pivot], a[column]
+
+		if (a[column][column] == 0) {
+
+			continue
+
+		}
+
+		for row := column + 1; row < size; row++ {
+
+			factor := a[row][column] / a[column][column]
+
+			for k := column; k <= size; k++ {
+
+				a[row][k] -= factor*a[column][k]
+
+			}
+
+		}
+
+	}
+
+	solution := make([]complex128, size)
+
+	for row := size - 1; row >= 0; row-- {
+
+		sum := a[row][size]
+
+		for k := row + 1; k < size; k++ {
+
+			sum -= a[row][k]*solution[k]
+
+		}
+
+		if (a[row][row] != 0) {
+
+			solution[row] = sum / a[row][row]
+
+		}
+
+	}
+
+	return solution
+
+}
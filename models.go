@@ -3,6 +3,8 @@ package main
 import ( "strings"
 		 "fmt"
 		 "math"
+		 "slices"
+		 "cmp"
 		 "strconv" )
 
 
@@ -31,54 +33,22 @@ type Term struct {
 
 }
 
-func (e *Expression) sort() {
-
-	orderedList, dc := findConstant(e.Terms)
-	cycles := len(orderedList)
-
-	if (cycles%2 == 0) {
-
-		cycles = cycles / 2
-
-	} else {
-
-		cycles = (cycles - 1) / 2
-
-	}
-
-	for address := 0; address < cycles; address++ {
-
-		front := address
-		back := len(orderedList[front:]) - address - 1
-		max := orderedList[front:back + 1][0]
-		last := len(orderedList[front:back + 1]) - 1
-		min := orderedList[front:back + 1][last]
-		position := back
-
-		for index, term := range orderedList[address:position + 1] {
+type Section struct {
 
-			if (term.Exponent >= max.Exponent) {
+	Numerator   [3]float64
+	Denominator [3]float64
 
-				max = term
-				front = index
-
-			}
-
-			if (term.Exponent <= min.Exponent) {
+}
 
-				min = term
-				back = index
+func (e *Expression) sort() {
 
-			}
+	orderedList, dc := findConstant(e.Terms)
 
-		}
+	slices.SortStableFunc(orderedList, func(p Term, q Term) int {
 
-		orderedList[front] = orderedList[address]
-		orderedList[back] = orderedList[position]
-		orderedList[address] = max
-		orderedList[position] = min
+		return cmp.Compare(q.Exponent, p.Exponent)
 
-	}
+	})
 
 	if (len(orderedList) < len(e.Terms)) {
 
@@ -113,6 +83,71 @@ func (p *Polynomial) multiply(q Polynomial) {
 
 }
 
+func (p *Polynomial) add(q Polynomial) {
+
+	if ((len(p.Numerator.Terms) > 0) &&
+		(len(p.Denominator.Terms) > 0) &&
+		(len(q.Numerator.Terms) > 0) &&
+		(len(q.Denominator.Terms) > 0)) {
+
+		s := p.Denominator.Terms[0].Variable
+
+		if equalExpressions(p.Denominator, q.Denominator) {
+
+			expression := accumulate(p.Numerator.Terms, q.Numerator.Terms)
+			p.Numerator.transform(expression, s)
+			p.Numerator.expand()
+			p.Numerator.build()
+
+		} else {
+
+			left := dotProduct(p.Numerator.Terms, q.Denominator.Terms)
+			right := dotProduct(q.Numerator.Terms, p.Denominator.Terms)
+			expression := dotProduct(p.Denominator.Terms, q.Denominator.Terms)
+
+			for exponent, coefficient := range right {
+
+				left[exponent] += coefficient
+
+			}
+
+			p.Numerator.transform(left, s)
+			p.Numerator.expand()
+			p.Numerator.build()
+			p.Denominator.transform(expression, s)
+			p.Denominator.expand()
+			p.Denominator.build()
+
+		}
+
+		p.build()
+
+	}
+
+}
+
+func (p *Polynomial) scale(gain float64) {
+
+	if (len(p.Numerator.Terms) > 0) {
+
+		s := p.Numerator.Terms[0].Variable
+		expression := map[int64]float64{}
+
+		for _, term := range p.Numerator.Terms {
+
+			expression[term.Exponent] += gain*term.Coefficient
+
+		}
+
+		p.Numerator.transform(expression, s)
+		p.Numerator.expand()
+		p.Numerator.build()
+		p.build()
+
+	}
+
+}
+
 func (e *Expression) build() {
 
 	terms := ""
@@ -213,46 +248,50 @@ func (p *Polynomial) build() {
 
 	if !constant {
 
-		lengthNumerator := len(p.Numerator.Representation)
-		lengthDenominator := len(p.Denominator.Representation)
-		lengthLine := max(lengthNumerator, lengthDenominator)
-		line := ""
+		p.TransferFunction = stackedFraction(p.Numerator.Representation, p.Denominator.Representation)
 
-		for index := 0; index < lengthLine + 4; index++ {
+	}
 
-			line += "-"
+}
 
-		}
+func stackedFraction(numerator string, denominator string) string {
 
-		centered := p.Numerator.Representation
+	lengthNumerator := len(numerator)
+	lengthDenominator := len(denominator)
+	lengthLine := max(lengthNumerator, lengthDenominator)
+	line := ""
 
-		if (lengthLine == lengthNumerator) {
+	for index := 0; index < lengthLine + 4; index++ {
 
-			centered = p.Denominator.Representation
+		line += "-"
 
-		}
+	}
 
-		delta := len(line) - len(centered)
-		empty := math.Floor(float64(delta / 2))
+	centered := numerator
 
-		for index := 0.0; index < empty; index++ {
+	if (lengthLine == lengthNumerator) {
 
-			centered = " " + centered
+		centered = denominator
 
-		}
+	}
 
-		if (lengthLine == lengthNumerator) {
+	delta := len(line) - len(centered)
+	empty := math.Floor(float64(delta / 2))
 
-			p.TransferFunction = fmt.Sprintf("  %s\n%s\n%s", p.Numerator.Representation, line, centered)
+	for index := 0.0; index < empty; index++ {
 
-		} else {
+		centered = " " + centered
 
-			p.TransferFunction = fmt.Sprintf("%s\n%s\n  %s", centered, line, p.Denominator.Representation)
+	}
 
-		}
+	if (lengthLine == lengthNumerator) {
+
+		return fmt.Sprintf("  %s\n%s\n%s", numerator, line, centered)
 
 	}
 
+	return fmt.Sprintf("%s\n%s\n  %s", centered, line, denominator)
+
 }
 
 func (p *Polynomial) representation(label ...string) {
@@ -342,7 +381,7 @@ func (e *Expression) transform(expression map[int64]float64, variable string) {
 
 	e.Reduction = map[int64]float64{}
 	e.Expansion = map[int64]float64{}
-	e.Terms = []Term
+	e.Terms = []Term{}
 
 	for exponent, coefficient := range expression {
 
@@ -394,6 +433,61 @@ func (p *Polynomial) evaluate(s float64) float64 {
 
 }
 
+func (e *Expression) response(x complex128) complex128 {
+
+	y := complex(0, 0)
+
+	for _, term := range e.Terms {
+
+		y += complex(term.Coefficient, 0)*complexPower(x, term.Exponent)
+
+	}
+
+	return y
+
+}
+
+func (e *Expression) derivative(x complex128) complex128 {
+
+	y := complex(0, 0)
+
+	for _, term := range e.Terms {
+
+		if (term.Exponent != 0) {
+
+			coefficient := term.Coefficient*float64(term.Exponent)
+			y += complex(coefficient, 0)*complexPower(x, term.Exponent - 1)
+
+		}
+
+	}
+
+	return y
+
+}
+
+func (p *Polynomial) response(x complex128) complex128 {
+
+	numerator := p.Numerator.response(x)
+	denominator := p.Denominator.response(x)
+	quotient := complex(0, 0)
+
+	if (denominator != 0) {
+
+		quotient = numerator / denominator
+
+	}
+
+	return quotient
+
+}
+
+func (s Section) polynomial() Polynomial {
+
+	return digitalPolynomial(s.Numerator[:], s.Denominator[:])
+
+}
+
 
 // config
 type Specs struct {
@@ -413,9 +507,18 @@ type Specs struct {
 	UpperStopbandEdgeFrequency *float64
 	Bandwidth				   *float64
 	CenterFrequency			   *float64
+	QualityFactor			   *float64
+	Gain					   *float64
 	TransitionWidth			   *float64
 	SamplingFrequency		   *float64
 	Order					   *uint16
+	Harmonics				   *uint16
+	SourceImpedance			   *float64
+	LoadImpedance			   *float64
+	Expansion				   Expansion
+	Topology				   Topology
+	Capacitance				   *float64
+	GainBandwidth			   *float64
 
 }
 
@@ -455,6 +558,7 @@ const (
 
 	BRF   Response = "brf"
 	Notch Response = "notch"
+	Peak  Response = "peak"
 
 )
 
@@ -466,7 +570,7 @@ func (r Response) exists() bool {
 
 			return true
 
-		case BRF, Notch:
+		case BRF, Notch, Peak:
 
 			return true
 
@@ -558,3 +662,566 @@ func (c Configuration) exists() bool {
 	}
 
 }
+
+type Cookbook struct {
+
+	Shape			  Biquad
+	Frequency		  float64
+	SamplingFrequency float64
+	Gain			  *float64
+	QualityFactor	  *float64
+	Slope			  *float64
+	Bandwidth		  *float64
+
+}
+
+type Biquad string
+
+const (
+
+	LowShelf			  Biquad = "low shelf"
+	HighShelf			  Biquad = "high shelf"
+	PeakingEQ			  Biquad = "peaking eq"
+	BandPassConstantSkirt Biquad = "band pass constant skirt"
+	BandPassConstantPeak  Biquad = "band pass constant peak"
+	AllPass				  Biquad = "all pass"
+
+	FirstOrderLowShelf	  Biquad = "first order low shelf"
+	FirstOrderHighShelf	  Biquad = "first order high shelf"
+
+)
+
+func (b Biquad) exists() bool {
+
+	switch b {
+
+		case LowShelf, HighShelf, PeakingEQ, AllPass:
+
+			return true
+
+		case BandPassConstantSkirt, BandPassConstantPeak:
+
+			return true
+
+		case FirstOrderLowShelf, FirstOrderHighShelf:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Crossover struct {
+
+	Domain				Domain
+	Approximation		Approximation
+	Complementarity		Complementarity
+	Frequencies			[]float64
+	Order				uint16
+	PassbandAttenuation *float64
+	StopbandAttenuation *float64
+	SamplingFrequency	*float64
+
+}
+
+type Complementarity string
+
+const (
+
+	Magnitude Complementarity = "magnitude"
+	Power	  Complementarity = "power"
+
+	LinkwitzRiley Complementarity = "linkwitz riley"
+
+)
+
+func (c Complementarity) exists() bool {
+
+	switch c {
+
+		case Magnitude, Power:
+
+			return true
+
+		case LinkwitzRiley:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type CIC struct {
+
+	Stages			  uint16
+	DifferentialDelay uint16
+	Rate			  uint16
+	SamplingFrequency float64
+	Interpolator	  bool
+
+}
+
+type Structure string
+
+const (
+
+	DirectFormI			   Structure = "direct form i"
+	DirectFormII		   Structure = "direct form ii"
+	TransposedDirectFormII Structure = "transposed direct form ii"
+	SecondOrderSections	   Structure = "second order sections"
+	SymmetricFolded		   Structure = "symmetric folded"
+
+)
+
+func (s Structure) exists() bool {
+
+	switch s {
+
+		case DirectFormI, DirectFormII:
+
+			return true
+
+		case TransposedDirectFormII, SecondOrderSections:
+
+			return true
+
+		case SymmetricFolded:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Padding string
+
+const (
+
+	Odd		 Padding = "odd"
+	Even	 Padding = "even"
+	Constant Padding = "constant"
+	None	 Padding = "none"
+
+)
+
+func (p Padding) exists() bool {
+
+	switch p {
+
+		case Odd, Even, Constant, None:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Format struct {
+
+	Integer  uint16
+	Fraction uint16
+
+}
+
+type Rounding string
+
+const (
+
+	Nearest	   Rounding = "nearest"
+	Truncate   Rounding = "truncate"
+	TowardZero Rounding = "toward zero"
+	Convergent Rounding = "convergent"
+
+)
+
+func (r Rounding) exists() bool {
+
+	switch r {
+
+		case Nearest, Truncate:
+
+			return true
+
+		case TowardZero, Convergent:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Norm string
+
+const (
+
+	L1		  Norm = "l1"
+	L2		  Norm = "l2"
+	LInfinity Norm = "l infinity"
+
+)
+
+func (n Norm) exists() bool {
+
+	switch n {
+
+		case L1, L2, LInfinity:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Datapath struct {
+
+	Coefficient	  Format
+	Data		  Format
+	Accumulator	  uint16
+	Overflow	  Overflow
+	Rounding	  Rounding
+	RoundProducts bool
+
+}
+
+type Overflow string
+
+const (
+
+	Saturate Overflow = "saturate"
+	Wrap	 Overflow = "wrap"
+
+)
+
+func (o Overflow) exists() bool {
+
+	switch o {
+
+		case Saturate, Wrap:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Codegen struct {
+
+	Name	  string
+	Package	  string
+	Precision Precision
+	Language  Language
+	Structure Structure
+	Datapath  Datapath
+	Samples	  int
+
+}
+
+type Precision string
+
+const (
+
+	Single Precision = "float"
+	Double Precision = "double"
+	Fixed  Precision = "fixed"
+
+)
+
+func (p Precision) exists() bool {
+
+	switch p {
+
+		case Single, Double, Fixed:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Language string
+
+const (
+
+	Verilog Language = "verilog"
+	VHDL	Language = "vhdl"
+
+)
+
+func (l Language) exists() bool {
+
+	switch l {
+
+		case Verilog, VHDL:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Realization string
+
+const (
+
+	Controllable Realization = "controllable"
+	Observable	 Realization = "observable"
+	Modal		 Realization = "modal"
+	Balanced	 Realization = "balanced"
+
+)
+
+func (r Realization) exists() bool {
+
+	switch r {
+
+		case Controllable, Observable, Modal, Balanced:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Expansion string
+
+const (
+
+	CauerI	Expansion = "cauer i"
+	CauerII Expansion = "cauer ii"
+
+)
+
+func (e Expansion) exists() bool {
+
+	switch e {
+
+		case CauerI, CauerII:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Topology string
+
+const (
+
+	FirstOrder		 Topology = "first order"
+	SallenKeyUnity	 Topology = "sallen-key unity gain"
+	SallenKeyEqual	 Topology = "sallen-key equal component"
+	MultipleFeedback Topology = "multiple feedback"
+	StateVariable	 Topology = "state variable"
+	TowThomas		 Topology = "tow-thomas"
+
+)
+
+func (t Topology) exists() bool {
+
+	switch t {
+
+		case FirstOrder, SallenKeyUnity, SallenKeyEqual:
+
+			return true
+
+		case MultipleFeedback, StateVariable, TowThomas:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Series string
+
+const (
+
+	E6	 Series = "e6"
+	E12	 Series = "e12"
+	E24	 Series = "e24"
+	E48	 Series = "e48"
+	E96	 Series = "e96"
+	E192 Series = "e192"
+
+)
+
+func (s Series) exists() bool {
+
+	switch s {
+
+		case E6, E12, E24:
+
+			return true
+
+		case E48, E96, E192:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Distribution string
+
+const (
+
+	Uniform	 Distribution = "uniform"
+	Gaussian Distribution = "gaussian"
+
+)
+
+func (d Distribution) exists() bool {
+
+	switch d {
+
+		case Uniform, Gaussian:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Simulation struct {
+
+	Name	  string
+	Start	  *float64
+	Stop	  *float64
+	Points	  int
+	Step	  *float64
+	Duration  *float64
+	Amplifier string
+	Include	  string
+
+}
+
+type Touchstone struct {
+
+	Start		*float64
+	Stop		*float64
+	Points		int
+	Logarithmic bool
+	Notation	Notation
+	InductorQ	*float64
+	CapacitorQ	*float64
+
+}
+
+type Notation string
+
+const (
+
+	MagnitudeAngle Notation = "ma"
+	DecibelAngle   Notation = "db"
+	RealImaginary  Notation = "ri"
+
+)
+
+func (n Notation) exists() bool {
+
+	switch n {
+
+		case MagnitudeAngle, DecibelAngle, RealImaginary:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
+
+type Formatting struct {
+
+	Markup	 Markup
+	Digits	 *int
+	Variable string
+	Label	 string
+	Factored bool
+
+}
+
+type Markup string
+
+const (
+
+	Unicode Markup = "unicode"
+	ASCII	Markup = "ascii"
+	LaTeX	Markup = "latex"
+	MathML	Markup = "mathml"
+
+)
+
+func (m Markup) exists() bool {
+
+	switch m {
+
+		case Unicode, ASCII, LaTeX, MathML:
+
+			return true
+
+		default:
+
+			return false
+
+	}
+
+}
@@ -0,0 +1,773 @@
+package main
+
+import ( "fmt"
+		 "math"
+		 "math/cmplx"
+		 "math/rand/v2"
+		 "runtime"
+		 "slices"
+		 "sync" )
+
+
+type Tolerance struct {
+
+	Resistors	 *float64
+	Capacitors	 *float64
+	Inductors	 *float64
+	Coefficients *float64
+	Distribution Distribution
+	Trials		 int
+	Workers		 int
+	Seed		 uint64
+
+}
+
+type MonteCarlo struct {
+
+	Trials	   int
+	Passed	   int
+	Yield	   float64
+	Deviations []Deviation
+
+}
+
+type Sensitivity struct {
+
+	Stage	  int
+	Component string
+	Frequency float64
+	Q		  float64
+
+}
+
+func (t Tolerance) values() (float64, float64, float64, float64) {
+
+	resistors, capacitors, inductors, coefficients := 0.01, 0.05, 0.05, 0.001
+
+	if (t.Resistors != nil) {
+
+		resistors = math.Abs(*t.Resistors)
+
+	}
+
+	if (t.Capacitors != nil) {
+
+		capacitors = math.Abs(*t.Capacitors)
+
+	}
+
+	if (t.Inductors != nil) {
+
+		inductors = math.Abs(*t.Inductors)
+
+	}
+
+	if (t.Coefficients != nil) {
+
+		coefficients = math.Abs(*t.Coefficients)
+
+	}
+
+	return resistors, capacitors, inductors, coefficients
+
+}
+
+func (t Tolerance) perturb(value float64, tolerance float64, random *rand.Rand) float64 {
+
+	if (t.Distribution == Gaussian) {
+
+		return value*(1.0 + tolerance / 3.0*random.NormFloat64())
+
+	}
+
+	return value*(1.0 + tolerance*(2.0*random.Float64() - 1.0))
+
+}
+
+func stopbandEdges(config Specs) [][2]float64 {
+
+	cutoffFrequency, _, _ := analogueFrequencies(config)
+	edges := []float64{}
+
+	for _, edge := range []*float64{ config.LowerStopbandEdgeFrequency, config.UpperStopbandEdgeFrequency } {
+
+		if ((edge != nil) && (*edge > 0.0)) {
+
+			edges = append(edges, *edge)
+
+		}
+
+	}
+
+	stopbands := [][2]float64{}
+
+	switch config.Response {
+
+		case BPF:
+
+			if (len(edges) == 2) {
+
+				stopbands = append(stopbands, [2]float64{ math.Min(edges[0], edges[1]) / 10.0, math.Min(edges[0], edges[1]) })
+				stopbands = append(stopbands, [2]float64{ math.Max(edges[0], edges[1]), 10.0*math.Max(edges[0], edges[1]) })
+
+			}
+
+		case BSF, BRF:
+
+			if (len(edges) == 2) {
+
+				stopbands = append(stopbands, [2]float64{ math.Min(edges[0], edges[1]), math.Max(edges[0], edges[1]) })
+
+			}
+
+		case HPF:
+
+			for _, edge := range edges {
+
+				if (edge < cutoffFrequency) {
+
+					stopbands = append(stopbands, [2]float64{ edge / 10.0, edge })
+
+				}
+
+			}
+
+		default:
+
+			for _, edge := range edges {
+
+				if (edge > cutoffFrequency) {
+
+					stopbands = append(stopbands, [2]float64{ edge, 10.0*edge })
+
+				}
+
+			}
+
+	}
+
+	return stopbands
+
+}
+
+func meetsSpecs(config Specs, response func(float64) complex128) bool {
+
+	passbandLoss := 10.0*math.Log10(2.0)
+
+	if (config.PassbandAttenuation != nil) {
+
+		passbandLoss = math.Abs(*config.PassbandAttenuation)
+
+	}
+
+	_, passbands := passbandEdges(config)
+	peak := math.Inf(-1)
+	floor := math.Inf(1)
+
+	for _, band := range passbands {
+
+		for _, exponent := range linearSpace(math.Log10(band[0]), math.Log10(band[1]), 401) {
+
+			level := decibels(cmplx.Abs(response(math.Pow(10, exponent))))
+			peak = math.Max(peak, level)
+			floor = math.Min(floor, level)
+
+		}
+
+	}
+
+	if (peak - floor > passbandLoss + 1e-9) {
+
+		return false
+
+	}
+
+	if (config.StopbandAttenuation == nil) {
+
+		return true
+
+	}
+
+	for _, band := range stopbandEdges(config) {
+
+		for _, exponent := range linearSpace(math.Log10(band[0]), math.Log10(band[1]), 401) {
+
+			if (peak - decibels(cmplx.Abs(response(math.Pow(10, exponent)))) < math.Abs(*config.StopbandAttenuation)) {
+
+				return false
+
+			}
+
+		}
+
+	}
+
+	return true
+
+}
+
+func runMonteCarlo(tolerance Tolerance, trial func(random *rand.Rand) (bool, Deviation)) MonteCarlo {
+
+	trials := tolerance.Trials
+
+	if (trials <= 0) {
+
+		trials = 1000
+
+	}
+
+	workers := tolerance.Workers
+
+	if (workers <= 0) {
+
+		workers = runtime.NumCPU()
+
+	}
+
+	passed := make([]bool, trials)
+	result := MonteCarlo{ Trials: trials, Deviations: make([]Deviation, trials) }
+	var wait sync.WaitGroup
+
+	for worker := 0; worker < min(workers, trials); worker++ {
+
+		wait.Add(1)
+
+		go func() {
+
+			defer wait.Done()
+
+			for index := worker; index < trials; index += workers {
+
+				random := rand.New(rand.NewPCG(tolerance.Seed, uint64(index)))
+				passed[index], result.Deviations[index] = trial(random)
+
+			}
+
+		}()
+
+	}
+
+	wait.Wait()
+
+	for _, pass := range passed {
+
+		if pass {
+
+			result.Passed++
+
+		}
+
+	}
+
+	result.Yield = float64(result.Passed) / float64(trials)
+	return result
+
+}
+
+func (a ActiveFilter) monteCarlo(config Specs, tolerance Tolerance) MonteCarlo {
+
+	resistors, capacitors, _, _ := tolerance.values()
+	poles := a.poles()
+
+	return runMonteCarlo(tolerance, func(random *rand.Rand) (bool, Deviation) {
+
+		realized := ActiveFilter{ Stages: slices.Clone(a.Stages), GainBandwidth: a.GainBandwidth }
+
+		for index := range realized.Stages {
+
+			stage := &realized.Stages[index]
+			stage.Resistors = slices.Clone(stage.Resistors)
+			stage.Capacitors = slices.Clone(stage.Capacitors)
+
+			for component := range stage.Resistors {
+
+				stage.Resistors[component] = tolerance.perturb(stage.Resistors[component], resistors, random)
+
+			}
+
+			for component := range stage.Capacitors {
+
+				stage.Capacitors[component] = tolerance.perturb(stage.Capacitors[component], capacitors, random)
+
+			}
+
+		}
+
+		return meetsSpecs(config, realized.response), measureDeviation(config, a.response, realized.response, poles, realized.poles())
+
+	})
+
+}
+
+func (l Ladder) monteCarlo(config Specs, tolerance Tolerance) MonteCarlo {
+
+	_, capacitors, inductors, _ := tolerance.values()
+	poles := l.poles()
+
+	return runMonteCarlo(tolerance, func(random *rand.Rand) (bool, Deviation) {
+
+		realized := Ladder{ Elements: slices.Clone(l.Elements), Source: l.Source, Load: l.Load }
+
+		for index := range realized.Elements {
+
+			element := &realized.Elements[index]
+			element.Inductance = tolerance.perturb(element.Inductance, inductors, random)
+			element.Capacitance = tolerance.perturb(element.Capacitance, capacitors, random)
+
+		}
+
+		return meetsSpecs(config, realized.response), measureDeviation(config, l.response, realized.response, poles, realized.poles())
+
+	})
+
+}
+
+func (p *Polynomial) monteCarlo(config Specs, tolerance Tolerance) MonteCarlo {
+
+	_, _, _, coefficients := tolerance.values()
+	numerator, denominator, domain, _ := transferCoefficients(*p)
+	samplingFrequency := []float64{}
+
+	if (domain == Digital) {
+
+		samplingFrequency = append(samplingFrequency, 48000.0)
+
+		if ((config.SamplingFrequency != nil) && (*config.SamplingFrequency > 0.0)) {
+
+			samplingFrequency[0] = *config.SamplingFrequency
+
+		}
+
+	}
+
+	rebuild := func(numerator []float64, denominator []float64) Polynomial {
+
+		if (domain == Digital) {
+
+			return digitalPolynomial(numerator, denominator)
+
+		}
+
+		return analoguePolynomial(numerator, denominator)
+
+	}
+
+	poles := func(denominator []float64) []complex128 {
+
+		roots := polynomialRoots(denominator)
+
+		if (domain == Digital) {
+
+			for index, root := range roots {
+
+				roots[index] = cmplx.Log(root)*complex(samplingFrequency[0], 0)
+
+			}
+
+		}
+
+		return roots
+
+	}
+
+	nominal := rebuild(numerator, denominator)
+	ideal := func(frequency float64) complex128 { return frequencyResponse(nominal, frequency, samplingFrequency...) }
+
+	return runMonteCarlo(tolerance, func(random *rand.Rand) (bool, Deviation) {
+
+		top := slices.Clone(numerator)
+		bottom := slices.Clone(denominator)
+
+		for index := range top {
+
+			top[index] = tolerance.perturb(top[index], coefficients, random)
+
+		}
+
+		for index := range bottom {
+
+			bottom[index] = tolerance.perturb(bottom[index], coefficients, random)
+
+		}
+
+		perturbed := rebuild(top, bottom)
+		realized := func(frequency float64) complex128 { return frequencyResponse(perturbed, frequency, samplingFrequency...) }
+		return meetsSpecs(config, realized), measureDeviation(config, ideal, realized, poles(denominator), poles(bottom))
+
+	})
+
+}
+
+type monomial struct {
+
+	coefficient float64
+	powers		map[string]int
+
+}
+
+func (s Stage) denominatorTerms() [][2][]monomial {
+
+	term := func(coefficient float64, names ...string) monomial {
+
+		powers := map[string]int{}
+
+		for _, name := range names {
+
+			if (name[0] == '/') {
+
+				powers[name[1:]]--
+
+			} else {
+
+				powers[name]++
+
+			}
+
+		}
+
+		return monomial{ coefficient, powers }
+
+	}
+
+	one := []monomial{ term(1.0) }
+	equal := (s.Topology == SallenKeyEqual)
+
+	switch s.Topology {
+
+		case FirstOrder:
+
+			return [][2][]monomial{ { { term(1.0, "R1", "C1") }, one }, { one, one } }
+
+		case SallenKeyUnity, SallenKeyEqual:
+
+			second := []monomial{ term(1.0, "R1", "R2", "C1", "C2") }
+			first := []monomial{ term(1.0, "R1", "C2"), term(1.0, "R2", "C2") }
+
+			if equal {
+
+				first = append(first, term(-1.0, "R1", "C1", "R4", "/R3"))
+
+			}
+
+			if (s.Response == HPF) {
+
+				first = []monomial{ term(1.0, "R1", "C1"), term(1.0, "R1", "C2") }
+
+				if equal {
+
+					first = append(first, term(-1.0, "R2", "C2", "R4", "/R3"))
+
+				}
+
+				// the input divider acts as C1 + C3
+				if (len(s.Capacitors) > 2) {
+
+					second = append(second, term(1.0, "R1", "R2", "C3", "C2"))
+					first = append(first, term(1.0, "R1", "C3"))
+
+				}
+
+			} else if (len(s.Resistors) > 4) {
+
+				// the input divider acts as R1 R5 / (R1 + R5), so every coefficient is taken over R1 + R5
+				divider := []monomial{ term(1.0, "R1"), term(1.0, "R5") }
+				second = []monomial{ term(1.0, "R1", "R5", "R2", "C1", "C2") }
+				first = []monomial{ term(1.0, "R1", "R5", "C2"), term(1.0, "R2", "C2", "R1"), term(1.0, "R2", "C2", "R5"), term(-1.0, "R1", "R5", "C1", "R4", "/R3") }
+				return [][2][]monomial{ { second, divider }, { first, divider }, { one, one } }
+
+			}
+
+			return [][2][]monomial{ { second, one }, { first, one }, { one, one } }
+
+		case MultipleFeedback:
+
+			switch s.Response {
+
+				case HPF:
+
+					return [][2][]monomial{ { { term(1.0, "C2", "C3", "R1", "R2") }, one },
+											{ { term(1.0, "R1", "C1"), term(1.0, "R1", "C2"), term(1.0, "R1", "C3") }, one },
+											{ one, one } }
+
+				case BPF:
+
+					return [][2][]monomial{ { { term(1.0, "C1", "C2", "R1", "R3") }, one },
+											{ { term(1.0, "R1", "C1"), term(1.0, "R1", "C2") }, one },
+											{ { term(1.0), term(1.0, "R1", "/R2") }, one } }
+
+			}
+
+			return [][2][]monomial{ { { term(1.0, "C1", "C2", "R2", "R3") }, one },
+									{ { term(1.0, "C1", "R2"), term(1.0, "C1", "R3"), term(1.0, "C1", "R2", "R3", "/R1") }, one },
+									{ one, one } }
+
+		case TowThomas:
+
+			return [][2][]monomial{ { { term(1.0, "C1", "C2", "R3", "R4") }, one },
+									{ { term(1.0, "R3", "R4", "C2", "/R2") }, one },
+									{ { term(1.0, "R6", "/R5") }, one } }
+
+		case StateVariable:
+
+			return [][2][]monomial{ { { term(1.0, "R6", "C1", "R7", "C2") }, one },
+									{ { term(1.0, "R5", "R7", "C2"), term(1.0, "R3", "R5", "R7", "C2", "/R1"), term(1.0, "R3", "R5", "R7", "C2", "/R2") },
+									  { term(1.0, "R4"), term(1.0, "R5") } },
+									{ { term(1.0, "R3", "/R2") }, one } }
+
+	}
+
+	return [][2][]monomial{}
+
+}
+
+func (s Stage) coefficientSensitivity(coefficient [2][]monomial, name string) float64 {
+
+	values := map[string]float64{}
+
+	for index, value := range s.Resistors {
+
+		values[fmt.Sprintf("R%d", index + 1)] = value
+
+	}
+
+	for index, value := range s.Capacitors {
+
+		values[fmt.Sprintf("C%d", index + 1)] = value
+
+	}
+
+	// x/P dP/dx of a sum of monomials is the power-weighted mean of its terms
+	sensitivity := func(terms []monomial) float64 {
+
+		total := 0.0
+		weighted := 0.0
+
+		for _, term := range terms {
+
+			value := term.coefficient
+
+			for component, power := range term.powers {
+
+				value *= math.Pow(values[component], float64(power))
+
+			}
+
+			total += value
+			weighted += float64(term.powers[name])*value
+
+		}
+
+		return weighted / total
+
+	}
+
+	return sensitivity(coefficient[0]) - sensitivity(coefficient[1])
+
+}
+
+func (a ActiveFilter) sensitivity() []Sensitivity {
+
+	sensitivities := []Sensitivity{}
+
+	for index, stage := range a.Stages {
+
+		terms := stage.denominatorTerms()
+
+		if (len(terms) < 2) {
+
+			continue
+
+		}
+
+		for kind, values := range [][]float64{ stage.Resistors, stage.Capacitors } {
+
+			for component := range values {
+
+				name := fmt.Sprintf("%s%d", []string{ "R", "C" }[kind], component + 1)
+				leading := stage.coefficientSensitivity(terms[0], name)
+				constant := stage.coefficientSensitivity(terms[len(terms) - 1], name)
+
+				// w0 = (d0/d1) for first order, w0^2 = d0/d2 and Q = sqrt(d0*d2)/d1 for second order
+				frequency := constant - leading
+				q := 0.0
+
+				if (len(terms) == 3) {
+
+					frequency = 0.5*(constant - leading)
+					q = 0.5*(constant + leading) - stage.coefficientSensitivity(terms[1], name)
+
+				}
+
+				sensitivities = append(sensitivities, Sensitivity{ Stage: index, Component: name, Frequency: frequency, Q: q })
+
+			}
+
+		}
+
+	}
+
+	return sensitivities
+
+}
+
+func (e Element) derivatives(s complex128) (complex128, complex128, complex128, complex128) {
+
+	// impedance, dZ/ds, L dZ/dL and C dZ/dC of the lossless element
+	value, slope, inductance, capacitance := complex(0, 0), complex(0, 0), complex(0, 0), complex(0, 0)
+
+	if (e.Inductance > 0.0) {
+
+		l := complex(e.Inductance, 0)
+
+		if e.Parallel {
+
+			value, slope, inductance = 1.0 / (s*l), -1.0 / (s*s*l), -1.0 / (s*l)
+
+		} else {
+
+			value, slope, inductance = s*l, l, s*l
+
+		}
+
+	}
+
+	if (e.Capacitance > 0.0) {
+
+		c := complex(e.Capacitance, 0)
+
+		if e.Parallel {
+
+			value, slope, capacitance = value + s*c, slope + c, s*c
+
+		} else {
+
+			value, slope, capacitance = value + 1.0 / (s*c), slope - 1.0 / (s*s*c), -1.0 / (s*c)
+
+		}
+
+	}
+
+	if e.Parallel {
+
+		// the sums above are admittances, dZ = -dY / Y^2
+		return 1.0 / value, -slope / (value*value), -inductance / (value*value), -capacitance / (value*value)
+
+	}
+
+	return value, slope, inductance, capacitance
+
+}
+
+func (l Ladder) poleDerivatives(s complex128, target int, kind int) (complex128, complex128) {
+
+	// forward-mode derivatives of the chain denominator a*RL + b + RS*(c*RL + d)
+	// with respect to s and to the logarithm of one element value
+	chain := [4]complex128{ 1, 0, 0, 1 }
+	slope := [4]complex128{}
+	scaled := [4]complex128{}
+
+	for index, element := range l.Elements {
+
+		value, dvalue, inductance, capacitance := element.derivatives(s)
+		dscaled := complex(0, 0)
+
+		if (index == target) {
+
+			dscaled = []complex128{ inductance, capacitance }[kind]
+
+		}
+
+		if element.Shunt {
+
+			admittance := 1.0 / value
+			dslope := -dvalue*admittance*admittance
+			dscaled = -dscaled*admittance*admittance
+			slope[0], slope[2] = slope[0] + slope[1]*admittance + chain[1]*dslope, slope[2] + slope[3]*admittance + chain[3]*dslope
+			scaled[0], scaled[2] = scaled[0] + scaled[1]*admittance + chain[1]*dscaled, scaled[2] + scaled[3]*admittance + chain[3]*dscaled
+			chain[0], chain[2] = chain[0] + chain[1]*admittance, chain[2] + chain[3]*admittance
+
+		} else {
+
+			slope[1], slope[3] = slope[0]*value + chain[0]*dvalue + slope[1], slope[2]*value + chain[2]*dvalue + slope[3]
+			scaled[1], scaled[3] = scaled[0]*value + chain[0]*dscaled + scaled[1], scaled[2]*value + chain[2]*dscaled + scaled[3]
+			chain[1], chain[3] = chain[0]*value + chain[1], chain[2]*value + chain[3]
+
+		}
+
+	}
+
+	source := complex(l.Source, 0)
+	load := complex(l.Load, 0)
+	combine := func(v [4]complex128) complex128 { return v[0]*load + v[1] + source*(v[2]*load + v[3]) }
+	return combine(slope), combine(scaled)
+
+}
+
+func (l Ladder) sensitivity() []Sensitivity {
+
+	nominal := []complex128{}
+
+	for _, pole := range l.poles() {
+
+		if (imag(pole) >= 0.0) {
+
+			nominal = append(nominal, pole)
+
+		}
+
+	}
+
+	slices.SortFunc(nominal, func(p complex128, q complex128) int {
+
+		if (cmplx.Abs(p) < cmplx.Abs(q)) {
+
+			return -1
+
+		} else if (cmplx.Abs(p) > cmplx.Abs(q)) {
+
+			return 1
+
+		}
+
+		return 0
+
+	})
+
+	sensitivities := []Sensitivity{}
+
+	for index, element := range l.Elements {
+
+		for kind, value := range []float64{ element.Inductance, element.Capacitance } {
+
+			if (value <= 0.0) {
+
+				continue
+
+			}
+
+			for pair, pole := range nominal {
+
+				// the pole moves by x dp/dx = -(x dF/dx) / (dF/ds), with w0 = |p| and Q = |p| / (-2 Re p)
+				slope, scaled := l.poleDerivatives(pole, index, kind)
+				shift := -scaled / slope
+				frequency := real(cmplx.Conj(pole)*shift) / (cmplx.Abs(pole)*cmplx.Abs(pole))
+				q := 0.0
+
+				if (imag(pole) > 1e-9*cmplx.Abs(pole)) {
+
+					q = frequency - real(shift) / real(pole)
+
+				}
+
+				name := fmt.Sprintf("%s%d", []string{ "L", "C" }[kind], index + 1)
+				sensitivities = append(sensitivities, Sensitivity{ Stage: pair, Component: name, Frequency: frequency, Q: q })
+
+			}
+
+		}
+
+	}
+
+	return sensitivities
+
+}
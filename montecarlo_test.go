@@ -0,0 +1,221 @@
+package main
+
+import ( "cmp"
+		 "math"
+		 "math/cmplx"
+		 "slices"
+		 "testing" )
+
+
+// central differences of ln w0 and ln Q against ln x for one resistor (kind 0) or capacitor (kind 1)
+func stageDifference(stage Stage, kind int, component int) (float64, float64) {
+
+	evaluate := func(scale float64) (float64, float64) {
+
+		perturbed := stage
+		perturbed.Resistors = slices.Clone(stage.Resistors)
+		perturbed.Capacitors = slices.Clone(stage.Capacitors)
+		[][]float64{ perturbed.Resistors, perturbed.Capacitors }[kind][component] *= scale
+		_, d := perturbed.coefficients()
+
+		if (len(d) == 2) {
+
+			return d[1] / d[0], 1.0
+
+		}
+
+		return math.Sqrt(d[2] / d[0]), math.Sqrt(d[0]*d[2]) / d[1]
+
+	}
+
+	h := 1e-6
+	upperFrequency, upperQ := evaluate(math.Exp(h))
+	lowerFrequency, lowerQ := evaluate(math.Exp(-h))
+	return math.Log(upperFrequency / lowerFrequency) / (2.0*h), math.Log(upperQ / lowerQ) / (2.0*h)
+
+}
+
+func TestActiveSensitivity(t *testing.T) {
+
+	for _, topology := range []Topology{ SallenKeyUnity, SallenKeyEqual, MultipleFeedback, TowThomas, StateVariable } {
+
+		for _, response := range []Response{ LPF, HPF, BPF } {
+
+			filter, _ := designActive(Specs{ Response: response, Approximation: Chebyshev, Order: pointer(uint16(5)), PassbandAttenuation: pointer(1.0), CutoffFrequency: pointer(1000.0), CenterFrequency: pointer(1000.0), Bandwidth: pointer(300.0), Topology: topology })
+			sensitivities := filter.sensitivity()
+			index := 0
+
+			for number, stage := range filter.Stages {
+
+				if (len(stage.denominatorTerms()) < 2) {
+
+					continue
+
+				}
+
+				for kind, values := range [][]float64{ stage.Resistors, stage.Capacitors } {
+
+					for component := range values {
+
+						frequency, q := stageDifference(stage, kind, component)
+						got := sensitivities[index]
+						index++
+
+						if ((got.Stage != number) || (math.Abs(got.Frequency - frequency) > 1e-5) || (math.Abs(got.Q - q) > 1e-5)) {
+
+							t.Errorf("%s %s stage %d %s: got (%g, %g), finite differences give (%g, %g)", topology, response, number, got.Component, got.Frequency, got.Q, frequency, q)
+
+						}
+
+					}
+
+				}
+
+			}
+
+			if (index != len(sensitivities)) {
+
+				t.Errorf("%s %s: %d sensitivities for %d components", topology, response, len(sensitivities), index)
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestLadderSensitivity(t *testing.T) {
+
+	for _, response := range []Response{ LPF, BPF } {
+
+		ladder, _ := designPassive(Specs{ Response: response, Approximation: Chebyshev, Order: pointer(uint16(5)), PassbandAttenuation: pointer(0.5), CutoffFrequency: pointer(1e6), CenterFrequency: pointer(1e6), Bandwidth: pointer(2e5) })
+		nominal := []complex128{}
+
+		for _, pole := range ladder.poles() {
+
+			if (imag(pole) >= 0.0) {
+
+				nominal = append(nominal, pole)
+
+			}
+
+		}
+
+		slices.SortFunc(nominal, func(p complex128, q complex128) int { return cmp.Compare(cmplx.Abs(p), cmplx.Abs(q)) })
+
+		for _, sensitivity := range ladder.sensitivity() {
+
+			pole := nominal[sensitivity.Stage]
+			index := int(sensitivity.Component[1] - '1')
+
+			// follow the nominal pole as one element is scaled
+			evaluate := func(scale float64) complex128 {
+
+				perturbed := Ladder{ Elements: slices.Clone(ladder.Elements), Source: ladder.Source, Load: ladder.Load }
+
+				if (sensitivity.Component[0] == 'L') {
+
+					perturbed.Elements[index].Inductance *= scale
+
+				} else {
+
+					perturbed.Elements[index].Capacitance *= scale
+
+				}
+
+				nearest := perturbed.poles()[0]
+
+				for _, candidate := range perturbed.poles() {
+
+					if (cmplx.Abs(candidate - pole) < cmplx.Abs(nearest - pole)) {
+
+						nearest = candidate
+
+					}
+
+				}
+
+				return nearest
+
+			}
+
+			h := 1e-6
+			upper, lower := evaluate(math.Exp(h)), evaluate(math.Exp(-h))
+			frequency := math.Log(cmplx.Abs(upper) / cmplx.Abs(lower)) / (2.0*h)
+			q := 0.0
+
+			if (imag(pole) > 1e-9*cmplx.Abs(pole)) {
+
+				q = frequency - math.Log(real(upper) / real(lower)) / (2.0*h)
+
+			}
+
+			if ((math.Abs(sensitivity.Frequency - frequency) > 1e-4) || (math.Abs(sensitivity.Q - q) > 1e-4)) {
+
+				t.Errorf("%s pole %d %s: got (%g, %g), finite differences give (%g, %g)", response, sensitivity.Stage, sensitivity.Component, sensitivity.Frequency, sensitivity.Q, frequency, q)
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestMonteCarloYield(t *testing.T) {
+
+	config := Specs{ Response: LPF, Approximation: Butterworth, Order: pointer(uint16(4)), PassbandAttenuation: pointer(3.2), CutoffFrequency: pointer(1000.0), StopbandAttenuation: pointer(40.0), UpperStopbandEdgeFrequency: pointer(3300.0), Topology: SallenKeyUnity }
+	filter, _ := designActive(config)
+
+	// trials draw from per-trial generators, so the worker count does not change the outcome
+	parallel := filter.monteCarlo(config, Tolerance{ Trials: 200, Seed: 7 })
+	serial := filter.monteCarlo(config, Tolerance{ Trials: 200, Seed: 7, Workers: 1 })
+
+	if ((parallel.Passed != serial.Passed) || !slices.Equal(parallel.Deviations, serial.Deviations)) {
+
+		t.Errorf("parallel workers passed %d trials, one worker passed %d", parallel.Passed, serial.Passed)
+
+	}
+
+	if ((parallel.Trials != 200) || (len(parallel.Deviations) != 200) || (parallel.Yield != float64(parallel.Passed) / 200.0)) {
+
+		t.Errorf("got %d trials, %d deviations and a yield of %g for %d passes", parallel.Trials, len(parallel.Deviations), parallel.Yield, parallel.Passed)
+
+	}
+
+	// 1% resistors and 5% capacitors only sometimes hold the passband within 3.2 dB, 0.1% parts always do
+	if ((parallel.Yield <= 0.0) || (parallel.Yield >= 1.0)) {
+
+		t.Errorf("default tolerances give a yield of %g", parallel.Yield)
+
+	}
+
+	if tight := filter.monteCarlo(config, Tolerance{ Trials: 100, Resistors: pointer(0.001), Capacitors: pointer(0.001), Distribution: Gaussian }); (tight.Yield != 1.0) {
+
+		t.Errorf("0.1%% parts give a yield of %g", tight.Yield)
+
+	}
+
+}
+
+func TestPolynomialMonteCarlo(t *testing.T) {
+
+	prototype := analogueLowPassFilterPrototype(string(Butterworth), 4, 1.0, 100.0)
+	digital := bilinear(lowPassToLowPass(prototype, 1000.0), 48000.0, 1000.0)
+	config := Specs{ CutoffFrequency: pointer(1000.0), SamplingFrequency: pointer(48000.0), PassbandAttenuation: pointer(3.1) }
+
+	if exact := digital.monteCarlo(config, Tolerance{ Trials: 50, Coefficients: pointer(0.0) }); (exact.Yield != 1.0) {
+
+		t.Errorf("unperturbed coefficients give a yield of %g", exact.Yield)
+
+	}
+
+	// direct form coefficients of a narrow low-pass are very sensitive
+	if perturbed := digital.monteCarlo(config, Tolerance{ Trials: 300, Coefficients: pointer(0.0005) }); (perturbed.Yield >= 0.9) {
+
+		t.Errorf("0.05%% coefficient errors give a yield of %g", perturbed.Yield)
+
+	}
+
+}
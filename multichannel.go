@@ -0,0 +1,174 @@
+package main
+
+import ( "context"
+		 "sync" )
+
+
+type MultiChannel struct {
+
+	Channels  []*Processor
+	Workers	  int
+	GroupSize int
+
+}
+
+func newMultiChannel(filter Polynomial, channels int, structure ...Structure) *MultiChannel {
+
+	processors := make([]*Processor, max(channels, 0))
+
+	if (len(processors) > 0) {
+
+		processors[0] = newProcessor(filter, structure...)
+
+		for index := 1; index < len(processors); index++ {
+
+			processors[index] = processors[0].clone()
+
+		}
+
+	}
+
+	return &MultiChannel{ Channels: processors, Workers: 1 }
+
+}
+
+func newMultiChannelFrom(processors []*Processor) *MultiChannel {
+
+	return &MultiChannel{ Channels: processors, Workers: 1 }
+
+}
+
+func (m *MultiChannel) Reset() {
+
+	for _, channel := range m.Channels {
+
+		channel.Reset()
+
+	}
+
+}
+
+func (m *MultiChannel) dispatch(ctx context.Context, job func(channel int)) error {
+
+	channels := len(m.Channels)
+	workers := max(m.Workers, 1)
+	size := m.GroupSize
+
+	if (size <= 0) {
+
+		size = max((channels + workers - 1) / workers, 1)
+
+	}
+
+	if (workers == 1) {
+
+		for channel := 0; channel < channels; channel++ {
+
+			if (ctx.Err() != nil) {
+
+				return ctx.Err()
+
+			}
+
+			job(channel)
+
+		}
+
+		return ctx.Err()
+
+	}
+
+	groups := make(chan int)
+	var wait sync.WaitGroup
+
+	for worker := 0; worker < workers; worker++ {
+
+		wait.Add(1)
+
+		go func() {
+
+			defer wait.Done()
+
+			for first := range groups {
+
+				for channel := first; channel < min(first + size, channels); channel++ {
+
+					if (ctx.Err() != nil) {
+
+						break
+
+					}
+
+					job(channel)
+
+				}
+
+			}
+
+		}()
+
+	}
+
+	feed:
+	for first := 0; first < channels; first += size {
+
+		select {
+
+			case groups <- first:
+
+			case <-ctx.Done():
+
+				break feed
+
+		}
+
+	}
+
+	close(groups)
+	wait.Wait()
+	return ctx.Err()
+
+}
+
+func (m *MultiChannel) ProcessInterleaved(ctx context.Context, in []float64, out []float64) error {
+
+	channels := len(m.Channels)
+
+	if (channels == 0) {
+
+		return ctx.Err()
+
+	}
+
+	frames := min(len(in), len(out)) / channels
+
+	return m.dispatch(ctx, func(channel int) {
+
+		processor := m.Channels[channel]
+
+		for frame := 0; frame < frames; frame++ {
+
+			position := frame*channels + channel
+			out[position] = processor.Process(in[position])
+
+		}
+
+	})
+
+}
+
+func (m *MultiChannel) ProcessPlanar(ctx context.Context, in [][]float64, out [][]float64) error {
+
+	channels := min(len(m.Channels), len(in), len(out))
+
+	return m.dispatch(ctx, func(channel int) {
+
+		if (channel < channels) {
+
+			m.Channels[channel].ProcessBlock(in[channel], out[channel])
+
+		}
+
+	})
+
+}
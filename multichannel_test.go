@@ -0,0 +1,122 @@
+package main
+
+import ( "context"
+		 "errors"
+		 "math"
+		 "testing" )
+
+
+func TestMultiChannelInterleaved(t *testing.T) {
+
+	const channels, frames = 37, 500
+
+	filter := digitalPolynomial(processorNumerator, equalizerDenominator)
+	in := make([]float64, channels*frames)
+
+	for index := range in {
+
+		in[index] = math.Sin(0.37*float64(index))
+
+	}
+
+	// each channel must see only its own samples
+	want := make([]float64, len(in))
+
+	for channel := 0; channel < channels; channel++ {
+
+		signal := make([]float64, frames)
+
+		for frame := range signal {
+
+			signal[frame] = in[frame*channels + channel]
+
+		}
+
+		for frame, value := range referenceFilter(processorNumerator, equalizerDenominator, signal) {
+
+			want[frame*channels + channel] = value
+
+		}
+
+	}
+
+	for _, workers := range []int{ 1, 4, 8 } {
+
+		bank := newMultiChannel(filter, channels)
+		bank.Workers = workers
+		bank.GroupSize = 3
+		got := make([]float64, len(in))
+
+		if err := bank.ProcessInterleaved(context.Background(), in, got); (err != nil) {
+
+			t.Fatal(err)
+
+		}
+
+		compareSignals(t, "interleaved", got, want, 1e-12)
+
+	}
+
+}
+
+func TestMultiChannelPlanar(t *testing.T) {
+
+	filter := digitalPolynomial(processorNumerator, equalizerDenominator)
+	bank := newMultiChannel(filter, 5, DirectFormI)
+	bank.Workers = 3
+	in := make([][]float64, 5)
+	out := make([][]float64, 5)
+
+	for channel := range in {
+
+		in[channel] = testSignal(100 + channel)
+		out[channel] = make([]float64, len(in[channel]))
+
+	}
+
+	if err := bank.ProcessPlanar(context.Background(), in, out); (err != nil) {
+
+		t.Fatal(err)
+
+	}
+
+	for channel := range in {
+
+		compareSignals(t, "planar", out[channel], referenceFilter(processorNumerator, equalizerDenominator, in[channel]), 1e-12)
+
+	}
+
+	bank.Reset()
+
+	for _, channel := range bank.Channels {
+
+		if ((channel.Structure != DirectFormI) || (channel.Process(0.0) != 0.0)) {
+
+			t.Errorf("channel is %s with leftover state after reset", channel.Structure)
+
+		}
+
+	}
+
+}
+
+func TestMultiChannelCancelled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, workers := range []int{ 1, 4 } {
+
+		bank := newMultiChannel(digitalPolynomial([]float64{ 1.0 }, []float64{ 1.0 }), 8)
+		bank.Workers = workers
+		out := make([]float64, 16)
+
+		if err := bank.ProcessInterleaved(ctx, make([]float64, 16), out); !errors.Is(err, context.Canceled) {
+
+			t.Errorf("%d workers: got %v, want %v", workers, err, context.Canceled)
+
+		}
+
+	}
+
+}
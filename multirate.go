@@ -0,0 +1,271 @@
+package main
+
+import ( "math" )
+
+
+type Resampler struct {
+
+	Interpolation int
+	Decimation	  int
+	Taps		  []float64
+	phases		  [][]float64
+	buffer		  []float64
+	position	  int
+
+}
+
+func greatestCommonDivisor(a int, b int) int {
+
+	for (b != 0) {
+
+		a, b = b, a%b
+
+	}
+
+	return a
+
+}
+
+func parseMultirate(config Specs, interpolation uint16, decimation uint16) (int, int, float64, float64, float64, float64) {
+
+	l := max(int(interpolation), 1)
+	m := max(int(decimation), 1)
+	divisor := greatestCommonDivisor(l, m)
+	l /= divisor
+	m /= divisor
+
+	samplingFrequency := 1.0
+
+	if ((config.SamplingFrequency != nil) &&
+		(*config.SamplingFrequency != 0.0)) {
+
+		samplingFrequency = math.Abs(*config.SamplingFrequency)
+
+	}
+
+	lowest := samplingFrequency*math.Min(1.0, float64(l) / float64(m))
+	passbandEdge := 0.4*lowest
+
+	if ((config.CutoffFrequency != nil) &&
+		(math.Abs(*config.CutoffFrequency) < lowest / 2.0)) {
+
+		passbandEdge = math.Abs(*config.CutoffFrequency)
+
+	}
+
+	stopbandEdge := lowest / 2.0
+
+	if ((config.TransitionWidth != nil) &&
+		(*config.TransitionWidth != 0.0)) {
+
+		stopbandEdge = passbandEdge + math.Abs(*config.TransitionWidth)
+
+	}
+
+	attenuation := 80.0
+
+	if (config.StopbandAttenuation != nil) {
+
+		attenuation = math.Abs(*config.StopbandAttenuation)
+
+	}
+
+	return l, m, samplingFrequency, passbandEdge, stopbandEdge, attenuation
+
+}
+
+func designResamplingFilter(config Specs, interpolation uint16, decimation uint16) []float64 {
+
+	l, _, samplingFrequency, passbandEdge, stopbandEdge, attenuation := parseMultirate(config, interpolation, decimation)
+	upsampled := samplingFrequency*float64(l)
+	length := kaiserLength(attenuation, stopbandEdge - passbandEdge, upsampled)
+	window := kaiserWindow(length, kaiserBeta(attenuation))
+	taps := windowedSinc((passbandEdge + stopbandEdge) / 2.0, upsampled, window)
+
+	for index := range taps {
+
+		taps[index] *= float64(l)
+
+	}
+
+	return taps
+
+}
+
+func designHalfBand(config Specs) []float64 {
+
+	samplingFrequency := 1.0
+
+	if ((config.SamplingFrequency != nil) &&
+		(*config.SamplingFrequency != 0.0)) {
+
+		samplingFrequency = math.Abs(*config.SamplingFrequency)
+
+	}
+
+	transitionWidth := 0.1*samplingFrequency
+
+	if ((config.TransitionWidth != nil) &&
+		(*config.TransitionWidth != 0.0)) {
+
+		transitionWidth = math.Min(math.Abs(*config.TransitionWidth), samplingFrequency / 2.0)
+
+	}
+
+	attenuation := 80.0
+
+	if (config.StopbandAttenuation != nil) {
+
+		attenuation = math.Abs(*config.StopbandAttenuation)
+
+	}
+
+	length := kaiserLength(attenuation, transitionWidth, samplingFrequency)
+
+	for (length%4 != 3) {
+
+		length++
+
+	}
+
+	taps := windowedSinc(samplingFrequency / 4.0, samplingFrequency, kaiserWindow(length, kaiserBeta(attenuation)))
+	centre := (length - 1) / 2
+
+	for index := range taps {
+
+		offset := index - centre
+
+		if ((offset != 0) && (offset%2 == 0)) {
+
+			taps[index] = 0.0
+
+		}
+
+	}
+
+	taps[centre] = 0.5
+	return taps
+
+}
+
+func newResampler(taps []float64, interpolation uint16, decimation uint16) *Resampler {
+
+	l := max(int(interpolation), 1)
+	m := max(int(decimation), 1)
+	divisor := greatestCommonDivisor(l, m)
+	l /= divisor
+	m /= divisor
+	length := (len(taps) + l - 1) / l
+	phases := make([][]float64, l)
+
+	for phase := range phases {
+
+		phases[phase] = make([]float64, length)
+
+		for k := 0; k < length; k++ {
+
+			if (phase + k*l < len(taps)) {
+
+				phases[phase][k] = taps[phase + k*l]
+
+			}
+
+		}
+
+	}
+
+	return &Resampler{
+
+		Interpolation: l,
+		Decimation: m,
+		Taps: taps,
+		phases: phases,
+		buffer: make([]float64, length - 1),
+
+	}
+
+}
+
+func newDecimator(taps []float64, decimation uint16) *Resampler {
+
+	return newResampler(taps, 1, decimation)
+
+}
+
+func newInterpolator(taps []float64, interpolation uint16) *Resampler {
+
+	return newResampler(taps, interpolation, 1)
+
+}
+
+func (r *Resampler) Reset() {
+
+	clear(r.buffer)
+	r.position = 0
+
+}
+
+func (r *Resampler) ProcessBlock(input []float64) []float64 {
+
+	history := len(r.phases[0]) - 1
+	r.buffer = append(r.buffer[:history], input...)
+	output := make([]float64, 0, (len(input)*r.Interpolation) / r.Decimation + 1)
+
+	for ((r.position / r.Interpolation) < len(input)) {
+
+		current := r.position / r.Interpolation + history
+		phase := r.phases[r.position%r.Interpolation]
+		y := 0.0
+
+		for k, coefficient := range phase {
+
+			y += coefficient*r.buffer[current - k]
+
+		}
+
+		output = append(output, y)
+		r.position += r.Decimation
+
+	}
+
+	r.position -= len(input)*r.Interpolation
+	copy(r.buffer, r.buffer[len(r.buffer) - history:])
+	r.buffer = r.buffer[:history]
+	return output
+
+}
+
+func aliasingRejection(taps []float64, interpolation uint16, decimation uint16, samplingFrequency float64, passbandEdge ...float64) float64 {
+
+	l := max(int(interpolation), 1)
+	m := max(int(decimation), 1)
+	upsampled := samplingFrequency*float64(l)
+	lowest := samplingFrequency*math.Min(1.0, float64(l) / float64(m))
+	edge := 0.4*lowest
+
+	if (len(passbandEdge) > 0) {
+
+		edge = passbandEdge[0]
+
+	}
+
+	filter := digitalPolynomial(taps, []float64{ 1.0 })
+	passband := math.Inf(1)
+
+	for _, frequency := range linearSpace(0.0, edge, 64) {
+
+		passband = math.Min(passband, magnitudeResponse(filter, frequency, upsampled))
+
+	}
+
+	stopband := 0.0
+
+	for _, frequency := range linearSpace(lowest - edge, upsampled / 2.0, 1024) {
+
+		stopband = math.Max(stopband, magnitudeResponse(filter, frequency, upsampled))
+
+	}
+
+	return decibels(passband) - decibels(stopband)
+
+}
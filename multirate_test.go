@@ -0,0 +1,137 @@
+package main
+
+import ( "math"
+		 "testing" )
+
+
+// direct evaluation of y[k] = Σ h[kM - nL] x[n], the zero-stuffed, filtered and decimated signal
+func referenceResample(taps []float64, input []float64, l int, m int, count int) []float64 {
+
+	output := make([]float64, count)
+
+	for k := range output {
+
+		for n := range input {
+
+			if j := k*m - n*l; ((j >= 0) && (j < len(taps))) {
+
+				output[k] += taps[j]*input[n]
+
+			}
+
+		}
+
+	}
+
+	return output
+
+}
+
+func TestResamplerMatchesDirectForm(t *testing.T) {
+
+	config := Specs{ SamplingFrequency: pointer(48000.0), StopbandAttenuation: pointer(80.0) }
+	input := make([]float64, 2000)
+
+	for index := range input {
+
+		input[index] = math.Sin(2.0*math.Pi*1000.0*float64(index) / 48000.0) + 0.25*math.Cos(0.37*float64(index))
+
+	}
+
+	for _, ratio := range [][2]uint16{ { 3, 2 }, { 2, 3 }, { 4, 1 }, { 1, 3 }, { 6, 4 } } {
+
+		taps := designResamplingFilter(config, ratio[0], ratio[1])
+		resampler := newResampler(taps, ratio[0], ratio[1])
+		output := []float64{}
+
+		// uneven blocks exercise the history carried between calls
+		for start := 0; start < len(input); start += 333 {
+
+			output = append(output, resampler.ProcessBlock(input[start:min(start + 333, len(input))])...)
+
+		}
+
+		l, m := resampler.Interpolation, resampler.Decimation
+		want := (len(input)*l + m - 1) / m
+
+		if (len(output) != want) {
+
+			t.Errorf("%d/%d: got %d samples, want %d", ratio[0], ratio[1], len(output), want)
+
+		}
+
+		compareSignals(t, "polyphase", output, referenceResample(taps, input, l, m, len(output)), 1e-12)
+
+	}
+
+}
+
+func TestResamplerReset(t *testing.T) {
+
+	taps := designResamplingFilter(Specs{}, 2, 1)
+	interpolator := newInterpolator(taps, 2)
+	first := interpolator.ProcessBlock([]float64{ 1.0, 0.5, -0.25, 0.0 })
+	interpolator.Reset()
+	compareSignals(t, "after reset", interpolator.ProcessBlock([]float64{ 1.0, 0.5, -0.25, 0.0 }), first, 0.0)
+
+}
+
+func TestAliasingRejection(t *testing.T) {
+
+	taps := designResamplingFilter(Specs{ SamplingFrequency: pointer(48000.0), StopbandAttenuation: pointer(80.0) }, 3, 2)
+
+	if rejection := aliasingRejection(taps, 3, 2, 48000.0); (rejection < 79.0) {
+
+		t.Errorf("aliasing rejection = %g dB, want at least 80 dB", rejection)
+
+	}
+
+}
+
+func TestHalfBand(t *testing.T) {
+
+	taps := designHalfBand(Specs{ TransitionWidth: pointer(0.1), StopbandAttenuation: pointer(70.0) })
+	centre := (len(taps) - 1) / 2
+
+	if (len(taps)%4 != 3) {
+
+		t.Fatalf("got %d taps, want a length of 4k + 3", len(taps))
+
+	}
+
+	if (taps[centre] != 0.5) {
+
+		t.Errorf("centre tap = %g, want 0.5", taps[centre])
+
+	}
+
+	for index := range taps {
+
+		if ((index != centre) && ((index - centre)%2 == 0) && (taps[index] != 0.0)) {
+
+			t.Errorf("tap %d = %g, want every other tap zero", index, taps[index])
+
+		}
+
+		if (taps[index] != taps[len(taps) - index - 1]) {
+
+			t.Errorf("taps are not symmetric at %d", index)
+
+		}
+
+	}
+
+	// the kaiser length formula is an estimate, so allow a couple of dB
+	if rejection := aliasingRejection(taps, 1, 2, 1.0, 0.2); (rejection < 67.0) {
+
+		t.Errorf("aliasing rejection = %g dB, want close to 70 dB", rejection)
+
+	}
+
+	if got := len(newDecimator(taps, 2).ProcessBlock(make([]float64, 101))); (got != 51) {
+
+		t.Errorf("decimating 101 samples gave %d, want 51", got)
+
+	}
+
+}
@@ -0,0 +1,229 @@
+package main
+
+import ( "errors"
+		 "fmt"
+		 "math" )
+
+
+func parseResonator(config Specs) (string, string, float64, float64, float64, float64, uint16, error) {
+
+	domain := string(Analogue)
+
+	if config.Domain.exists() {
+
+		domain = string(config.Domain)
+
+	}
+
+	response := string(Notch)
+
+	if (config.Response == Peak) {
+
+		response = string(Peak)
+
+	}
+
+	lowerEdgeFrequency := config.LowerStopbandEdgeFrequency
+	upperEdgeFrequency := config.UpperStopbandEdgeFrequency
+
+	if (response == string(Peak)) {
+
+		lowerEdgeFrequency = config.LowerPassbandEdgeFrequency
+		upperEdgeFrequency = config.UpperPassbandEdgeFrequency
+
+	}
+
+	centerFrequency := 0.0
+
+	if (config.CenterFrequency != nil) {
+
+		centerFrequency = math.Abs(*config.CenterFrequency)
+
+	} else if ((lowerEdgeFrequency != nil) &&
+			   (upperEdgeFrequency != nil)) {
+
+		centerFrequency = math.Sqrt(math.Abs(*lowerEdgeFrequency*(*upperEdgeFrequency)))
+
+	}
+
+	bandwidth := 0.0
+
+	if (config.Bandwidth != nil) {
+
+		bandwidth = math.Abs(*config.Bandwidth)
+
+	} else if ((lowerEdgeFrequency != nil) &&
+			   (upperEdgeFrequency != nil)) {
+
+		bandwidth = math.Abs(*upperEdgeFrequency - *lowerEdgeFrequency)
+
+	}
+
+	quality := math.Sqrt(0.5)
+
+	if ((config.QualityFactor != nil) &&
+		(*config.QualityFactor != 0.0)) {
+
+		quality = math.Abs(*config.QualityFactor)
+
+	} else if (bandwidth > 0.0) {
+
+		quality = centerFrequency / bandwidth
+
+	}
+
+	gain := 0.0
+
+	if (response == string(Notch)) {
+
+		if (config.StopbandAttenuation != nil) {
+
+			gain = math.Pow(10, -math.Abs(*config.StopbandAttenuation) / 20.0)
+
+		}
+
+	} else if (config.Gain != nil) {
+
+		gain = math.Pow(10, *config.Gain / 20.0)
+
+	} else {
+
+		return domain, response, centerFrequency, quality, gain, 0.0, 0, errors.New("peak resonator requires a gain")
+
+	}
+
+	samplingFrequency := 0.0
+
+	if (config.SamplingFrequency != nil) {
+
+		samplingFrequency = math.Abs(*config.SamplingFrequency)
+
+	}
+
+	harmonics := uint16(1)
+
+	if ((config.Harmonics != nil) &&
+		(*config.Harmonics > 0)) {
+
+		harmonics = *config.Harmonics
+
+	}
+
+	var err error
+
+	if (centerFrequency == 0.0) {
+
+		err = errors.New("resonator requires a center frequency or both band edges")
+
+	} else if ((domain == string(Digital)) && (samplingFrequency == 0.0)) {
+
+		err = errors.New("digital resonator requires a sampling frequency")
+
+	} else if ((domain == string(Digital)) && (centerFrequency >= samplingFrequency / 2.0)) {
+
+		err = fmt.Errorf("resonator frequency %.6g Hz is not below the Nyquist frequency of %.6g Hz", centerFrequency, samplingFrequency / 2.0)
+
+	}
+
+	return domain, response, centerFrequency, quality, gain, samplingFrequency, harmonics, err
+
+}
+
+func notchSection(centerFrequency float64, quality float64, gain float64, samplingFrequency float64) Section {
+
+	omega := 2.0*math.Pi*centerFrequency / samplingFrequency
+	bandwidth := omega / quality
+	t := math.Tan(bandwidth / 2.0)
+	k := (1.0 - t) / (1.0 + t)
+	c := -math.Cos(omega)*(1.0 + k)
+
+	return Section{
+
+		Numerator: [3]float64{ ((1.0 + gain) + (1.0 - gain)*k) / 2.0, c, ((1.0 + gain)*k + (1.0 - gain)) / 2.0 },
+		Denominator: [3]float64{ 1.0, c, k },
+
+	}
+
+}
+
+func notchPolynomial(centerFrequency float64, quality float64, gain float64) Polynomial {
+
+	omega := 2.0*math.Pi*centerFrequency
+	numerator := []float64{ 1.0, gain*omega / quality, math.Pow(omega, 2) }
+	denominator := []float64{ 1.0, omega / quality, math.Pow(omega, 2) }
+	return analoguePolynomial(numerator, denominator)
+
+}
+
+func resonatorSections(config Specs) ([]Section, error) {
+
+	domain, _, centerFrequency, quality, gain, samplingFrequency, harmonics, err := parseResonator(config)
+	sections := []Section{}
+
+	if (err != nil) {
+
+		return sections, err
+
+	}
+
+	if (domain != string(Digital)) {
+
+		return sections, errors.New("resonator sections require a digital design")
+
+	}
+
+	for harmonic := uint16(1); harmonic <= harmonics; harmonic++ {
+
+		frequency := float64(harmonic)*centerFrequency
+
+		if (frequency >= samplingFrequency / 2.0) {
+
+			break
+
+		}
+
+		sections = append(sections, notchSection(frequency, float64(harmonic)*quality, gain, samplingFrequency))
+
+	}
+
+	return sections, nil
+
+}
+
+func designResonator(config Specs) (Polynomial, error) {
+
+	domain, _, centerFrequency, quality, gain, _, harmonics, err := parseResonator(config)
+	tf := analoguePolynomial([]float64{ 1.0 }, []float64{ 1.0 })
+
+	if (err != nil) {
+
+		return tf, err
+
+	}
+
+	if (domain == string(Digital)) {
+
+		sections, err := resonatorSections(config)
+
+		if (err != nil) {
+
+			return tf, err
+
+		}
+
+		return cascadePolynomial(sections), nil
+
+	}
+
+	tf = notchPolynomial(centerFrequency, quality, gain)
+
+	for harmonic := uint16(2); harmonic <= harmonics; harmonic++ {
+
+		frequency := float64(harmonic)*centerFrequency
+		tf.multiply(notchPolynomial(frequency, float64(harmonic)*quality, gain))
+
+	}
+
+	return tf, nil
+
+}
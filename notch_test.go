@@ -0,0 +1,140 @@
+package main
+
+import ( "math"
+		 "testing" )
+
+
+func pointer[T any](value T) *T {
+
+	return &value
+
+}
+
+func TestDigitalNotch(t *testing.T) {
+
+	config := Specs{
+
+		Domain: Digital,
+		Response: Notch,
+		CenterFrequency: pointer(50.0),
+		Bandwidth: pointer(4.0),
+		SamplingFrequency: pointer(1000.0),
+		Harmonics: pointer(uint16(3)),
+
+	}
+
+	filter, err := designFilter(config)
+
+	if (err != nil) {
+
+		t.Fatal(err)
+
+	}
+
+	for _, frequency := range []float64{ 50.0, 100.0, 150.0 } {
+
+		if magnitude := magnitudeResponse(filter, frequency, 1000.0); (magnitude > 1e-9) {
+
+			t.Errorf("|H| at the %g Hz harmonic = %g, want 0", frequency, magnitude)
+
+		}
+
+	}
+
+	for _, frequency := range []float64{ 0.0, 300.0, 500.0 } {
+
+		if magnitude := magnitudeResponse(filter, frequency, 1000.0); (math.Abs(magnitude - 1.0) > 1e-2) {
+
+			t.Errorf("|H| at %g Hz = %g, want 1", frequency, magnitude)
+
+		}
+
+	}
+
+	sections, err := resonatorSections(config)
+
+	if ((err != nil) || (len(sections) != 3)) {
+
+		t.Fatalf("got %d sections and %v, want 3 sections", len(sections), err)
+
+	}
+
+}
+
+func TestNotchDepth(t *testing.T) {
+
+	filter, err := designResonator(Specs{
+
+		Domain: Digital,
+		Response: Notch,
+		CenterFrequency: pointer(1000.0),
+		Bandwidth: pointer(100.0),
+		StopbandAttenuation: pointer(20.0),
+		SamplingFrequency: pointer(8000.0),
+
+	})
+
+	if (err != nil) {
+
+		t.Fatal(err)
+
+	}
+
+	if got := decibels(magnitudeResponse(filter, 1000.0, 8000.0)); (math.Abs(got + 20.0) > 1e-9) {
+
+		t.Errorf("depth = %g dB, want -20 dB", got)
+
+	}
+
+}
+
+func TestAnaloguePeak(t *testing.T) {
+
+	filter, err := designFilter(Specs{ Response: Peak, CenterFrequency: pointer(1000.0), QualityFactor: pointer(2.0), Gain: pointer(6.0) })
+
+	if (err != nil) {
+
+		t.Fatal(err)
+
+	}
+
+	for frequency, want := range map[float64]float64{ 0.0: 0.0, 1000.0: 6.0, 1e7: 0.0 } {
+
+		if got := decibels(magnitudeResponse(filter, frequency)); (math.Abs(got - want) > 1e-6) {
+
+			t.Errorf("gain at %g Hz = %g dB, want %g dB", frequency, got, want)
+
+		}
+
+	}
+
+}
+
+func TestResonatorErrors(t *testing.T) {
+
+	cases := map[string]Specs{
+
+		"peak resonator requires a gain": { Response: Peak, CenterFrequency: pointer(1000.0) },
+		"resonator requires a center frequency or both band edges": { Response: Notch },
+		"digital resonator requires a sampling frequency": { Response: Notch, Domain: Digital, CenterFrequency: pointer(1000.0) },
+		"resonator frequency 30000 Hz is not below the Nyquist frequency of 24000 Hz": { Response: Notch, Domain: Digital, CenterFrequency: pointer(30000.0), SamplingFrequency: pointer(48000.0) },
+
+	}
+
+	for message, config := range cases {
+
+		if _, err := designFilter(config); ((err == nil) || (err.Error() != message)) {
+
+			t.Errorf("got %v, want %q", err, message)
+
+		}
+
+	}
+
+	if _, err := resonatorSections(Specs{ Response: Notch, CenterFrequency: pointer(50.0) }); (err == nil) {
+
+		t.Error("analogue resonator sections did not fail")
+
+	}
+
+}
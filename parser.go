@@ -0,0 +1,817 @@
+package main
+
+import ( "errors"
+		 "fmt"
+		 "strconv"
+		 "strings"
+		 "unicode" )
+
+
+type ParseError struct {
+
+	Input	 string
+	Position int
+	Message	 string
+
+}
+
+type expressionParser struct {
+
+	input	 string
+	runes	 []rune
+	position int
+	variable string
+
+}
+
+type rational struct {
+
+	numerator	map[int64]float64
+	denominator map[int64]float64
+
+}
+
+var superscriptDigits = map[rune]rune{ '⁰': '0', '¹': '1', '²': '2', '³': '3', '⁴': '4', '⁵': '5', '⁶': '6', '⁷': '7', '⁸': '8', '⁹': '9', '⁻': '-', '⁺': '+' }
+
+const maximumExponent = 1000
+
+const maximumDegree = 1000
+
+func (e *ParseError) Error() string {
+
+	line := strings.ReplaceAll(e.Input, "\n", " ")
+	caret := strings.Repeat(" ", max(e.Position - 1, 0)) + "^"
+	return fmt.Sprintf("column %d: %s\n  %s\n  %s", e.Position, e.Message, line, caret)
+
+}
+
+func laurentProduct(p map[int64]float64, q map[int64]float64) map[int64]float64 {
+
+	product := map[int64]float64{}
+
+	for left, a := range p {
+
+		for right, b := range q {
+
+			product[left + right] += a*b
+
+		}
+
+	}
+
+	return product
+
+}
+
+func laurentSum(p map[int64]float64, q map[int64]float64, sign float64) map[int64]float64 {
+
+	sum := map[int64]float64{}
+
+	for exponent, coefficient := range p {
+
+		sum[exponent] += coefficient
+
+	}
+
+	for exponent, coefficient := range q {
+
+		sum[exponent] += sign*coefficient
+
+	}
+
+	return sum
+
+}
+
+func laurentShift(p map[int64]float64, offset int64) map[int64]float64 {
+
+	shifted := map[int64]float64{}
+
+	for exponent, coefficient := range p {
+
+		shifted[exponent + offset] = coefficient
+
+	}
+
+	return shifted
+
+}
+
+func laurentSpan(p map[int64]float64) (int64, int64) {
+
+	lowest, highest, first := int64(0), int64(0), true
+
+	for exponent, coefficient := range p {
+
+		if (coefficient == 0.0) {
+
+			continue
+
+		}
+
+		if (first || (exponent < lowest)) {
+
+			lowest = exponent
+
+		}
+
+		if (first || (exponent > highest)) {
+
+			highest = exponent
+
+		}
+
+		first = false
+
+	}
+
+	return lowest, highest
+
+}
+
+func laurentZero(p map[int64]float64) bool {
+
+	for _, coefficient := range p {
+
+		if (coefficient != 0.0) {
+
+			return false
+
+		}
+
+	}
+
+	return true
+
+}
+
+func laurentEqual(p map[int64]float64, q map[int64]float64) bool {
+
+	return laurentZero(laurentSum(p, q, -1.0))
+
+}
+
+func constantRational(value float64) rational {
+
+	return rational{ numerator: map[int64]float64{ 0: value }, denominator: map[int64]float64{ 0: 1.0 } }
+
+}
+
+func (r rational) add(q rational, sign float64) rational {
+
+	if laurentEqual(r.denominator, q.denominator) {
+
+		return rational{ numerator: laurentSum(r.numerator, q.numerator, sign), denominator: r.denominator }
+
+	}
+
+	return rational{
+
+		numerator: laurentSum(laurentProduct(r.numerator, q.denominator), laurentProduct(q.numerator, r.denominator), sign),
+		denominator: laurentProduct(r.denominator, q.denominator),
+
+	}
+
+}
+
+func (r rational) normalize() rational {
+
+	exponent, coefficient, count := int64(0), 0.0, 0
+
+	for power, value := range r.denominator {
+
+		if (value != 0.0) {
+
+			exponent, coefficient = power, value
+			count++
+
+		}
+
+	}
+
+	if (count != 1) {
+
+		return r
+
+	}
+
+	numerator := map[int64]float64{}
+
+	for power, value := range r.numerator {
+
+		numerator[power - exponent] = value / coefficient
+
+	}
+
+	return rational{ numerator: numerator, denominator: map[int64]float64{ 0: 1.0 } }
+
+}
+
+func (r rational) degree() int64 {
+
+	degree := int64(0)
+
+	for _, side := range []map[int64]float64{ r.numerator, r.denominator } {
+
+		lowest, highest := laurentSpan(side)
+		degree = max(degree, highest - lowest)
+
+	}
+
+	return degree
+
+}
+
+func (r rational) multiply(q rational) rational {
+
+	return rational{ numerator: laurentProduct(r.numerator, q.numerator), denominator: laurentProduct(r.denominator, q.denominator) }
+
+}
+
+func (r rational) divide(q rational) rational {
+
+	return rational{ numerator: laurentProduct(r.numerator, q.denominator), denominator: laurentProduct(r.denominator, q.numerator) }.normalize()
+
+}
+
+func (p *expressionParser) fail(position int, format string, arguments ...any) *ParseError {
+
+	return &ParseError{ Input: p.input, Position: position + 1, Message: fmt.Sprintf(format, arguments...) }
+
+}
+
+func (p *expressionParser) bound(position int, value rational) error {
+
+	if degree := value.degree(); (degree > maximumDegree) {
+
+		return p.fail(position, "expression degree %d exceeds the limit of %d", degree, maximumDegree)
+
+	}
+
+	return nil
+
+}
+
+func (p *expressionParser) skip() {
+
+	for ((p.position < len(p.runes)) && unicode.IsSpace(p.runes[p.position])) {
+
+		p.position++
+
+	}
+
+}
+
+func (p *expressionParser) peek() rune {
+
+	p.skip()
+
+	if (p.position < len(p.runes)) {
+
+		return p.runes[p.position]
+
+	}
+
+	return 0
+
+}
+
+func (p *expressionParser) describe() string {
+
+	if (p.position >= len(p.runes)) {
+
+		return "end of input"
+
+	}
+
+	return strconv.QuoteRune(p.runes[p.position])
+
+}
+
+func (p *expressionParser) sum() (rational, error) {
+
+	value, err := p.product()
+
+	if (err != nil) {
+
+		return value, err
+
+	}
+
+	for {
+
+		switch p.peek() {
+
+			case '+', '-', '−':
+
+				start := p.position
+				sign := 1.0
+
+				if (p.runes[p.position] != '+') {
+
+					sign = -1.0
+
+				}
+
+				p.position++
+				term, err := p.product()
+
+				if (err != nil) {
+
+					return term, err
+
+				}
+
+				value = value.add(term, sign)
+
+				if err := p.bound(start, value); (err != nil) {
+
+					return value, err
+
+				}
+
+			default:
+
+				return value, nil
+
+		}
+
+	}
+
+}
+
+func (p *expressionParser) product() (rational, error) {
+
+	value, err := p.factor()
+
+	if (err != nil) {
+
+		return value, err
+
+	}
+
+	for {
+
+		next := p.peek()
+		start := p.position
+
+		switch {
+
+			case ((next == '*') || (next == '·') || (next == '×')):
+
+				p.position++
+				operand, err := p.factor()
+
+				if (err != nil) {
+
+					return operand, err
+
+				}
+
+				value = value.multiply(operand)
+
+				if err := p.bound(start, value); (err != nil) {
+
+					return value, err
+
+				}
+
+			case (next == '/'):
+
+				p.position++
+				operand, err := p.factor()
+
+				if (err != nil) {
+
+					return operand, err
+
+				}
+
+				if laurentZero(operand.numerator) {
+
+					return operand, p.fail(start, "division by zero")
+
+				}
+
+				value = value.divide(operand)
+
+				if err := p.bound(start, value); (err != nil) {
+
+					return value, err
+
+				}
+
+			case ((next == '(') || (next == '.') || unicode.IsDigit(next) || unicode.IsLetter(next)):
+
+				operand, err := p.factor()
+
+				if (err != nil) {
+
+					return operand, err
+
+				}
+
+				value = value.multiply(operand)
+
+				if err := p.bound(start, value); (err != nil) {
+
+					return value, err
+
+				}
+
+			default:
+
+				return value, nil
+
+		}
+
+	}
+
+}
+
+func (p *expressionParser) factor() (rational, error) {
+
+	switch p.peek() {
+
+		case '+':
+
+			p.position++
+			return p.factor()
+
+		case '-', '−':
+
+			p.position++
+			value, err := p.factor()
+			value.numerator = laurentProduct(value.numerator, map[int64]float64{ 0: -1.0 })
+			return value, err
+
+	}
+
+	start := p.position
+	value, err := p.primary()
+
+	if (err != nil) {
+
+		return value, err
+
+	}
+
+	exponent, present, err := p.exponent()
+
+	if ((err != nil) || !present) {
+
+		return value, err
+
+	}
+
+	if ((exponent < 0) && laurentZero(value.numerator)) {
+
+		return value, p.fail(start, "zero raised to a negative power")
+
+	}
+
+	power := constantRational(1.0)
+
+	for index := int64(0); index < max(exponent, -exponent); index++ {
+
+		power = power.multiply(value)
+
+		if err := p.bound(start, power); (err != nil) {
+
+			return power, err
+
+		}
+
+	}
+
+	if (exponent < 0) {
+
+		return rational{ numerator: power.denominator, denominator: power.numerator }.normalize(), nil
+
+	}
+
+	return power, nil
+
+}
+
+func (p *expressionParser) exponent() (int64, bool, error) {
+
+	if (p.position < len(p.runes)) {
+
+		if _, exists := superscriptDigits[p.runes[p.position]]; exists {
+
+			start := p.position
+			digits := ""
+
+			for (p.position < len(p.runes)) {
+
+				digit, exists := superscriptDigits[p.runes[p.position]]
+
+				if !exists {
+
+					break
+
+				}
+
+				digits += string(digit)
+				p.position++
+
+			}
+
+			exponent, err := strconv.ParseInt(digits, 10, 64)
+
+			if ((err != nil) && !errors.Is(err, strconv.ErrRange)) {
+
+				return 0, true, p.fail(start, "invalid superscript exponent %q", digits)
+
+			}
+
+			if ((err != nil) || (max(exponent, -exponent) > maximumExponent)) {
+
+				return 0, true, p.fail(start, "exponent %s exceeds the limit of %d", digits, maximumExponent)
+
+			}
+
+			return exponent, true, nil
+
+		}
+
+	}
+
+	if ((p.peek() != '^') && !strings.HasPrefix(string(p.runes[p.position:]), "**")) {
+
+		return 0, false, nil
+
+	}
+
+	if (p.runes[p.position] == '^') {
+
+		p.position++
+
+	} else {
+
+		p.position += 2
+
+	}
+
+	grouped := false
+	open := p.position
+
+	if (p.peek() == '(') {
+
+		grouped = true
+		open = p.position
+		p.position++
+
+	}
+
+	p.skip()
+	digits := ""
+
+	if ((p.peek() == '-') || (p.peek() == '−') || (p.peek() == '+')) {
+
+		digits = strings.Replace(string(p.runes[p.position]), "−", "-", 1)
+		p.position++
+
+	}
+
+	p.skip()
+	start := p.position
+
+	for ((p.position < len(p.runes)) && (unicode.IsDigit(p.runes[p.position]) || (p.runes[p.position] == '.'))) {
+
+		digits += string(p.runes[p.position])
+		p.position++
+
+	}
+
+	if (p.position == start) {
+
+		return 0, true, p.fail(p.position, "expected an integer exponent, found %s", p.describe())
+
+	}
+
+	exponent, err := strconv.ParseInt(digits, 10, 64)
+
+	if ((err != nil) && !errors.Is(err, strconv.ErrRange)) {
+
+		return 0, true, p.fail(start, "exponent %q must be an integer", digits)
+
+	}
+
+	if ((err != nil) || (max(exponent, -exponent) > maximumExponent)) {
+
+		return 0, true, p.fail(start, "exponent %s exceeds the limit of %d", digits, maximumExponent)
+
+	}
+
+	if grouped {
+
+		if (p.peek() != ')') {
+
+			return 0, true, p.fail(p.position, "expected ')' to close '(' at column %d, found %s", open + 1, p.describe())
+
+		}
+
+		p.position++
+
+	}
+
+	return exponent, true, nil
+
+}
+
+func (p *expressionParser) primary() (rational, error) {
+
+	next := p.peek()
+	start := p.position
+
+	switch {
+
+		case (next == '('):
+
+			p.position++
+			value, err := p.sum()
+
+			if (err != nil) {
+
+				return value, err
+
+			}
+
+			if (p.peek() != ')') {
+
+				return value, p.fail(p.position, "expected ')' to close '(' at column %d, found %s", start + 1, p.describe())
+
+			}
+
+			p.position++
+			return value, nil
+
+		case ((next == '.') || unicode.IsDigit(next)):
+
+			for ((p.position < len(p.runes)) && (unicode.IsDigit(p.runes[p.position]) || (p.runes[p.position] == '.'))) {
+
+				p.position++
+
+			}
+
+			if ((p.position + 1 < len(p.runes)) && ((p.runes[p.position] == 'e') || (p.runes[p.position] == 'E'))) {
+
+				mark := p.position + 1
+
+				if ((p.runes[mark] == '+') || (p.runes[mark] == '-')) {
+
+					mark++
+
+				}
+
+				if ((mark < len(p.runes)) && unicode.IsDigit(p.runes[mark])) {
+
+					p.position = mark
+
+					for ((p.position < len(p.runes)) && unicode.IsDigit(p.runes[p.position])) {
+
+						p.position++
+
+					}
+
+				}
+
+			}
+
+			number, err := strconv.ParseFloat(string(p.runes[start:p.position]), 64)
+
+			if (err != nil) {
+
+				return rational{}, p.fail(start, "invalid number %q", string(p.runes[start:p.position]))
+
+			}
+
+			return constantRational(number), nil
+
+		case unicode.IsLetter(next):
+
+			for ((p.position < len(p.runes)) && unicode.IsLetter(p.runes[p.position])) {
+
+				p.position++
+
+			}
+
+			name := string(p.runes[start:p.position])
+
+			if (p.variable == "") {
+
+				p.variable = name
+
+			} else if (name != p.variable) {
+
+				return rational{}, p.fail(start, "unexpected variable %q, expression is in %q", name, p.variable)
+
+			}
+
+			return rational{ numerator: map[int64]float64{ 1: 1.0 }, denominator: map[int64]float64{ 0: 1.0 } }, nil
+
+	}
+
+	return rational{}, p.fail(p.position, "expected a number, variable or '(', found %s", p.describe())
+
+}
+
+func parseTransferFunction(text string) (Polynomial, error) {
+
+	parser := &expressionParser{ input: text, runes: []rune(text) }
+
+	if equals := strings.IndexRune(text, '='); (equals >= 0) {
+
+		label := strings.TrimSpace(text[:equals])
+		opening := strings.Index(label, "(")
+		closing := strings.LastIndex(label, ")")
+
+		if ((opening <= 0) || (closing != len(label) - 1) || (closing <= opening + 1)) {
+
+			return Polynomial{}, parser.fail(0, "expected a label such as H(s) before '='")
+
+		}
+
+		parser.variable = strings.TrimSpace(label[opening + 1:closing])
+		parser.position = len([]rune(text[:equals])) + 1
+
+	}
+
+	value, err := parser.sum()
+
+	if (err != nil) {
+
+		return Polynomial{}, err
+
+	}
+
+	if (parser.peek() != 0) {
+
+		if (parser.runes[parser.position] == ')') {
+
+			return Polynomial{}, parser.fail(parser.position, "unmatched ')'")
+
+		}
+
+		return Polynomial{}, parser.fail(parser.position, "unexpected %s", parser.describe())
+
+	}
+
+	if laurentZero(value.denominator) {
+
+		return Polynomial{}, parser.fail(0, "denominator is zero")
+
+	}
+
+	variable := parser.variable
+
+	if (variable == "") {
+
+		variable = "s"
+
+	}
+
+	for _, coefficients := range []map[int64]float64{ value.numerator, value.denominator } {
+
+		for exponent, coefficient := range coefficients {
+
+			if (coefficient == 0.0) {
+
+				delete(coefficients, exponent)
+
+			}
+
+		}
+
+	}
+
+	// clear negative powers of s and positive powers of z so both sides are ordinary polynomials
+	lowest, highest := laurentSpan(value.numerator)
+	lower, upper := laurentSpan(value.denominator)
+	lowest, highest = min(lowest, lower), max(highest, upper)
+	offset := int64(0)
+
+	if ((variable == "z") && (highest > 0)) {
+
+		offset = -highest
+
+	} else if ((variable != "z") && (lowest < 0)) {
+
+		offset = -lowest
+
+	}
+
+	value.numerator = laurentShift(value.numerator, offset)
+	value.denominator = laurentShift(value.denominator, offset)
+
+	return constructPolynomial(map[string]interface{}{
+
+		"variable": variable,
+		"numerator": value.numerator,
+		"denominator": value.denominator,
+
+	}), nil
+
+}
@@ -0,0 +1,191 @@
+package main
+
+import ( "errors"
+		 "math"
+		 "math/cmplx"
+		 "slices"
+		 "testing" )
+
+
+func TestParseTransferFunction(t *testing.T) {
+
+	tests := []struct {
+
+		text		string
+		variable	string
+		numerator	[]float64
+		denominator []float64
+
+	}{
+
+		{ "(s^2 + 2.1s + 1)/(s^3 + 0.5s - 4)", "s", []float64{ 1.0, 2.1, 1.0 }, []float64{ 1.0, 0.0, 0.5, -4.0 } },
+		{ "(s+1)(s^2+s+1)", "s", []float64{ 1.0, 2.0, 2.0, 1.0 }, []float64{ 1.0 } },
+		{ "H(s) = 2/(s^2 + 1.414s + 1)", "s", []float64{ 2.0 }, []float64{ 1.0, 1.414, 1.0 } },
+		{ "1/(s+1) + 1/(s+2)", "s", []float64{ 2.0, 3.0 }, []float64{ 1.0, 3.0, 2.0 } },
+		{ "-s^2/(s+1)^2", "s", []float64{ -1.0, 0.0, 0.0 }, []float64{ 1.0, 2.0, 1.0 } },
+		{ "1e-3s/(2.2E-9 s**2 + s + 1)", "s", []float64{ 0.001, 0.0 }, []float64{ 2.2e-9, 1.0, 1.0 } },
+		{ "s · (s − 1)", "s", []float64{ 1.0, -1.0, 0.0 }, []float64{ 1.0 } },
+		{ "5", "s", []float64{ 5.0 }, []float64{ 1.0 } },
+		{ "H(p) = p/(p+1)", "p", []float64{ 1.0, 0.0 }, []float64{ 1.0, 1.0 } },
+		{ "1/s^2 + 1", "s", []float64{ 1.0, 0.0, 1.0 }, []float64{ 1.0, 0.0, 0.0 } },
+
+	}
+
+	for _, test := range tests {
+
+		filter, err := parseTransferFunction(test.text)
+
+		if (err != nil) {
+
+			t.Errorf("%q: %v", test.text, err)
+			continue
+
+		}
+
+		numerator, denominator := filter.analogueCoefficients()
+
+		if ((filter.Denominator.Terms[0].Variable != test.variable) || !slices.Equal(numerator, test.numerator) || !slices.Equal(denominator, test.denominator)) {
+
+			t.Errorf("%q = %v / %v in %s, want %v / %v in %s", test.text, numerator, denominator, filter.Denominator.Terms[0].Variable, test.numerator, test.denominator, test.variable)
+
+		}
+
+	}
+
+}
+
+func TestParseDelays(t *testing.T) {
+
+	// positive powers of z are cleared, leaving coefficients of ascending powers of z⁻¹
+	tests := []struct {
+
+		text		string
+		numerator	[]float64
+		denominator []float64
+
+	}{
+
+		{ "(1 + 2z⁻¹ + z⁻²)/(1 - 1.56z⁻¹ + 0.64z⁻²)", []float64{ 1.0, 2.0, 1.0 }, []float64{ 1.0, -1.56, 0.64 } },
+		{ "0.02(1 + z^-1)^2 / (1 - 1.56*z^(-1) + 0.64 z^-2)", []float64{ 0.02, 0.04, 0.02 }, []float64{ 1.0, -1.56, 0.64 } },
+		{ "H(z) = (1 + z^-1)/(1 - 0.5z^-1)", []float64{ 1.0, 1.0 }, []float64{ 1.0, -0.5 } },
+		{ "z^-2/(1 - 0.5z^-1)", []float64{ 0.0, 0.0, 1.0 }, []float64{ 1.0, -0.5 } },
+		{ "(z^-1)^-1/(1+z^-1)", []float64{ 1.0 }, []float64{ 0.0, 1.0, 1.0 } },
+
+	}
+
+	for _, test := range tests {
+
+		filter, err := parseTransferFunction(test.text)
+
+		if ((err != nil) || (filter.domain() != Digital)) {
+
+			t.Errorf("%q: %v in the %s domain", test.text, err, filter.domain())
+			continue
+
+		}
+
+		if numerator, denominator := filter.digitalCoefficients(); (!slices.Equal(numerator, test.numerator) || !slices.Equal(denominator, test.denominator)) {
+
+			t.Errorf("%q = %v / %v, want %v / %v", test.text, numerator, denominator, test.numerator, test.denominator)
+
+		}
+
+	}
+
+}
+
+func TestParseFormatted(t *testing.T) {
+
+	// ascii output of the formatter reads back as the same transfer function
+	filters := []Polynomial{ analoguePolynomial([]float64{ 2.0, 0.0, 0.0 }, []float64{ 1.0, 3.2, 5.1, 4.2, 1.5 }), digitalPolynomial([]float64{ 0.02, 0.04, 0.02 }, []float64{ 1.0, -1.56, 0.64 }) }
+
+	for _, filter := range filters {
+
+		for _, factored := range []bool{ false, true } {
+
+			text := filter.format(Formatting{ Markup: ASCII, Factored: factored, Label: "H" })
+			parsed, err := parseTransferFunction(text)
+
+			if (err != nil) {
+
+				t.Errorf("%q: %v", text, err)
+				continue
+
+			}
+
+			for _, frequency := range []float64{ 0.05, 0.3, 1.0, 2.0 } {
+
+				x := complex(0, frequency)
+
+				if (filter.domain() == Digital) {
+
+					x = cmplx.Exp(x)
+
+				}
+
+				if got, want := parsed.response(x), filter.response(x); (cmplx.Abs(got - want) > 1e-9*math.Max(cmplx.Abs(want), 1.0)) {
+
+					t.Errorf("%q at %v = %v, want %v", text, x, got, want)
+
+				}
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestParseErrors(t *testing.T) {
+
+	tests := []struct {
+
+		text	 string
+		position int
+		message  string
+
+	}{
+
+		{ "(s^2 + 1", 9, "expected ')' to close '(' at column 1, found end of input" },
+		{ "s^2 + + ", 9, "expected a number, variable or '(', found end of input" },
+		{ "", 1, "expected a number, variable or '(', found end of input" },
+		{ "s^2.5", 3, "exponent \"2.5\" must be an integer" },
+		{ "s + z", 5, "unexpected variable \"z\", expression is in \"s\"" },
+		{ "1/(s - s)", 2, "division by zero" },
+		{ "(s+1))", 6, "unmatched ')'" },
+		{ "s^", 3, "expected an integer exponent, found end of input" },
+		{ "2 $ s", 3, "unexpected '$'" },
+		{ "= s", 1, "expected a label such as H(s) before '='" },
+		{ "s^(2", 5, "expected ')' to close '(' at column 3, found end of input" },
+		{ "0^-1", 1, "zero raised to a negative power" },
+		{ "s^1001", 3, "exponent 1001 exceeds the limit of 1000" },
+		{ "s¹⁰⁰¹", 2, "exponent 1001 exceeds the limit of 1000" },
+		{ "s^(-2001)", 5, "exponent -2001 exceeds the limit of 1000" },
+		{ "s¹⁻", 2, "invalid superscript exponent \"1-\"" },
+		{ "1..2s", 1, "invalid number \"1..2\"" },
+		{ "((s+1)^600)^2", 1, "expression degree 1200 exceeds the limit of 1000" },
+
+	}
+
+	for _, test := range tests {
+
+		_, err := parseTransferFunction(test.text)
+		failure := &ParseError{}
+
+		if (!errors.As(err, &failure) || (failure.Position != test.position) || (failure.Message != test.message)) {
+
+			t.Errorf("%q: got %v, want column %d: %s", test.text, err, test.position, test.message)
+
+		}
+
+	}
+
+	// the caret sits under the reported column
+	if _, err := parseTransferFunction("(s^2 + 1"); ((err == nil) || (err.Error() != "column 9: expected ')' to close '(' at column 1, found end of input\n  (s^2 + 1\n          ^")) {
+
+		t.Errorf("got %q", err)
+
+	}
+
+}
@@ -0,0 +1,156 @@
+package main
+
+import ( "fmt"
+		 "math" )
+
+
+func analoguePrototype(config Specs) Polynomial {
+
+	approximation := string(Butterworth)
+
+	if config.Approximation.exists() {
+
+		approximation = string(config.Approximation)
+
+	}
+
+	order := uint16(4)
+
+	if ((config.Order != nil) && (*config.Order > 0)) {
+
+		order = *config.Order
+
+	}
+
+	epsilonPass := 1.0
+
+	if (config.PassbandAttenuation != nil) {
+
+		epsilonPass = math.Sqrt(math.Pow(10, math.Abs(*config.PassbandAttenuation) / 10.0) - 1.0)
+
+	}
+
+	epsilonStop := math.Sqrt(math.Pow(10, 4.0) - 1.0)
+
+	if (config.StopbandAttenuation != nil) {
+
+		epsilonStop = math.Sqrt(math.Pow(10, math.Abs(*config.StopbandAttenuation) / 10.0) - 1.0)
+
+	}
+
+	return analogueLowPassFilterPrototype(approximation, order, epsilonPass, epsilonStop)
+
+}
+
+func analogueFrequencies(config Specs) (float64, float64, float64) {
+
+	cutoffFrequency := 1000.0
+
+	if ((config.CutoffFrequency != nil) && (*config.CutoffFrequency > 0.0)) {
+
+		cutoffFrequency = *config.CutoffFrequency
+
+	}
+
+	centerFrequency := cutoffFrequency
+	bandwidth := centerFrequency / 2.0
+
+	if ((config.LowerPassbandEdgeFrequency != nil) &&
+		(config.UpperPassbandEdgeFrequency != nil) &&
+		(*config.LowerPassbandEdgeFrequency > 0.0) &&
+		(*config.UpperPassbandEdgeFrequency > 0.0)) {
+
+		lower := math.Min(*config.LowerPassbandEdgeFrequency, *config.UpperPassbandEdgeFrequency)
+		upper := math.Max(*config.LowerPassbandEdgeFrequency, *config.UpperPassbandEdgeFrequency)
+		centerFrequency = math.Sqrt(lower*upper)
+		bandwidth = upper - lower
+
+	}
+
+	if ((config.CenterFrequency != nil) && (*config.CenterFrequency > 0.0)) {
+
+		centerFrequency = *config.CenterFrequency
+
+	}
+
+	if ((config.Bandwidth != nil) && (*config.Bandwidth > 0.0)) {
+
+		bandwidth = *config.Bandwidth
+
+	}
+
+	return cutoffFrequency, centerFrequency, bandwidth
+
+}
+
+func designPassive(config Specs) (Ladder, error) {
+
+	source := 50.0
+
+	if ((config.SourceImpedance != nil) && (*config.SourceImpedance > 0.0)) {
+
+		source = *config.SourceImpedance
+
+	}
+
+	load := source
+
+	if ((config.LoadImpedance != nil) && (*config.LoadImpedance > 0.0)) {
+
+		load = *config.LoadImpedance
+
+	}
+
+	cutoffFrequency, centerFrequency, bandwidth := analogueFrequencies(config)
+	prototype := analoguePrototype(config)
+	ladder, err := synthesizeLadder(prototype, config.Expansion, load / source)
+
+	if (err != nil) {
+
+		return ladder, err
+
+	}
+
+	if (math.Abs(ladder.Load*source / load - 1.0) > 1e-6) {
+
+		err = fmt.Errorf("ladder terminates in %.6g ohm instead of the requested %.6g ohm", ladder.Load*source, load)
+
+		if config.Expansion.exists() {
+
+			dual := CauerI
+
+			if (config.Expansion == CauerI) {
+
+				dual = CauerII
+
+			}
+
+			if alternative, failure := synthesizeLadder(prototype, dual, load / source); ((failure == nil) && (math.Abs(alternative.Load*source / load - 1.0) <= 1e-6)) {
+
+				err = fmt.Errorf("%s expansion terminates in %.6g ohm instead of the requested %.6g ohm, %s reaches it", config.Expansion, ladder.Load*source, load, dual)
+
+			}
+
+		}
+
+	}
+
+	switch config.Response {
+
+		case HPF:
+
+			return ladder.highPass().scale(cutoffFrequency, source), err
+
+		case BPF:
+
+			return ladder.bandPass(bandwidth / centerFrequency).scale(centerFrequency, source), err
+
+		case BSF, BRF:
+
+			return ladder.bandStop(bandwidth / centerFrequency).scale(centerFrequency, source), err
+
+	}
+
+	return ladder.scale(cutoffFrequency, source), err
+
+}
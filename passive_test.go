@@ -0,0 +1,138 @@
+package main
+
+import ( "math"
+		 "math/cmplx"
+		 "testing" )
+
+
+func TestPassiveButterworthValues(t *testing.T) {
+
+	// the normalized third order butterworth ladder is g = 1, 2, 1, here at 1 kHz between 50 ohm terminations
+	capacitance := 1.0 / (2.0*math.Pi*1000.0*50.0)
+	inductance := 50.0 / (2.0*math.Pi*1000.0)
+
+	tests := []struct {
+
+		expansion Expansion
+		want	  []Element
+
+	}{
+
+		{ CauerI, []Element{ { Shunt: true, Capacitance: capacitance }, { Inductance: 2.0*inductance }, { Shunt: true, Capacitance: capacitance } } },
+		{ CauerII, []Element{ { Inductance: inductance }, { Shunt: true, Capacitance: 2.0*capacitance }, { Inductance: inductance } } },
+
+	}
+
+	for _, test := range tests {
+
+		ladder, err := designPassive(Specs{ Order: pointer(uint16(3)), CutoffFrequency: pointer(1000.0), Expansion: test.expansion })
+
+		if (err != nil) {
+
+			t.Fatalf("%s: %v", test.expansion, err)
+
+		}
+
+		if ((len(ladder.Elements) != 3) || (ladder.Source != 50.0) || (ladder.Load != 50.0)) {
+
+			t.Fatalf("%s: got %+v", test.expansion, ladder)
+
+		}
+
+		for index, element := range ladder.Elements {
+
+			want := test.want[index]
+
+			if ((element.Shunt != want.Shunt) ||
+				(math.Abs(element.Inductance - want.Inductance) > 1e-12) ||
+				(math.Abs(element.Capacitance - want.Capacitance) > 1e-15)) {
+
+				t.Errorf("%s element %d = %+v, want %+v", test.expansion, index, element, want)
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestPassiveResponses(t *testing.T) {
+
+	prototype := analogueLowPassFilterPrototype(string(Chebyshev), 5, math.Sqrt(math.Pow(10, 0.1) - 1.0), 100.0)
+
+	// each response maps its frequency onto the low-pass prototype axis
+	tests := []struct {
+
+		response Response
+		mapping  func(frequency float64) float64
+
+	}{
+
+		{ LPF, func(f float64) float64 { return f / 1000.0 } },
+		{ HPF, func(f float64) float64 { return -1000.0 / f } },
+		{ BPF, func(f float64) float64 { return (f*f - 1e8) / (2000.0*f) } },
+		{ BSF, func(f float64) float64 { return -2000.0*f / (f*f - 1e8) } },
+
+	}
+
+	for _, test := range tests {
+
+		for _, load := range []float64{ 50.0, 100.0 } {
+
+			config := Specs{ Response: test.response, Approximation: Chebyshev, Order: pointer(uint16(5)), PassbandAttenuation: pointer(1.0), LoadImpedance: pointer(load), CutoffFrequency: pointer(1000.0), CenterFrequency: pointer(10000.0), Bandwidth: pointer(2000.0) }
+			ladder, err := designPassive(config)
+
+			if (err != nil) {
+
+				t.Fatalf("%s into %g ohm: %v", test.response, load, err)
+
+			}
+
+			// unequal terminations only scale the flat gain
+			scale := cmplx.Abs(ladder.response(300.0)) / cmplx.Abs(prototype.response(complex(0, test.mapping(300.0))))
+
+			for _, frequency := range []float64{ 800.0, 1500.0, 9000.0, 10500.0, 20000.0 } {
+
+				want := scale*cmplx.Abs(prototype.response(complex(0, test.mapping(frequency))))
+
+				if got := cmplx.Abs(ladder.response(frequency)); (math.Abs(got - want) > 1e-6) {
+
+					t.Errorf("%s into %g ohm at %g Hz = %g, want %g", test.response, load, frequency, got, want)
+
+				}
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestPassiveErrors(t *testing.T) {
+
+	tests := []struct {
+
+		config  Specs
+		message string
+
+	}{
+
+		{ Specs{ Approximation: Elliptic, Order: pointer(uint16(4)) }, "ladder synthesis only realizes all-pole prototypes, this one has finite transmission zeros" },
+		{ Specs{ Approximation: Chebyshev, Order: pointer(uint16(4)), PassbandAttenuation: pointer(1.0) }, "ladder terminates in 18.799 ohm instead of the requested 50 ohm" },
+		{ Specs{ Approximation: Chebyshev, Order: pointer(uint16(4)), PassbandAttenuation: pointer(1.0), LoadImpedance: pointer(200.0), Expansion: CauerI }, "cauer i expansion terminates in 12.5 ohm instead of the requested 200 ohm, cauer ii reaches it" },
+
+	}
+
+	for _, test := range tests {
+
+		if _, err := designPassive(test.config); ((err == nil) || (err.Error() != test.message)) {
+
+			t.Errorf("got %v, want %q", err, test.message)
+
+		}
+
+	}
+
+}
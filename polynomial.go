@@ -1,9 +1,9 @@
 package main
 
-import ( "fmt"
-		 // "math/cmplx"
+import ( "math/cmplx"
 		 "math"
-		 "slices" )
+		 "slices"
+		 "cmp" )
 
 
 func constructPolynomial(parameters ...map[string]interface{}) Polynomial {
@@ -36,7 +36,7 @@ func dotProduct(p []Term, q []Term) map[int64]float64 {
 
 			if exists {
 
-				expression[exponent] += coeffient
+				expression[exponent] += coefficient
 
 			} else {
 
@@ -151,3 +151,409 @@ func extract(parameters ...map[string]interface{}) (map[int64]float64, map[int64
     return numerator, denominator, variable
 
 }
+
+func digitalPolynomial(numerator []float64, denominator []float64) Polynomial {
+
+	top := map[int64]float64{}
+	bottom := map[int64]float64{}
+
+	for index, coefficient := range numerator {
+
+		if (coefficient != 0.0) {
+
+			top[-int64(index)] = coefficient
+
+		}
+
+	}
+
+	for index, coefficient := range denominator {
+
+		if (coefficient != 0.0) {
+
+			bottom[-int64(index)] = coefficient
+
+		}
+
+	}
+
+	term := map[string]interface{}{
+
+		"variable": "z",
+		"numerator": top,
+		"denominator": bottom,
+
+	}
+
+	return constructPolynomial(term)
+
+}
+
+func analoguePolynomial(numerator []float64, denominator []float64) Polynomial {
+
+	top := map[int64]float64{}
+	bottom := map[int64]float64{}
+
+	for index, coefficient := range numerator {
+
+		if (coefficient != 0.0) {
+
+			top[int64(len(numerator) - index - 1)] = coefficient
+
+		}
+
+	}
+
+	for index, coefficient := range denominator {
+
+		if (coefficient != 0.0) {
+
+			bottom[int64(len(denominator) - index - 1)] = coefficient
+
+		}
+
+	}
+
+	term := map[string]interface{}{
+
+		"variable": "s",
+		"numerator": top,
+		"denominator": bottom,
+
+	}
+
+	return constructPolynomial(term)
+
+}
+
+func cascadePolynomial(sections []Section) Polynomial {
+
+	if (len(sections) == 0) {
+
+		return digitalPolynomial([]float64{ 1.0 }, []float64{ 1.0 })
+
+	}
+
+	tf := sections[0].polynomial()
+
+	for _, section := range sections[1:] {
+
+		tf.multiply(section.polynomial())
+
+	}
+
+	return tf
+
+}
+
+func exponentRange(expressions ...Expression) (int64, int64) {
+
+	minimum := int64(0)
+	maximum := int64(0)
+	initialised := false
+
+	for _, expression := range expressions {
+
+		for _, term := range expression.Terms {
+
+			if (!initialised || (term.Exponent < minimum)) {
+
+				minimum = term.Exponent
+
+			}
+
+			if (!initialised || (term.Exponent > maximum)) {
+
+				maximum = term.Exponent
+
+			}
+
+			initialised = true
+
+		}
+
+	}
+
+	return minimum, maximum
+
+}
+
+func (e *Expression) coefficient(exponent int64) float64 {
+
+	c := 0.0
+
+	for _, term := range e.Terms {
+
+		if (term.Exponent == exponent) {
+
+			c += term.Coefficient
+
+		}
+
+	}
+
+	return c
+
+}
+
+func (p *Polynomial) domain() Domain {
+
+	for _, term := range append(slices.Clone(p.Numerator.Terms), p.Denominator.Terms...) {
+
+		if (term.Variable == "z") {
+
+			return Digital
+
+		}
+
+	}
+
+	return Analogue
+
+}
+
+func (p *Polynomial) digitalCoefficients() ([]float64, []float64) {
+
+	minimum, maximum := exponentRange(p.Numerator, p.Denominator)
+	length := int(maximum - minimum) + 1
+	numerator := make([]float64, length)
+	denominator := make([]float64, length)
+
+	for index := 0; index < length; index++ {
+
+		numerator[index] = p.Numerator.coefficient(maximum - int64(index))
+		denominator[index] = p.Denominator.coefficient(maximum - int64(index))
+
+	}
+
+	for ((len(denominator) > 1) &&
+		 (denominator[0] == 0.0) &&
+		 (numerator[0] == 0.0)) {
+
+		numerator = numerator[1:]
+		denominator = denominator[1:]
+
+	}
+
+	for ((len(numerator) > 1) && (numerator[len(numerator) - 1] == 0.0)) {
+
+		numerator = numerator[:len(numerator) - 1]
+
+	}
+
+	for ((len(denominator) > 1) && (denominator[len(denominator) - 1] == 0.0)) {
+
+		denominator = denominator[:len(denominator) - 1]
+
+	}
+
+	if ((denominator[0] != 0.0) && (denominator[0] != 1.0)) {
+
+		scale := denominator[0]
+
+		for index := range numerator {
+
+			numerator[index] /= scale
+
+		}
+
+		for index := range denominator {
+
+			denominator[index] /= scale
+
+		}
+
+	}
+
+	return numerator, denominator
+
+}
+
+func (p *Polynomial) analogueCoefficients() ([]float64, []float64) {
+
+	minimum, maximum := exponentRange(p.Numerator, p.Denominator)
+	length := int(maximum - minimum) + 1
+	numerator := make([]float64, length)
+	denominator := make([]float64, length)
+
+	for index := 0; index < length; index++ {
+
+		numerator[index] = p.Numerator.coefficient(maximum - int64(index))
+		denominator[index] = p.Denominator.coefficient(maximum - int64(index))
+
+	}
+
+	for ((len(numerator) > 1) && (numerator[0] == 0.0)) {
+
+		numerator = numerator[1:]
+
+	}
+
+	for ((len(denominator) > 1) && (denominator[0] == 0.0)) {
+
+		denominator = denominator[1:]
+
+	}
+
+	return numerator, denominator
+
+}
+
+func accumulate(p []Term, q []Term) map[int64]float64 {
+
+	expression := map[int64]float64{}
+
+	for _, term := range append(append([]Term{}, p...), q...) {
+
+		expression[term.Exponent] += term.Coefficient
+
+	}
+
+	return expression
+
+}
+
+func equalExpressions(p Expression, q Expression) bool {
+
+	left := accumulate(p.Terms, []Term{})
+	right := accumulate(q.Terms, []Term{})
+	tolerance := 1e-12
+
+	for exponent := range accumulate(p.Terms, q.Terms) {
+
+		difference := math.Abs(left[exponent] - right[exponent])
+		scale := math.Max(math.Abs(left[exponent]), math.Abs(right[exponent]))
+
+		if (difference > tolerance*math.Max(scale, 1.0)) {
+
+			return false
+
+		}
+
+	}
+
+	return true
+
+}
+
+func mergeFactors(factors [][3]float64) [][3]float64 {
+
+	merged := [][3]float64{}
+	pending := -1
+
+	for index, factor := range factors {
+
+		if (factor[2] != 0.0) {
+
+			merged = append(merged, factor)
+
+		} else if (pending < 0) {
+
+			pending = index
+
+		} else {
+
+			product := convolve(factors[pending][:2], factor[:2])
+			merged = append(merged, [3]float64{ product[0], product[1], product[2] })
+			pending = -1
+
+		}
+
+	}
+
+	if (pending >= 0) {
+
+		merged = append(merged, factors[pending])
+
+	}
+
+	return merged
+
+}
+
+func factorRoot(factor [3]float64) complex128 {
+
+	if (factor[0] == 0.0) {
+
+		return complex(0, 0)
+
+	}
+
+	discriminant := cmplx.Sqrt(complex(math.Pow(factor[1], 2) - 4.0*factor[0]*factor[2], 0))
+	return (complex(-factor[1], 0) + discriminant) / complex(2.0*factor[0], 0)
+
+}
+
+func (p *Polynomial) sections() []Section {
+
+	numerator, denominator := p.digitalCoefficients()
+	zeroFactors := [][3]float64{}
+
+	for ((len(numerator) > 1) && (numerator[0] == 0.0)) {
+
+		numerator = numerator[1:]
+		zeroFactors = append(zeroFactors, [3]float64{ 0.0, 1.0, 0.0 })
+
+	}
+
+	gain := numerator[0]
+	zeroFactors = mergeFactors(append(quadraticFactors(polynomialRoots(numerator)), zeroFactors...))
+	poleFactors := mergeFactors(quadraticFactors(polynomialRoots(denominator)))
+	count := max(len(zeroFactors), len(poleFactors), 1)
+
+	for (len(zeroFactors) < count) {
+
+		zeroFactors = append(zeroFactors, [3]float64{ 1.0, 0.0, 0.0 })
+
+	}
+
+	for (len(poleFactors) < count) {
+
+		poleFactors = append(poleFactors, [3]float64{ 1.0, 0.0, 0.0 })
+
+	}
+
+	slices.SortStableFunc(poleFactors, func(p [3]float64, q [3]float64) int {
+
+		return cmp.Compare(cmplx.Abs(factorRoot(q)), cmplx.Abs(factorRoot(p)))
+
+	})
+
+	sections := make([]Section, count)
+
+	for index, pole := range poleFactors {
+
+		nearest := 0
+
+		for candidate := range zeroFactors {
+
+			distance := cmplx.Abs(factorRoot(zeroFactors[candidate]) - factorRoot(pole))
+
+			if (distance < cmplx.Abs(factorRoot(zeroFactors[nearest]) - factorRoot(pole))) {
+
+				nearest = candidate
+
+			}
+
+		}
+
+		sections[count - index - 1] = Section{
+
+			Numerator: zeroFactors[nearest],
+			Denominator: pole,
+
+		}
+
+		zeroFactors = slices.Delete(zeroFactors, nearest, nearest + 1)
+
+	}
+
+	for index := range sections[0].Numerator {
+
+		sections[0].Numerator[index] *= gain
+
+	}
+
+	return sections
+
+}
@@ -0,0 +1,212 @@
+package main
+
+import ( "slices" )
+
+
+type Processor struct {
+
+	Structure	Structure
+	Numerator	[]float64
+	Denominator []float64
+	Sections	[]Section
+	input		[]float64
+	output		[]float64
+	state		[]float64
+
+}
+
+func newProcessor(filter Polynomial, structure ...Structure) *Processor {
+
+	form := TransposedDirectFormII
+
+	if ((len(structure) > 0) &&
+		structure[0].exists()) {
+
+		form = structure[0]
+
+	}
+
+	if (form == SecondOrderSections) {
+
+		return newCascadeProcessor(filter.sections())
+
+	}
+
+	numerator, denominator := filter.digitalCoefficients()
+	order := max(len(numerator), len(denominator))
+	numerator = append(numerator, make([]float64, order - len(numerator))...)
+	denominator = append(denominator, make([]float64, order - len(denominator))...)
+
+	return &Processor{
+
+		Structure: form,
+		Numerator: numerator,
+		Denominator: denominator,
+		input: make([]float64, order),
+		output: make([]float64, order),
+		state: make([]float64, order),
+
+	}
+
+}
+
+func newCascadeProcessor(sections []Section) *Processor {
+
+	cascade := make([]Section, len(sections))
+
+	for index, section := range sections {
+
+		scale := section.Denominator[0]
+
+		if (scale == 0.0) {
+
+			scale = 1.0
+
+		}
+
+		for k := range section.Numerator {
+
+			cascade[index].Numerator[k] = section.Numerator[k] / scale
+			cascade[index].Denominator[k] = section.Denominator[k] / scale
+
+		}
+
+	}
+
+	return &Processor{
+
+		Structure: SecondOrderSections,
+		Sections: cascade,
+		state: make([]float64, 2*len(cascade)),
+
+	}
+
+}
+
+func (p *Processor) Reset() {
+
+	clear(p.input)
+	clear(p.output)
+	clear(p.state)
+
+}
+
+func (p *Processor) Process(x float64) float64 {
+
+	switch p.Structure {
+
+		case DirectFormI:
+
+			copy(p.input[1:], p.input)
+			p.input[0] = x
+			y := 0.0
+
+			for k, b := range p.Numerator {
+
+				y += b*p.input[k]
+
+			}
+
+			for k := 1; k < len(p.Denominator); k++ {
+
+				y -= p.Denominator[k]*p.output[k - 1]
+
+			}
+
+			copy(p.output[1:], p.output)
+			p.output[0] = y
+			return y
+
+		case DirectFormII:
+
+			w := x
+
+			for k := 1; k < len(p.Denominator); k++ {
+
+				w -= p.Denominator[k]*p.state[k - 1]
+
+			}
+
+			y := p.Numerator[0]*w
+
+			for k := 1; k < len(p.Numerator); k++ {
+
+				y += p.Numerator[k]*p.state[k - 1]
+
+			}
+
+			copy(p.state[1:], p.state)
+			p.state[0] = w
+			return y
+
+		case SecondOrderSections:
+
+			y := x
+
+			for index := range p.Sections {
+
+				b := &p.Sections[index].Numerator
+				a := &p.Sections[index].Denominator
+				s := p.state[2*index:2*index + 2]
+				v := b[0]*y + s[0]
+				s[0] = b[1]*y - a[1]*v + s[1]
+				s[1] = b[2]*y - a[2]*v
+				y = v
+
+			}
+
+			return y
+
+		default:
+
+			last := len(p.Numerator) - 1
+			y := p.Numerator[0]*x
+
+			if (last > 0) {
+
+				y += p.state[0]
+
+			}
+
+			for k := 0; k < last; k++ {
+
+				s := p.Numerator[k + 1]*x - p.Denominator[k + 1]*y
+
+				if (k + 1 < last) {
+
+					s += p.state[k + 1]
+
+				}
+
+				p.state[k] = s
+
+			}
+
+			return y
+
+	}
+
+}
+
+func (p *Processor) ProcessBlock(in []float64, out []float64) {
+
+	for index := 0; index < min(len(in), len(out)); index++ {
+
+		out[index] = p.Process(in[index])
+
+	}
+
+}
+
+func (p *Processor) clone() *Processor {
+
+	duplicate := *p
+	duplicate.Numerator = slices.Clone(p.Numerator)
+	duplicate.Denominator = slices.Clone(p.Denominator)
+	duplicate.Sections = slices.Clone(p.Sections)
+	duplicate.input = slices.Clone(p.input)
+	duplicate.output = slices.Clone(p.output)
+	duplicate.state = slices.Clone(p.state)
+	return &duplicate
+
+}
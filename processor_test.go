@@ -0,0 +1,174 @@
+package main
+
+import ( "cmp"
+		 "math"
+		 "math/cmplx"
+		 "slices"
+		 "testing" )
+
+
+// pairs the equalizer denominator with well separated zeros, the fourfold butterworth zero at z = -1
+// would limit the section factorization to root finding precision
+var processorNumerator = []float64{ 0.1, 0.2, 0.3, -0.1, 0.05 }
+
+// direct evaluation of the difference equation a[0] y[n] = Σ b[k] x[n - k] - Σ a[k] y[n - k]
+func referenceFilter(b []float64, a []float64, x []float64) []float64 {
+
+	y := make([]float64, len(x))
+
+	for n := range x {
+
+		for k := range b {
+
+			if (n - k >= 0) {
+
+				y[n] += b[k]*x[n - k]
+
+			}
+
+		}
+
+		for k := 1; k < len(a); k++ {
+
+			if (n - k >= 0) {
+
+				y[n] -= a[k]*y[n - k]
+
+			}
+
+		}
+
+		y[n] /= a[0]
+
+	}
+
+	return y
+
+}
+
+func testSignal(length int) []float64 {
+
+	signal := make([]float64, length)
+
+	for index := range signal {
+
+		signal[index] = math.Sin(0.3*float64(index)) + 0.1*float64(index%7)
+
+	}
+
+	return signal
+
+}
+
+func TestProcessorStructures(t *testing.T) {
+
+	filter := digitalPolynomial(processorNumerator, equalizerDenominator)
+	signal := testSignal(200)
+	want := referenceFilter(processorNumerator, equalizerDenominator, signal)
+
+	for _, structure := range []Structure{ DirectFormI, DirectFormII, TransposedDirectFormII, SecondOrderSections } {
+
+		processor := newProcessor(filter, structure)
+		got := make([]float64, len(signal))
+
+		// state carries across blocks
+		processor.ProcessBlock(signal[:50], got[:50])
+		processor.ProcessBlock(signal[50:], got[50:])
+		compareSignals(t, string(structure), got, want, 1e-12)
+
+		processor.Reset()
+		processor.ProcessBlock(signal, got)
+		compareSignals(t, string(structure) + " after reset", got, want, 1e-12)
+
+		if allocations := testing.AllocsPerRun(100, func() { processor.ProcessBlock(signal, got) }); (allocations != 0) {
+
+			t.Errorf("%s: %g allocations per block, want none", structure, allocations)
+
+		}
+
+	}
+
+}
+
+func TestProcessorDefaultStructure(t *testing.T) {
+
+	processor := newProcessor(digitalPolynomial([]float64{ 0.5, 0.5 }, []float64{ 1.0 }))
+
+	if (processor.Structure != TransposedDirectFormII) {
+
+		t.Errorf("default structure is %s, want %s", processor.Structure, TransposedDirectFormII)
+
+	}
+
+	got := make([]float64, 4)
+	processor.ProcessBlock([]float64{ 1.0, 0.0, 2.0, 2.0 }, got)
+	compareSignals(t, "moving average", got, []float64{ 0.5, 0.5, 1.0, 2.0 }, 0.0)
+
+}
+
+func TestProcessorClone(t *testing.T) {
+
+	processor := newProcessor(digitalPolynomial(processorNumerator, equalizerDenominator))
+	processor.Process(1.0)
+	duplicate := processor.clone()
+	original := processor.Process(0.5)
+
+	if got := duplicate.Process(0.5); (got != original) {
+
+		t.Errorf("clone produced %g, want %g", got, original)
+
+	}
+
+}
+
+func TestPolynomialRoots(t *testing.T) {
+
+	roots := polynomialRoots([]float64{ 1.0, -6.0, 11.0, -6.0 })
+	slices.SortFunc(roots, func(p complex128, q complex128) int { return cmp.Compare(real(p), real(q)) })
+
+	for index, want := range []complex128{ 1, 2, 3 } {
+
+		if (cmplx.Abs(roots[index] - want) > 1e-9) {
+
+			t.Errorf("root %d = %v, want %v", index, roots[index], want)
+
+		}
+
+	}
+
+	for _, root := range polynomialRoots([]float64{ 1.0, 0.0, 4.0 }) {
+
+		if (cmplx.Abs(root*root + 4.0) > 1e-9) {
+
+			t.Errorf("%v is not a root of s² + 4", root)
+
+		}
+
+	}
+
+}
+
+func TestSections(t *testing.T) {
+
+	filter := digitalPolynomial(processorNumerator, equalizerDenominator)
+	sections := filter.sections()
+
+	if (len(sections) != 2) {
+
+		t.Fatalf("got %d sections, want 2", len(sections))
+
+	}
+
+	for _, frequency := range []float64{ 0.0, 50.0, 100.0, 300.0 } {
+
+		want := frequencyResponse(filter, frequency, 1000.0)
+
+		if got := frequencyResponse(cascadePolynomial(sections), frequency, 1000.0); (cmplx.Abs(got - want) > 1e-9) {
+
+			t.Errorf("cascade response at %g Hz = %v, want %v", frequency, got, want)
+
+		}
+
+	}
+
+}
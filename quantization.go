@@ -0,0 +1,469 @@
+package main
+
+import ( "math"
+		 "math/cmplx" )
+
+
+type Quantization struct {
+
+	Format		   Format
+	Rounding	   Rounding
+	Sections	   []Section
+	Filter		   Polynomial
+	Poles		   []complex128
+	QuantizedPoles []complex128
+	PoleMovement   float64
+	Deviation	   float64
+	Stable		   bool
+	Overflow	   bool
+
+}
+
+func (f Format) bits() uint16 {
+
+	return f.Integer + f.Fraction + 1
+
+}
+
+func (f Format) limits() (int64, int64) {
+
+	magnitude := int64(1) << (f.Integer + f.Fraction)
+	return -magnitude, magnitude - 1
+
+}
+
+func (f Format) step() float64 {
+
+	return math.Ldexp(1.0, -int(f.Fraction))
+
+}
+
+func roundValue(x float64, rounding Rounding) float64 {
+
+	switch rounding {
+
+		case Truncate:
+
+			return math.Floor(x)
+
+		case TowardZero:
+
+			return math.Trunc(x)
+
+		case Convergent:
+
+			return math.RoundToEven(x)
+
+		default:
+
+			return math.Floor(x + 0.5)
+
+	}
+
+}
+
+func (f Format) quantize(x float64, rounding Rounding) (int64, bool) {
+
+	minimum, maximum := f.limits()
+	scaled := roundValue(math.Ldexp(x, int(f.Fraction)), rounding)
+
+	if (scaled > float64(maximum)) {
+
+		return maximum, true
+
+	}
+
+	if (scaled < float64(minimum)) {
+
+		return minimum, true
+
+	}
+
+	return int64(scaled), false
+
+}
+
+func (f Format) value(q int64) float64 {
+
+	return float64(q)*f.step()
+
+}
+
+func quantizeCoefficients(coefficients []float64, format Format, rounding Rounding) ([]float64, bool) {
+
+	quantized := make([]float64, len(coefficients))
+	overflow := false
+
+	for index, coefficient := range coefficients {
+
+		q, saturated := format.quantize(coefficient, rounding)
+		quantized[index] = format.value(q)
+		overflow = overflow || saturated
+
+	}
+
+	return quantized, overflow
+
+}
+
+func quantizeTransfer(numerator []float64, denominator []float64, format Format, rounding Rounding) ([]float64, []float64, bool) {
+
+	scale := 1.0
+
+	if ((len(denominator) > 0) && (denominator[0] != 0.0)) {
+
+		scale = denominator[0]
+
+	}
+
+	normalizedNumerator := make([]float64, len(numerator))
+	normalizedDenominator := make([]float64, max(len(denominator) - 1, 0))
+
+	for index, coefficient := range numerator {
+
+		normalizedNumerator[index] = coefficient / scale
+
+	}
+
+	for index := range normalizedDenominator {
+
+		normalizedDenominator[index] = denominator[index + 1] / scale
+
+	}
+
+	quantizedNumerator, saturatedNumerator := quantizeCoefficients(normalizedNumerator, format, rounding)
+	quantizedDenominator, saturatedDenominator := quantizeCoefficients(normalizedDenominator, format, rounding)
+	return quantizedNumerator, append([]float64{ 1.0 }, quantizedDenominator...), saturatedNumerator || saturatedDenominator
+
+}
+
+func sectionPoles(sections []Section) []complex128 {
+
+	poles := []complex128{}
+
+	for _, section := range sections {
+
+		poles = append(poles, polynomialRoots(section.Denominator[:])...)
+
+	}
+
+	return poles
+
+}
+
+func stableSections(sections []Section) bool {
+
+	for _, section := range sections {
+
+		a0 := section.Denominator[0]
+
+		if (a0 == 0.0) {
+
+			return false
+
+		}
+
+		a1 := section.Denominator[1] / a0
+		a2 := section.Denominator[2] / a0
+
+		if ((math.Abs(a2) >= 1.0) ||
+			(math.Abs(a1) >= 1.0 + a2)) {
+
+			return false
+
+		}
+
+	}
+
+	return true
+
+}
+
+func stablePolynomial(filter Polynomial) bool {
+
+	_, denominator := filter.digitalCoefficients()
+
+	for _, pole := range polynomialRoots(denominator) {
+
+		if (cmplx.Abs(pole) >= 1.0) {
+
+			return false
+
+		}
+
+	}
+
+	return true
+
+}
+
+func poleMovement(original []complex128, quantized []complex128) float64 {
+
+	movement := 0.0
+	remaining := append([]complex128{}, quantized...)
+
+	for _, pole := range original {
+
+		if (len(remaining) == 0) {
+
+			break
+
+		}
+
+		nearest := 0
+
+		for index := range remaining {
+
+			if (cmplx.Abs(remaining[index] - pole) < cmplx.Abs(remaining[nearest] - pole)) {
+
+				nearest = index
+
+			}
+
+		}
+
+		movement = math.Max(movement, cmplx.Abs(remaining[nearest] - pole))
+		remaining = append(remaining[:nearest], remaining[nearest + 1:]...)
+
+	}
+
+	return movement
+
+}
+
+func responseDeviation(original []Polynomial, quantized []Polynomial) float64 {
+
+	deviation := 0.0
+
+	for _, frequency := range linearSpace(0.0, 0.5, 512) {
+
+		reference := complex(1, 0)
+		approximation := complex(1, 0)
+
+		for _, stage := range original {
+
+			reference *= frequencyResponse(stage, frequency, 1.0)
+
+		}
+
+		for _, stage := range quantized {
+
+			approximation *= frequencyResponse(stage, frequency, 1.0)
+
+		}
+
+		if (cmplx.Abs(reference) > 1e-6) {
+
+			deviation = math.Max(deviation, math.Abs(decibels(cmplx.Abs(approximation)) - decibels(cmplx.Abs(reference))))
+
+		}
+
+	}
+
+	return deviation
+
+}
+
+func quantizeSections(sections []Section, format Format, rounding ...Rounding) Quantization {
+
+	mode := Nearest
+
+	if ((len(rounding) > 0) &&
+		rounding[0].exists()) {
+
+		mode = rounding[0]
+
+	}
+
+	quantized := make([]Section, len(sections))
+	original := []Polynomial{}
+	stages := []Polynomial{}
+	overflow := false
+
+	for index, section := range sections {
+
+		numerator, denominator, saturated := quantizeTransfer(section.Numerator[:], section.Denominator[:], format, mode)
+		overflow = overflow || saturated
+		copy(quantized[index].Numerator[:], numerator)
+		copy(quantized[index].Denominator[:], denominator)
+		original = append(original, section.polynomial())
+		stages = append(stages, quantized[index].polynomial())
+
+	}
+
+	poles := sectionPoles(sections)
+	quantizedPoles := sectionPoles(quantized)
+
+	return Quantization{
+
+		Format: format,
+		Rounding: mode,
+		Sections: quantized,
+		Filter: cascadePolynomial(quantized),
+		Poles: poles,
+		QuantizedPoles: quantizedPoles,
+		PoleMovement: poleMovement(poles, quantizedPoles),
+		Deviation: responseDeviation(original, stages),
+		Stable: stableSections(quantized),
+		Overflow: overflow,
+
+	}
+
+}
+
+func quantizePolynomial(filter Polynomial, format Format, rounding ...Rounding) Quantization {
+
+	mode := Nearest
+
+	if ((len(rounding) > 0) &&
+		rounding[0].exists()) {
+
+		mode = rounding[0]
+
+	}
+
+	numerator, denominator := filter.digitalCoefficients()
+	quantizedNumerator, quantizedDenominator, overflow := quantizeTransfer(numerator, denominator, format, mode)
+	quantized := digitalPolynomial(quantizedNumerator, quantizedDenominator)
+	poles := polynomialRoots(denominator)
+	quantizedPoles := polynomialRoots(quantizedDenominator)
+
+	return Quantization{
+
+		Format: format,
+		Rounding: mode,
+		Filter: quantized,
+		Poles: poles,
+		QuantizedPoles: quantizedPoles,
+		PoleMovement: poleMovement(poles, quantizedPoles),
+		Deviation: responseDeviation([]Polynomial{ filter }, []Polynomial{ quantized }),
+		Stable: stablePolynomial(quantized),
+		Overflow: overflow,
+
+	}
+
+}
+
+func impulseResponse(sections []Section, length int) []float64 {
+
+	processor := newCascadeProcessor(sections)
+	response := make([]float64, length)
+
+	for index := range response {
+
+		x := 0.0
+
+		if (index == 0) {
+
+			x = 1.0
+
+		}
+
+		response[index] = processor.Process(x)
+
+	}
+
+	return response
+
+}
+
+func sectionNorm(sections []Section, norm Norm) float64 {
+
+	switch norm {
+
+		case L1, L2:
+
+			total := 0.0
+
+			for _, sample := range impulseResponse(sections, 8192) {
+
+				if (norm == L1) {
+
+					total += math.Abs(sample)
+
+				} else {
+
+					total += math.Pow(sample, 2)
+
+				}
+
+			}
+
+			if (norm == L2) {
+
+				total = math.Sqrt(total)
+
+			}
+
+			return total
+
+		default:
+
+			peak := 0.0
+			stages := []Polynomial{}
+
+			for _, section := range sections {
+
+				stages = append(stages, section.polynomial())
+
+			}
+
+			for _, frequency := range linearSpace(0.0, 0.5, 1024) {
+
+				h := complex(1, 0)
+
+				for _, stage := range stages {
+
+					h *= frequencyResponse(stage, frequency, 1.0)
+
+				}
+
+				peak = math.Max(peak, cmplx.Abs(h))
+
+			}
+
+			return peak
+
+	}
+
+}
+
+func scaleSections(sections []Section, norm ...Norm) ([]Section, float64) {
+
+	measure := LInfinity
+
+	if ((len(norm) > 0) &&
+		norm[0].exists()) {
+
+		measure = norm[0]
+
+	}
+
+	scaled := append([]Section{}, sections...)
+	previous := 1.0
+
+	for index := range scaled {
+
+		size := sectionNorm(sections[:index + 1], measure)
+
+		if (size == 0.0) {
+
+			continue
+
+		}
+
+		factor := 1.0 / size
+
+		for k := range scaled[index].Numerator {
+
+			scaled[index].Numerator[k] *= factor / previous
+
+		}
+
+		previous = factor
+
+	}
+
+	return scaled, 1.0 / previous
+
+}
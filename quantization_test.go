@@ -0,0 +1,141 @@
+package main
+
+import ( "math"
+		 "math/cmplx"
+		 "testing" )
+
+
+func TestFormatQuantize(t *testing.T) {
+
+	q15 := Format{ Integer: 0, Fraction: 15 }
+
+	tests := []struct {
+
+		value	 float64
+		rounding Rounding
+		want	 int64
+		overflow bool
+
+	}{
+
+		{ 0.5, Nearest, 16384, false },
+		{ -1.0, Nearest, -32768, false },
+		{ 1.0, Nearest, 32767, true },
+		{ -2.0, Truncate, -32768, true },
+		{ 2.5/32768.0, Nearest, 3, false },
+		{ 2.5/32768.0, Convergent, 2, false },
+		{ -2.5/32768.0, Truncate, -3, false },
+		{ -2.5/32768.0, TowardZero, -2, false },
+
+	}
+
+	for _, test := range tests {
+
+		got, overflow := q15.quantize(test.value, test.rounding)
+
+		if ((got != test.want) || (overflow != test.overflow)) {
+
+			t.Errorf("quantize(%g, %s) = %d, %t, want %d, %t", test.value, test.rounding, got, overflow, test.want, test.overflow)
+
+		}
+
+	}
+
+	if ((q15.bits() != 16) || (q15.value(16384) != 0.5)) {
+
+		t.Errorf("Q0.15 has %d bits and maps 16384 to %g", q15.bits(), q15.value(16384))
+
+	}
+
+}
+
+func TestQuantizeSections(t *testing.T) {
+
+	filter := digitalPolynomial(equalizerNumerator, equalizerDenominator)
+	sections := filter.sections()
+	fine := quantizeSections(sections, Format{ Integer: 1, Fraction: 14 })
+	coarse := quantizeSections(sections, Format{ Integer: 1, Fraction: 6 })
+
+	if (!fine.Stable || !coarse.Stable) {
+
+		t.Errorf("quantized sections became unstable")
+
+	}
+
+	if ((fine.PoleMovement > 1e-4) || (fine.PoleMovement >= coarse.PoleMovement)) {
+
+		t.Errorf("pole movement = %g with 14 fraction bits and %g with 6", fine.PoleMovement, coarse.PoleMovement)
+
+	}
+
+	if ((fine.Deviation > 0.1) || (fine.Deviation >= coarse.Deviation)) {
+
+		t.Errorf("response deviation = %g dB with 14 fraction bits and %g dB with 6", fine.Deviation, coarse.Deviation)
+
+	}
+
+	if (fine.Rounding != Nearest) {
+
+		t.Errorf("default rounding is %s, want %s", fine.Rounding, Nearest)
+
+	}
+
+}
+
+func TestQuantizePolynomial(t *testing.T) {
+
+	filter := digitalPolynomial(equalizerNumerator, equalizerDenominator)
+
+	// the direct form feedback coefficients reach -2.37 and saturate a Q0.15 word
+	if q := quantizePolynomial(filter, Format{ Integer: 0, Fraction: 15 }); !q.Overflow {
+
+		t.Errorf("Q0.15 direct form did not report an overflow")
+
+	}
+
+	q := quantizePolynomial(filter, Format{ Integer: 2, Fraction: 13 })
+
+	if (q.Overflow || !q.Stable) {
+
+		t.Errorf("Q2.13 direct form overflow %t, stable %t", q.Overflow, q.Stable)
+
+	}
+
+	if ((len(q.Poles) != 4) || (len(q.QuantizedPoles) != 4)) {
+
+		t.Errorf("got %d and %d poles, want 4", len(q.Poles), len(q.QuantizedPoles))
+
+	}
+
+}
+
+func TestScaleSections(t *testing.T) {
+
+	filter := digitalPolynomial(equalizerNumerator, equalizerDenominator)
+	sections := filter.sections()
+	scaled, gain := scaleSections(sections)
+
+	// every partial cascade peaks at unity and the gain restores the overall response
+	for index := range scaled {
+
+		if peak := sectionNorm(scaled[:index + 1], LInfinity); (math.Abs(peak - 1.0) > 1e-9) {
+
+			t.Errorf("peak after section %d = %g, want 1", index, peak)
+
+		}
+
+	}
+
+	for _, frequency := range []float64{ 0.0, 80.0, 200.0 } {
+
+		want := frequencyResponse(filter, frequency, 1000.0)
+
+		if got := frequencyResponse(cascadePolynomial(scaled), frequency, 1000.0)*complex(gain, 0); (cmplx.Abs(got - want) > 1e-6) {
+
+			t.Errorf("scaled response at %g Hz = %v, want %v", frequency, got, want)
+
+		}
+
+	}
+
+}
@@ -0,0 +1,716 @@
+package main
+
+import ( "bytes"
+		 "encoding/binary"
+		 "fmt"
+		 "math"
+		 "math/cmplx"
+		 "strconv"
+		 "strings" )
+
+
+type Plot struct {
+
+	Title	  string
+	Name	  string
+	Complex	  bool
+	Variables []string
+	Values	  [][]complex128
+
+}
+
+type Overlay struct {
+
+	Frequency []float64
+	Simulated []float64
+	Ideal	  []float64
+	Error	  float64
+	Phase	  float64
+
+}
+
+type spiceDeck struct {
+
+	builder	   strings.Builder
+	resistors  int
+	capacitors int
+	inductors  int
+	amplifiers int
+	amplifier  string
+
+}
+
+func spiceValue(value float64) string {
+
+	if ((value == 0.0) || math.IsInf(value, 0) || math.IsNaN(value)) {
+
+		return strconv.FormatFloat(value, 'g', -1, 64)
+
+	}
+
+	suffixes := []string{ "f", "p", "n", "u", "m", "", "k", "meg", "g", "t" }
+	exponent := int(math.Floor(math.Log10(math.Abs(value)) / 3.0))
+	exponent = min(max(exponent, -5), 4)
+	mantissa := value / math.Pow(1000, float64(exponent))
+	return strconv.FormatFloat(mantissa, 'g', 6, 64) + suffixes[exponent + 5]
+
+}
+
+func (d *spiceDeck) resistor(a string, b string, value float64) {
+
+	d.resistors++
+	fmt.Fprintf(&d.builder, "R%d %s %s %s\n", d.resistors, a, b, spiceValue(value))
+
+}
+
+func (d *spiceDeck) capacitor(a string, b string, value float64) {
+
+	d.capacitors++
+	fmt.Fprintf(&d.builder, "C%d %s %s %s\n", d.capacitors, a, b, spiceValue(value))
+
+}
+
+func (d *spiceDeck) inductor(a string, b string, value float64) {
+
+	d.inductors++
+	fmt.Fprintf(&d.builder, "L%d %s %s %s\n", d.inductors, a, b, spiceValue(value))
+
+}
+
+func (d *spiceDeck) opamp(plus string, minus string, output string) {
+
+	d.amplifiers++
+	fmt.Fprintf(&d.builder, "X%d %s %s %s %s\n", d.amplifiers, plus, minus, output, d.amplifier)
+
+}
+
+func parseSimulation(config Simulation, low float64, high float64) (string, float64, float64, int, float64, float64) {
+
+	if ((low <= 0.0) || (high <= 0.0)) {
+
+		low, high = 1000.0, 1000.0
+
+	}
+
+	start := low / 100.0
+
+	if ((config.Start != nil) && (*config.Start > 0.0)) {
+
+		start = *config.Start
+
+	}
+
+	stop := 100.0*high
+
+	if ((config.Stop != nil) && (*config.Stop > start)) {
+
+		stop = *config.Stop
+
+	}
+
+	points := config.Points
+
+	if (points <= 0) {
+
+		points = 100
+
+	}
+
+	step := 1.0 / (100.0*high)
+
+	if ((config.Step != nil) && (*config.Step > 0.0)) {
+
+		step = *config.Step
+
+	}
+
+	duration := 20.0 / low
+
+	if ((config.Duration != nil) && (*config.Duration > step)) {
+
+		duration = *config.Duration
+
+	}
+
+	return identifier(config.Name), start, stop, points, step, duration
+
+}
+
+func spiceHeader(deck *spiceDeck, config Simulation, name string, amplitude float64, step float64, duration float64) {
+
+	fmt.Fprintf(&deck.builder, "* %s\n", name)
+	deck.amplifier = "opamp"
+
+	if (config.Amplifier != "") {
+
+		deck.amplifier = identifier(config.Amplifier)
+
+	}
+
+	if (config.Include != "") {
+
+		fmt.Fprintf(&deck.builder, ".include %s\n", config.Include)
+
+	}
+
+	fmt.Fprintf(&deck.builder, "V1 in 0 DC 0 AC %s PULSE(0 %s 0 %s %s %s %s)\n",
+				spiceValue(amplitude), spiceValue(amplitude), spiceValue(step), spiceValue(step),
+				spiceValue(duration), spiceValue(2.0*duration))
+
+}
+
+func spiceFooter(deck *spiceDeck, config Simulation, name string, start float64, stop float64, points int, step float64, duration float64, gainBandwidth float64) {
+
+	if ((deck.amplifiers > 0) && (config.Include == "")) {
+
+		fmt.Fprintf(&deck.builder, "\n* placeholder: replace with the vendor model, pins non-inverting inverting output\n")
+		fmt.Fprintf(&deck.builder, ".subckt %s inp inn out\n", deck.amplifier)
+
+		if (gainBandwidth > 0.0) {
+
+			fmt.Fprintf(&deck.builder, "G1 0 pole inp inn 1\nR1 pole 0 100k\nC1 pole 0 %s\nE1 out 0 pole 0 1\n",
+						spiceValue(1.0 / (2.0*math.Pi*gainBandwidth)))
+
+		} else {
+
+			fmt.Fprintf(&deck.builder, "E1 out 0 inp inn 1meg\n")
+
+		}
+
+		fmt.Fprintf(&deck.builder, ".ends %s\n", deck.amplifier)
+
+	}
+
+	fmt.Fprintf(&deck.builder, "\n.ac dec %d %s %s\n", points, spiceValue(start), spiceValue(stop))
+	fmt.Fprintf(&deck.builder, ".tran %s %s\n", spiceValue(step), spiceValue(duration))
+	fmt.Fprintf(&deck.builder, "\n.control\nset filetype=ascii\nrun\n")
+	fmt.Fprintf(&deck.builder, "write %s.raw ac1.v(in) ac1.v(out) tran1.v(in) tran1.v(out)\n", name)
+	fmt.Fprintf(&deck.builder, ".endc\n\n.end\n")
+
+}
+
+func (a ActiveFilter) netlist(config Simulation) string {
+
+	low, high := math.Inf(1), 0.0
+
+	for _, stage := range a.Stages {
+
+		low = math.Min(low, stage.Frequency)
+		high = math.Max(high, stage.Frequency)
+
+	}
+
+	name, start, stop, points, step, duration := parseSimulation(config, low, high)
+	deck := &spiceDeck{}
+	spiceHeader(deck, config, name, 1.0, step, duration)
+	input := "in"
+
+	for index, stage := range a.Stages {
+
+		node := func(label string) string {
+
+			return fmt.Sprintf("s%d_%s", index + 1, label)
+
+		}
+
+		output := node("out")
+
+		if (index == len(a.Stages) - 1) {
+
+			output = "out"
+
+		}
+
+		r, c := stage.Resistors, stage.Capacitors
+		fmt.Fprintf(&deck.builder, "\n* stage %d: %s %s, f0 = %s, Q = %.4g\n",
+					index + 1, stage.Topology, stage.Response, spiceValue(stage.Frequency), stage.Q)
+
+		switch stage.Topology {
+
+			case FirstOrder:
+
+				if (stage.Response == HPF) {
+
+					deck.capacitor(input, node("a"), c[0])
+					deck.resistor(node("a"), "0", r[0])
+
+				} else {
+
+					deck.resistor(input, node("a"), r[0])
+					deck.capacitor(node("a"), "0", c[0])
+
+				}
+
+				deck.opamp(node("a"), output, output)
+
+			case SallenKeyUnity, SallenKeyEqual:
+
+				if (stage.Response == HPF) {
+
+					deck.capacitor(input, node("a"), c[0])
+					deck.capacitor(node("a"), node("b"), c[1])
+					deck.resistor(node("a"), output, r[0])
+					deck.resistor(node("b"), "0", r[1])
+
+					if (len(c) > 2) {
+
+						deck.capacitor(node("a"), "0", c[2])
+
+					}
+
+				} else {
+
+					deck.resistor(input, node("a"), r[0])
+					deck.resistor(node("a"), node("b"), r[1])
+					deck.capacitor(node("a"), output, c[0])
+					deck.capacitor(node("b"), "0", c[1])
+
+					if (len(r) > 4) {
+
+						deck.resistor(node("a"), "0", r[4])
+
+					}
+
+				}
+
+				if (stage.Topology == SallenKeyEqual) {
+
+					deck.resistor(node("f"), "0", r[2])
+					deck.resistor(node("f"), output, r[3])
+					deck.opamp(node("b"), node("f"), output)
+
+				} else {
+
+					deck.opamp(node("b"), output, output)
+
+				}
+
+			case MultipleFeedback:
+
+				switch stage.Response {
+
+					case HPF:
+
+						deck.capacitor(input, node("a"), c[0])
+						deck.capacitor(node("a"), output, c[1])
+						deck.capacitor(node("a"), node("m"), c[2])
+						deck.resistor(node("a"), "0", r[0])
+						deck.resistor(node("m"), output, r[1])
+
+					case BPF:
+
+						deck.resistor(input, node("a"), r[0])
+						deck.resistor(node("a"), "0", r[1])
+						deck.capacitor(node("a"), output, c[0])
+						deck.capacitor(node("a"), node("m"), c[1])
+						deck.resistor(node("m"), output, r[2])
+
+					default:
+
+						deck.resistor(input, node("a"), r[0])
+						deck.resistor(node("a"), output, r[1])
+						deck.resistor(node("a"), node("m"), r[2])
+						deck.capacitor(node("m"), output, c[0])
+						deck.capacitor(node("a"), "0", c[1])
+
+				}
+
+				deck.opamp("0", node("m"), output)
+
+			case TowThomas:
+
+				bandPass, lowPass := node("bp"), output
+
+				if (stage.Response == BPF) {
+
+					bandPass, lowPass = output, node("lp")
+
+				}
+
+				deck.resistor(input, node("m1"), r[0])
+				deck.resistor(node("m1"), bandPass, r[1])
+				deck.capacitor(node("m1"), bandPass, c[0])
+				deck.resistor(node("inv"), node("m1"), r[2])
+				deck.opamp("0", node("m1"), bandPass)
+				deck.resistor(bandPass, node("m2"), r[3])
+				deck.capacitor(node("m2"), lowPass, c[1])
+				deck.opamp("0", node("m2"), lowPass)
+				deck.resistor(lowPass, node("m3"), r[4])
+				deck.resistor(node("m3"), node("inv"), r[5])
+				deck.opamp("0", node("m3"), node("inv"))
+
+			case StateVariable:
+
+				highPass, bandPass, lowPass := node("hp"), node("bp"), node("lp")
+
+				switch stage.Response {
+
+					case HPF:
+
+						highPass = output
+
+					case BPF:
+
+						bandPass = output
+
+					case LPF:
+
+						lowPass = output
+
+				}
+
+				deck.resistor(input, node("m1"), r[0])
+				deck.resistor(lowPass, node("m1"), r[1])
+				deck.resistor(node("m1"), highPass, r[2])
+				deck.resistor(bandPass, node("p1"), r[3])
+				deck.resistor(node("p1"), "0", r[4])
+				deck.opamp(node("p1"), node("m1"), highPass)
+				deck.resistor(highPass, node("m2"), r[5])
+				deck.capacitor(node("m2"), bandPass, c[0])
+				deck.opamp("0", node("m2"), bandPass)
+				deck.resistor(bandPass, node("m3"), r[6])
+				deck.capacitor(node("m3"), lowPass, c[1])
+				deck.opamp("0", node("m3"), lowPass)
+
+				if (stage.Response == Notch) {
+
+					deck.resistor(node("m4"), output, r[7])
+					deck.resistor(highPass, node("m4"), r[8])
+					deck.resistor(lowPass, node("m4"), r[9])
+					deck.opamp("0", node("m4"), output)
+
+				}
+
+		}
+
+		input = output
+
+	}
+
+	spiceFooter(deck, config, name, start, stop, points, step, duration, a.GainBandwidth)
+	return deck.builder.String()
+
+}
+
+func (l Ladder) netlist(config Simulation) string {
+
+	low, high := l.band()
+	name, start, stop, points, step, duration := parseSimulation(config, low, high)
+	deck := &spiceDeck{}
+	spiceHeader(deck, config, name, 2.0*math.Sqrt(l.Source / l.Load), step, duration)
+	remaining := 0
+
+	for _, element := range l.Elements {
+
+		if (!element.Shunt && ((element.Inductance > 0.0) || (element.Capacitance > 0.0))) {
+
+			remaining++
+
+		}
+
+	}
+
+	nodes := 1
+	current := "out"
+
+	if (remaining > 0) {
+
+		current = "n1"
+
+	}
+
+	deck.resistor("in", current, l.Source)
+
+	for _, element := range l.Elements {
+
+		if ((element.Inductance <= 0.0) && (element.Capacitance <= 0.0)) {
+
+			continue
+
+		}
+
+		next := "0"
+
+		if (!element.Shunt) {
+
+			remaining--
+			nodes++
+			next = fmt.Sprintf("n%d", nodes)
+
+			if (remaining == 0) {
+
+				next = "out"
+
+			}
+
+		}
+
+		switch {
+
+			case (element.Capacitance <= 0.0):
+
+				deck.inductor(current, next, element.Inductance)
+
+			case (element.Inductance <= 0.0):
+
+				deck.capacitor(current, next, element.Capacitance)
+
+			case element.Parallel:
+
+				deck.inductor(current, next, element.Inductance)
+				deck.capacitor(current, next, element.Capacitance)
+
+			default:
+
+				nodes++
+				middle := fmt.Sprintf("n%d", nodes)
+				deck.inductor(current, middle, element.Inductance)
+				deck.capacitor(middle, next, element.Capacitance)
+
+		}
+
+		if (!element.Shunt) {
+
+			current = next
+
+		}
+
+	}
+
+	deck.resistor("out", "0", l.Load)
+	spiceFooter(deck, config, name, start, stop, points, step, duration, 0.0)
+	return deck.builder.String()
+
+}
+
+func parseRawValue(token string) (complex128, error) {
+
+	left, right, found := strings.Cut(token, ",")
+	x, err := strconv.ParseFloat(strings.TrimSpace(left), 64)
+
+	if (err != nil) {
+
+		return 0, fmt.Errorf("invalid raw value %q", token)
+
+	}
+
+	if !found {
+
+		return complex(x, 0), nil
+
+	}
+
+	y, err := strconv.ParseFloat(strings.TrimSpace(right), 64)
+
+	if (err != nil) {
+
+		return 0, fmt.Errorf("invalid raw value %q", token)
+
+	}
+
+	return complex(x, y), nil
+
+}
+
+func parseRaw(data []byte) ([]Plot, error) {
+
+	plots := []Plot{}
+	plot := Plot{ Variables: []string{} }
+	points := 0
+
+	for (len(data) > 0) {
+
+		line, rest, _ := bytes.Cut(data, []byte("\n"))
+		data = rest
+		key, value, _ := strings.Cut(strings.TrimRight(string(line), "\r"), ":")
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+
+			case "title":
+
+				plot = Plot{ Title: value, Variables: []string{} }
+				points = 0
+
+			case "plotname":
+
+				plot.Name = value
+
+			case "flags":
+
+				plot.Complex = strings.Contains(strings.ToLower(value), "complex")
+
+			case "no. points":
+
+				count, err := strconv.Atoi(value)
+
+				if ((err != nil) || (count < 0)) {
+
+					return plots, fmt.Errorf("invalid point count %q", value)
+
+				}
+
+				points = count
+
+			case "no. variables":
+
+				count, err := strconv.Atoi(value)
+
+				if ((err != nil) || (count < 0)) {
+
+					return plots, fmt.Errorf("invalid variable count %q", value)
+
+				}
+
+				plot.Variables = make([]string, 0, count)
+
+			case "variables":
+
+				for index := 0; index < cap(plot.Variables); index++ {
+
+					line, data, _ = bytes.Cut(data, []byte("\n"))
+					fields := strings.Fields(string(line))
+
+					if (len(fields) > 1) {
+
+						plot.Variables = append(plot.Variables, fields[1])
+
+					}
+
+				}
+
+			case "values":
+
+				plot.Values = make([][]complex128, len(plot.Variables))
+				tokens := []string{}
+				expected := points*(len(plot.Variables) + 1)
+
+				for ((len(tokens) < expected) && (len(data) > 0)) {
+
+					line, data, _ = bytes.Cut(data, []byte("\n"))
+					tokens = append(tokens, strings.Fields(string(line))...)
+
+				}
+
+				for index := 0; index + len(plot.Variables) < len(tokens); index += len(plot.Variables) + 1 {
+
+					for variable := range plot.Variables {
+
+						value, err := parseRawValue(tokens[index + variable + 1])
+
+						if (err != nil) {
+
+							return plots, err
+
+						}
+
+						plot.Values[variable] = append(plot.Values[variable], value)
+
+					}
+
+				}
+
+				plots = append(plots, plot)
+
+			case "binary":
+
+				width := 8
+
+				if plot.Complex {
+
+					width = 16
+
+				}
+
+				record := len(plot.Variables)*width
+				plot.Values = make([][]complex128, len(plot.Variables))
+				size := min(points*record, len(data) - len(data)%max(record, 1))
+
+				for offset := 0; offset < size; offset += width {
+
+					variable := (offset / width)%len(plot.Variables)
+					x := math.Float64frombits(binary.LittleEndian.Uint64(data[offset:]))
+					y := 0.0
+
+					if plot.Complex {
+
+						y = math.Float64frombits(binary.LittleEndian.Uint64(data[offset + 8:]))
+
+					}
+
+					plot.Values[variable] = append(plot.Values[variable], complex(x, y))
+
+				}
+
+				data = data[size:]
+				plots = append(plots, plot)
+
+		}
+
+	}
+
+	return plots, nil
+
+}
+
+func (p Plot) vector(name string) []complex128 {
+
+	for index, variable := range p.Variables {
+
+		variable = strings.ToLower(variable)
+
+		if ((variable == strings.ToLower(name)) || (variable == "v(" + strings.ToLower(name) + ")")) {
+
+			return p.Values[index]
+
+		}
+
+	}
+
+	return []complex128{}
+
+}
+
+func overlayResponses(frequencies []float64, measured []complex128, ideal func(float64) complex128) Overlay {
+
+	overlay := Overlay{ Frequency: []float64{}, Simulated: []float64{}, Ideal: []float64{} }
+
+	for index, value := range measured[:min(len(measured), len(frequencies))] {
+
+		expected := ideal(frequencies[index])
+		overlay.Frequency = append(overlay.Frequency, frequencies[index])
+		overlay.Simulated = append(overlay.Simulated, decibels(cmplx.Abs(value)))
+		overlay.Ideal = append(overlay.Ideal, decibels(cmplx.Abs(expected)))
+
+		if ((cmplx.Abs(value) > 1e-6) && (cmplx.Abs(expected) > 1e-6)) {
+
+			overlay.Error = math.Max(overlay.Error, math.Abs(overlay.Simulated[index] - overlay.Ideal[index]))
+			overlay.Phase = math.Max(overlay.Phase, math.Abs(cmplx.Phase(value / expected))*180.0 / math.Pi)
+
+		}
+
+	}
+
+	return overlay
+
+}
+
+func overlaySimulation(plot Plot, node string, ideal func(float64) complex128) Overlay {
+
+	if (len(plot.Values) == 0) {
+
+		return overlayResponses([]float64{}, []complex128{}, ideal)
+
+	}
+
+	frequencies := make([]float64, len(plot.Values[0]))
+
+	for index, value := range plot.Values[0] {
+
+		frequencies[index] = real(value)
+
+	}
+
+	return overlayResponses(frequencies, plot.vector(node), ideal)
+
+}
@@ -0,0 +1,339 @@
+package main
+
+import ( "bytes"
+		 "encoding/binary"
+		 "fmt"
+		 "math"
+		 "math/cmplx"
+		 "strconv"
+		 "strings"
+		 "testing" )
+
+
+func parseSpiceValue(token string) float64 {
+
+	multipliers := []struct{ suffix string; scale float64 }{ { "meg", 1e6 }, { "f", 1e-15 }, { "p", 1e-12 }, { "n", 1e-9 }, { "u", 1e-6 }, { "m", 1e-3 }, { "k", 1e3 }, { "g", 1e9 }, { "t", 1e12 } }
+
+	for _, multiplier := range multipliers {
+
+		if mantissa, found := strings.CutSuffix(token, multiplier.suffix); found {
+
+			value, _ := strconv.ParseFloat(mantissa, 64)
+			return value*multiplier.scale
+
+		}
+
+	}
+
+	value, _ := strconv.ParseFloat(token, 64)
+	return value
+
+}
+
+// modified nodal analysis of a generated deck at one frequency, with ideal op-amps as nullors
+func nodalResponse(deck string, frequency float64) complex128 {
+
+	type part struct{ kind byte; a, b, c int; value float64 }
+
+	nodes := map[string]int{ "0": 0 }
+	node := func(name string) int {
+
+		if _, exists := nodes[name]; !exists {
+
+			nodes[name] = len(nodes)
+
+		}
+
+		return nodes[name]
+
+	}
+
+	parts := []part{}
+	subcircuit := false
+
+	for _, line := range strings.Split(deck, "\n") {
+
+		fields := strings.Fields(line)
+
+		if ((len(fields) == 0) || (fields[0][0] == '*')) {
+
+			continue
+
+		}
+
+		switch {
+
+			case (fields[0] == ".subckt"):
+
+				subcircuit = true
+
+			case (fields[0] == ".ends"):
+
+				subcircuit = false
+
+			case (subcircuit || (fields[0][0] == '.')):
+
+				continue
+
+			case strings.ContainsRune("RCL", rune(fields[0][0])):
+
+				parts = append(parts, part{ kind: fields[0][0], a: node(fields[1]), b: node(fields[2]), value: parseSpiceValue(fields[3]) })
+
+			case (fields[0][0] == 'V'):
+
+				parts = append(parts, part{ kind: 'V', a: node(fields[1]), value: parseSpiceValue(fields[6]) })
+
+			case (fields[0][0] == 'X'):
+
+				parts = append(parts, part{ kind: 'X', a: node(fields[3]), b: node(fields[1]), c: node(fields[2]) })
+
+		}
+
+	}
+
+	size := len(nodes) - 1
+
+	for _, p := range parts {
+
+		if ((p.kind == 'V') || (p.kind == 'X')) {
+
+			size++
+
+		}
+
+	}
+
+	system := make([][]complex128, size)
+	input := make([]complex128, size)
+
+	for row := range system {
+
+		system[row] = make([]complex128, size)
+
+	}
+
+	stamp := func(i int, j int, admittance complex128) {
+
+		if (i > 0) {
+
+			system[i - 1][i - 1] += admittance
+
+		}
+
+		if (j > 0) {
+
+			system[j - 1][j - 1] += admittance
+
+		}
+
+		if ((i > 0) && (j > 0)) {
+
+			system[i - 1][j - 1] -= admittance
+			system[j - 1][i - 1] -= admittance
+
+		}
+
+	}
+
+	s := complex(0, 2.0*math.Pi*frequency)
+	branch := len(nodes) - 1
+
+	for _, p := range parts {
+
+		switch p.kind {
+
+			case 'R':
+
+				stamp(p.a, p.b, complex(1.0 / p.value, 0))
+
+			case 'C':
+
+				stamp(p.a, p.b, s*complex(p.value, 0))
+
+			case 'L':
+
+				stamp(p.a, p.b, 1.0 / (s*complex(p.value, 0)))
+
+			case 'V':
+
+				system[p.a - 1][branch] += 1
+				system[branch][p.a - 1] = 1
+				input[branch] = complex(p.value, 0)
+				branch++
+
+			case 'X':
+
+				// the output supplies whatever current holds the inputs at equal voltages
+				system[p.a - 1][branch] += 1
+
+				if (p.b > 0) {
+
+					system[branch][p.b - 1] += 1
+
+				}
+
+				if (p.c > 0) {
+
+					system[branch][p.c - 1] -= 1
+
+				}
+
+				branch++
+
+		}
+
+	}
+
+	return solveComplexLinear(system, input)[nodes["out"] - 1]
+
+}
+
+func TestSpiceValue(t *testing.T) {
+
+	for value, want := range map[float64]string{ 10000.0: "10k", 4.7e-9: "4.7n", 1.5e6: "1.5meg", 0.033: "33m", 1.0: "1", 22e-12: "22p", 0.0: "0" } {
+
+		if got := spiceValue(value); (got != want) {
+
+			t.Errorf("spiceValue(%g) = %q, want %q", value, got, want)
+
+		}
+
+	}
+
+}
+
+func TestNetlists(t *testing.T) {
+
+	// component values are written with six significant digits
+	check := func(label string, deck string, ideal func(float64) complex128) {
+
+		for _, frequency := range []float64{ 100.0, 900.0, 1000.0, 8000.0, 10000.0, 12500.0, 50000.0 } {
+
+			got, want := nodalResponse(deck, frequency), ideal(frequency)
+
+			if (cmplx.Abs(got - want) > 2e-4*cmplx.Abs(want) + 1e-6) {
+
+				t.Errorf("%s at %g Hz: netlist gives %v, design gives %v", label, frequency, got, want)
+
+			}
+
+		}
+
+	}
+
+	for _, topology := range []Topology{ SallenKeyUnity, SallenKeyEqual, MultipleFeedback, StateVariable, TowThomas } {
+
+		for _, response := range []Response{ LPF, HPF, BPF, BSF } {
+
+			filter, _ := designActive(Specs{ Response: response, Approximation: Chebyshev, Order: pointer(uint16(5)), PassbandAttenuation: pointer(1.0), Topology: topology, CutoffFrequency: pointer(1000.0), CenterFrequency: pointer(10000.0), Bandwidth: pointer(2000.0) })
+			check(fmt.Sprintf("%s %s", topology, response), filter.netlist(Simulation{ Name: "active" }), filter.response)
+
+		}
+
+	}
+
+	for _, response := range []Response{ LPF, HPF, BPF, BSF } {
+
+		ladder, _ := designPassive(Specs{ Response: response, Approximation: Chebyshev, Order: pointer(uint16(5)), PassbandAttenuation: pointer(1.0), CutoffFrequency: pointer(1000.0), CenterFrequency: pointer(10000.0), Bandwidth: pointer(2000.0), LoadImpedance: pointer(100.0) })
+		check(fmt.Sprintf("ladder %s", response), ladder.netlist(Simulation{}), ladder.response)
+
+	}
+
+	// a finite gain-bandwidth turns the placeholder into a single pole model
+	filter, _ := designActive(Specs{ Order: pointer(uint16(2)), GainBandwidth: pointer(1e6) })
+	deck := filter.netlist(Simulation{ Name: "model" })
+
+	for _, line := range []string{ ".subckt opamp inp inn out", "C1 pole 0 159.155n", "E1 out 0 pole 0 1", ".ac dec 100 10 100k", "write model.raw" } {
+
+		if (!strings.Contains(deck, line)) {
+
+			t.Errorf("deck does not contain %q", line)
+
+		}
+
+	}
+
+}
+
+func TestParseRaw(t *testing.T) {
+
+	frequencies := []float64{ 100.0, 1000.0, 10000.0 }
+	ladder, _ := designPassive(Specs{ Order: pointer(uint16(3)), CutoffFrequency: pointer(1000.0) })
+	ascii := strings.Builder{}
+	fmt.Fprintf(&ascii, "Title: ladder\nDate: today\nPlotname: AC Analysis\nFlags: complex\nNo. Variables: 2\nNo. Points: 3\nVariables:\n\t0\tfrequency\tfrequency grid=3\n\t1\tv(out)\tvoltage\nValues:\n")
+	binaryRaw := bytes.Buffer{}
+	fmt.Fprintf(&binaryRaw, "Title: ladder\nPlotname: AC Analysis\nFlags: complex\nNo. Variables: 2\nNo. Points: 3\nVariables:\n\t0\tfrequency\tfrequency\n\t1\tv(out)\tvoltage\nBinary:\n")
+
+	for index, frequency := range frequencies {
+
+		value := ladder.response(frequency)
+		fmt.Fprintf(&ascii, " %d\t%.15e,0.0\n\t%.15e,%.15e\n\n", index, frequency, real(value), imag(value))
+
+		for _, number := range []float64{ frequency, 0.0, real(value), imag(value) } {
+
+			binary.Write(&binaryRaw, binary.LittleEndian, number)
+
+		}
+
+	}
+
+	ascii.WriteString("Title: ladder\nPlotname: Transient Analysis\nFlags: real\nNo. Variables: 2\nNo. Points: 2\nVariables:\n\t0\ttime\ttime\n\t1\tv(out)\tvoltage\nValues:\n 0\t0\n\t0\n 1\t1e-3\n\t0.5\n")
+
+	for label, data := range map[string][]byte{ "ascii": []byte(ascii.String()), "binary": binaryRaw.Bytes() } {
+
+		plots, err := parseRaw(data)
+
+		if ((err != nil) || (len(plots) == 0)) {
+
+			t.Fatalf("%s: got %d plots and %v", label, len(plots), err)
+
+		}
+
+		if ((plots[0].Name != "AC Analysis") || !plots[0].Complex || (len(plots[0].vector("out")) != 3)) {
+
+			t.Errorf("%s: first plot is %+v", label, plots[0])
+
+		}
+
+		if overlay := overlaySimulation(plots[0], "v(out)", ladder.response); ((len(overlay.Frequency) != 3) || (overlay.Error > 1e-9) || (overlay.Phase > 1e-9)) {
+
+			t.Errorf("%s: overlay of the ideal response against itself is %+v", label, overlay)
+
+		}
+
+	}
+
+	if plots, _ := parseRaw([]byte(ascii.String())); ((len(plots) != 2) || (plots[1].Complex) || (real(plots[1].vector("out")[1]) != 0.5)) {
+
+		t.Errorf("transient plot is %+v", plots[len(plots) - 1])
+
+	}
+
+}
+
+func TestParseRawErrors(t *testing.T) {
+
+	tests := []struct {
+
+		data	string
+		message string
+
+	}{
+
+		{ "Title: t\nPlotname: AC\nFlags: complex\nNo. Variables: 2\nNo. Points: 2\nVariables:\n\t0\tfrequency\tfrequency\n\t1\tv(out)\tvoltage\nValues:\n0\t1,0\n\t0.5,0.1\n1\t2,0\n\t0.4,x\n", "invalid raw value \"0.4,x\"" },
+		{ "Title: t\nNo. Variables: two\n", "invalid variable count \"two\"" },
+		{ "Title: t\nNo. Points: many\n", "invalid point count \"many\"" },
+
+	}
+
+	for _, test := range tests {
+
+		if _, err := parseRaw([]byte(test.data)); ((err == nil) || (err.Error() != test.message)) {
+
+			t.Errorf("got %v, want %q", err, test.message)
+
+		}
+
+	}
+
+}
@@ -0,0 +1,669 @@
+package main
+
+import ( "errors"
+		 "fmt"
+		 "math"
+		 "math/cmplx"
+		 "slices" )
+
+
+type StateSpace struct {
+
+	A			[][]float64
+	B			[]float64
+	C			[]float64
+	D			float64
+	Domain		Domain
+	Realization Realization
+
+}
+
+type ZeroPoleGain struct {
+
+	Zeros  []complex128
+	Poles  []complex128
+	Gain   float64
+	Domain Domain
+
+}
+
+func expandRoots(roots []complex128) []float64 {
+
+	coefficients := []complex128{ 1 }
+
+	for _, root := range roots {
+
+		next := make([]complex128, len(coefficients) + 1)
+
+		for index, coefficient := range coefficients {
+
+			next[index] += coefficient
+			next[index + 1] -= root*coefficient
+
+		}
+
+		coefficients = next
+
+	}
+
+	expanded := make([]float64, len(coefficients))
+
+	for index, coefficient := range coefficients {
+
+		expanded[index] = real(coefficient)
+
+	}
+
+	return expanded
+
+}
+
+func hornerComplex(coefficients []float64, x complex128) complex128 {
+
+	value := complex(0, 0)
+
+	for _, coefficient := range coefficients {
+
+		value = value*x + complex(coefficient, 0)
+
+	}
+
+	return value
+
+}
+
+func transferCoefficients(p Polynomial) ([]float64, []float64, Domain, error) {
+
+	domain := p.domain()
+	numerator, denominator := p.analogueCoefficients()
+	var err error
+
+	if (domain == Digital) {
+
+		numerator, denominator = p.digitalCoefficients()
+		length := max(len(numerator), len(denominator))
+		numerator = append(numerator, make([]float64, length - len(numerator))...)
+		denominator = append(denominator, make([]float64, length - len(denominator))...)
+
+	} else if (len(numerator) > len(denominator)) {
+
+		err = fmt.Errorf("improper transfer function: numerator order %d exceeds denominator order %d", len(numerator) - 1, len(denominator) - 1)
+
+	} else {
+
+		numerator = append(make([]float64, len(denominator) - len(numerator)), numerator...)
+
+	}
+
+	if ((denominator[0] != 0.0) && (denominator[0] != 1.0)) {
+
+		scale := denominator[0]
+
+		for index := range numerator {
+
+			numerator[index] /= scale
+
+		}
+
+		for index := range denominator {
+
+			denominator[index] /= scale
+
+		}
+
+	}
+
+	return numerator, denominator, domain, err
+
+}
+
+func zeroPoleGain(p Polynomial) ZeroPoleGain {
+
+	numerator, denominator, domain, _ := transferCoefficients(p)
+	gain := 0.0
+
+	for _, coefficient := range numerator {
+
+		if (coefficient != 0.0) {
+
+			gain = coefficient
+			break
+
+		}
+
+	}
+
+	return ZeroPoleGain{
+
+		Zeros: polynomialRoots(numerator),
+		Poles: polynomialRoots(denominator),
+		Gain: gain,
+		Domain: domain,
+
+	}
+
+}
+
+func (z ZeroPoleGain) polynomial() Polynomial {
+
+	numerator := expandRoots(z.Zeros)
+	denominator := expandRoots(z.Poles)
+
+	for index := range numerator {
+
+		numerator[index] *= z.Gain
+
+	}
+
+	if (z.Domain == Digital) {
+
+		length := max(len(numerator), len(denominator))
+		numerator = append(make([]float64, length - len(numerator)), numerator...)
+		denominator = append(make([]float64, length - len(denominator)), denominator...)
+		return digitalPolynomial(numerator, denominator)
+
+	}
+
+	return analoguePolynomial(numerator, denominator)
+
+}
+
+func (z ZeroPoleGain) stateSpace(realization ...Realization) (StateSpace, error) {
+
+	return stateSpace(z.polynomial(), realization...)
+
+}
+
+func controllableRealization(numerator []float64, denominator []float64, domain Domain) StateSpace {
+
+	order := len(denominator) - 1
+	system := StateSpace{
+
+		A: zeroMatrix(order, order),
+		B: make([]float64, order),
+		C: make([]float64, order),
+		D: numerator[0],
+		Domain: domain,
+		Realization: Controllable,
+
+	}
+
+	for index := 0; index < order; index++ {
+
+		system.A[0][index] = -denominator[index + 1]
+		system.C[index] = numerator[index + 1] - numerator[0]*denominator[index + 1]
+
+		if (index > 0) {
+
+			system.A[index][index - 1] = 1.0
+
+		}
+
+	}
+
+	if (order > 0) {
+
+		system.B[0] = 1.0
+
+	}
+
+	return system
+
+}
+
+func modalRealization(numerator []float64, denominator []float64, domain Domain) (StateSpace, bool) {
+
+	order := len(denominator) - 1
+	poles := polynomialRoots(denominator)
+	derivative := make([]float64, order)
+	residual := make([]float64, order)
+	scale := 0.0
+
+	for index := 0; index < order; index++ {
+
+		derivative[index] = float64(order - index)*denominator[index]
+		residual[index] = numerator[index + 1] - numerator[0]*denominator[index + 1]
+		scale += math.Abs(derivative[index])
+
+	}
+
+	slices.SortStableFunc(poles, func(p complex128, q complex128) int {
+
+		if ((imag(p) == 0.0) && (imag(q) != 0.0)) {
+
+			return -1
+
+		} else if ((imag(p) != 0.0) && (imag(q) == 0.0)) {
+
+			return 1
+
+		}
+
+		return 0
+
+	})
+
+	system := StateSpace{
+
+		A: zeroMatrix(order, order),
+		B: make([]float64, order),
+		C: make([]float64, order),
+		D: numerator[0],
+		Domain: domain,
+		Realization: Modal,
+
+	}
+
+	index := 0
+
+	for _, pole := range poles {
+
+		if (imag(pole) < 0.0) {
+
+			continue
+
+		}
+
+		slope := hornerComplex(derivative, pole)
+
+		if (cmplx.Abs(slope) < 1e-8*scale*math.Pow(math.Max(cmplx.Abs(pole), 1.0), float64(order - 1))) {
+
+			return system, false
+
+		}
+
+		residue := hornerComplex(residual, pole) / slope
+
+		if (imag(pole) == 0.0) {
+
+			if (index >= order) {
+
+				return system, false
+
+			}
+
+			system.A[index][index] = real(pole)
+			system.B[index] = 1.0
+			system.C[index] = real(residue)
+			index++
+			continue
+
+		}
+
+		if (index + 1 >= order) {
+
+			return system, false
+
+		}
+
+		system.A[index][index] = real(pole)
+		system.A[index][index + 1] = -imag(pole)
+		system.A[index + 1][index] = imag(pole)
+		system.A[index + 1][index + 1] = real(pole)
+		system.B[index] = 1.0
+		system.C[index] = 2.0*real(residue)
+		system.C[index + 1] = -2.0*imag(residue)
+		index += 2
+
+	}
+
+	return system, (index == order)
+
+}
+
+func (s StateSpace) gramians() ([][]float64, [][]float64) {
+
+	controllability := lyapunov(s.A, outerProduct(s.B, s.B), s.Domain)
+	observability := lyapunov(transposeMatrix(s.A), outerProduct(s.C, s.C), s.Domain)
+	return controllability, observability
+
+}
+
+func (s StateSpace) hankelSingularValues() []float64 {
+
+	controllability, observability := s.gramians()
+	factor, definite := choleskyFactor(controllability)
+
+	if (!definite) {
+
+		return []float64{}
+
+	}
+
+	values, _ := symmetricEigen(multiplyMatrices(multiplyMatrices(transposeMatrix(factor), observability), factor))
+
+	for index := range values {
+
+		values[index] = math.Sqrt(math.Max(values[index], 0.0))
+
+	}
+
+	return values
+
+}
+
+func (s StateSpace) similarity(transform [][]float64, inverse [][]float64) StateSpace {
+
+	return StateSpace{
+
+		A: multiplyMatrices(multiplyMatrices(inverse, s.A), transform),
+		B: multiplyVector(inverse, s.B),
+		C: multiplyVector(transposeMatrix(transform), s.C),
+		D: s.D,
+		Domain: s.Domain,
+		Realization: s.Realization,
+
+	}
+
+}
+
+func (s StateSpace) balance() (StateSpace, bool) {
+
+	controllability, observability := s.gramians()
+	factor, definite := choleskyFactor(controllability)
+
+	if (!definite) {
+
+		return s, false
+
+	}
+
+	values, vectors := symmetricEigen(multiplyMatrices(multiplyMatrices(transposeMatrix(factor), observability), factor))
+	order := len(values)
+	transform := multiplyMatrices(factor, vectors)
+	inverse := multiplyMatrices(transposeMatrix(vectors), invertMatrix(factor))
+
+	for index, value := range values {
+
+		if (value <= 0.0) {
+
+			return s, false
+
+		}
+
+		sigma := math.Sqrt(math.Sqrt(value))
+
+		for k := 0; k < order; k++ {
+
+			transform[k][index] /= sigma
+			inverse[index][k] *= sigma
+
+		}
+
+	}
+
+	balanced := s.similarity(transform, inverse)
+	balanced.Realization = Balanced
+	return balanced, true
+
+}
+
+func stateSpace(p Polynomial, realization ...Realization) (StateSpace, error) {
+
+	numerator, denominator, domain, err := transferCoefficients(p)
+
+	if (err != nil) {
+
+		return StateSpace{ Domain: domain }, err
+
+	}
+
+	system := controllableRealization(numerator, denominator, domain)
+	form := Controllable
+
+	if ((len(realization) > 0) && realization[0].exists()) {
+
+		form = realization[0]
+
+	}
+
+	switch form {
+
+		case Observable:
+
+			return StateSpace{
+
+				A: transposeMatrix(system.A),
+				B: slices.Clone(system.C),
+				C: slices.Clone(system.B),
+				D: system.D,
+				Domain: domain,
+				Realization: Observable,
+
+			}, nil
+
+		case Modal:
+
+			if modal, valid := modalRealization(numerator, denominator, domain); valid {
+
+				return modal, nil
+
+			}
+
+			return system, errors.New("modal realization requires distinct poles")
+
+		case Balanced:
+
+			if balanced, valid := system.balance(); valid {
+
+				return balanced, nil
+
+			}
+
+			return system, errors.New("balanced realization requires a stable minimal system")
+
+	}
+
+	return system, nil
+
+}
+
+func (s StateSpace) order() int {
+
+	return len(s.A)
+
+}
+
+func (s StateSpace) coefficients() ([]float64, []float64) {
+
+	denominator := characteristicPolynomial(s.A)
+	numerator := characteristicPolynomial(s.A)
+	coupled := copyMatrix(s.A)
+
+	for row := range coupled {
+
+		for column := range coupled[row] {
+
+			coupled[row][column] -= s.B[row]*s.C[column]
+
+		}
+
+	}
+
+	shifted := characteristicPolynomial(coupled)
+
+	for index := range numerator {
+
+		numerator[index] = shifted[index] - denominator[index] + s.D*denominator[index]
+
+	}
+
+	return numerator, denominator
+
+}
+
+func (s StateSpace) polynomial() Polynomial {
+
+	numerator, denominator := s.coefficients()
+
+	if (s.Domain == Digital) {
+
+		return digitalPolynomial(numerator, denominator)
+
+	}
+
+	return analoguePolynomial(numerator, denominator)
+
+}
+
+func (s StateSpace) zeroPoleGain() ZeroPoleGain {
+
+	return zeroPoleGain(s.polynomial())
+
+}
+
+func (s StateSpace) poles() []complex128 {
+
+	return polynomialRoots(characteristicPolynomial(s.A))
+
+}
+
+func (s StateSpace) realize(realization Realization) (StateSpace, error) {
+
+	return stateSpace(s.polynomial(), realization)
+
+}
+
+func (s StateSpace) response(x complex128) complex128 {
+
+	order := s.order()
+	system := make([][]complex128, order)
+	input := make([]complex128, order)
+
+	for row := 0; row < order; row++ {
+
+		system[row] = make([]complex128, order)
+		input[row] = complex(s.B[row], 0)
+
+		for column := 0; column < order; column++ {
+
+			system[row][column] = complex(-s.A[row][column], 0)
+
+		}
+
+		system[row][row] += x
+
+	}
+
+	state := solveComplexLinear(system, input)
+	output := complex(s.D, 0)
+
+	for index, value := range state {
+
+		output += complex(s.C[index], 0)*value
+
+	}
+
+	return output
+
+}
+
+func (s StateSpace) frequencyResponse(frequency float64, samplingFrequency ...float64) complex128 {
+
+	x, _ := frequencyVariable(frequency, samplingFrequency...)
+	return s.response(x)
+
+}
+
+func (s StateSpace) discretize(samplingFrequency float64) StateSpace {
+
+	if ((s.Domain == Digital) || (samplingFrequency <= 0.0)) {
+
+		return s
+
+	}
+
+	order := s.order()
+	augmented := zeroMatrix(order + 1, order + 1)
+	period := 1.0 / samplingFrequency
+
+	for row := 0; row < order; row++ {
+
+		for column := 0; column < order; column++ {
+
+			augmented[row][column] = s.A[row][column]*period
+
+		}
+
+		augmented[row][order] = s.B[row]*period
+
+	}
+
+	exponential := matrixExponential(augmented)
+	discrete := StateSpace{
+
+		A: zeroMatrix(order, order),
+		B: make([]float64, order),
+		C: slices.Clone(s.C),
+		D: s.D,
+		Domain: Digital,
+		Realization: s.Realization,
+
+	}
+
+	for row := 0; row < order; row++ {
+
+		copy(discrete.A[row], exponential[row][:order])
+		discrete.B[row] = exponential[row][order]
+
+	}
+
+	return discrete
+
+}
+
+func (s StateSpace) simulate(input []float64, samplingFrequency ...float64) []float64 {
+
+	system := s
+
+	if (s.Domain == Analogue) {
+
+		rate := 1.0
+
+		if ((len(samplingFrequency) > 0) && (samplingFrequency[0] > 0.0)) {
+
+			rate = samplingFrequency[0]
+
+		}
+
+		system = s.discretize(rate)
+
+	}
+
+	order := system.order()
+	state := make([]float64, order)
+	next := make([]float64, order)
+	output := make([]float64, len(input))
+
+	for n, u := range input {
+
+		y := system.D*u
+
+		for index := range state {
+
+			y += system.C[index]*state[index]
+
+		}
+
+		for row := 0; row < order; row++ {
+
+			value := system.B[row]*u
+
+			for column := 0; column < order; column++ {
+
+				value += system.A[row][column]*state[column]
+
+			}
+
+			next[row] = value
+
+		}
+
+		state, next = next, state
+		output[n] = y
+
+	}
+
+	return output
+
+}
@@ -0,0 +1,173 @@
+package main
+
+import ( "math"
+		 "math/cmplx"
+		 "testing" )
+
+
+func TestStateSpaceRealizations(t *testing.T) {
+
+	analogue := analoguePolynomial([]float64{ 0.5, 1.0, 2.0 }, []float64{ 1.0, 2.613, 3.414, 2.613, 1.0 })
+	digital := digitalPolynomial(equalizerNumerator, equalizerDenominator)
+
+	for _, test := range []struct{ filter Polynomial; rate []float64 }{ { analogue, nil }, { digital, []float64{ 1000.0 } } } {
+
+		for _, realization := range []Realization{ Controllable, Observable, Modal, Balanced } {
+
+			system, err := stateSpace(test.filter, realization)
+
+			if (err != nil) {
+
+				t.Fatalf("%s %s: %v", test.filter.domain(), realization, err)
+
+			}
+
+			if ((system.Realization != realization) || (system.order() != 4)) {
+
+				t.Errorf("%s %s: got a %s realization of order %d", test.filter.domain(), realization, system.Realization, system.order())
+
+			}
+
+			back := system.polynomial()
+
+			for _, frequency := range []float64{ 0.01, 0.1, 1.0, 50.0, 300.0 } {
+
+				want := frequencyResponse(test.filter, frequency, test.rate...)
+
+				if got := system.frequencyResponse(frequency, test.rate...); (cmplx.Abs(got - want) > 1e-8) {
+
+					t.Errorf("%s %s response at %g = %v, want %v", test.filter.domain(), realization, frequency, got, want)
+
+				}
+
+				if got := frequencyResponse(back, frequency, test.rate...); (cmplx.Abs(got - want) > 1e-8) {
+
+					t.Errorf("%s %s round trip at %g = %v, want %v", test.filter.domain(), realization, frequency, got, want)
+
+				}
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestBalancedGramians(t *testing.T) {
+
+	system, err := stateSpace(analoguePolynomial([]float64{ 1.0 }, []float64{ 1.0, 3.0, 2.0 }), Balanced)
+
+	if (err != nil) {
+
+		t.Fatal(err)
+
+	}
+
+	// a balanced realization has equal diagonal gramians holding the hankel singular values
+	controllability, observability := system.gramians()
+	values := system.hankelSingularValues()
+
+	for row := range controllability {
+
+		for column := range controllability[row] {
+
+			want := 0.0
+
+			if (row == column) {
+
+				want = values[row]
+
+			}
+
+			if ((math.Abs(controllability[row][column] - want) > 1e-9) || (math.Abs(observability[row][column] - want) > 1e-9)) {
+
+				t.Errorf("gramians at (%d, %d) = %g and %g, want %g", row, column, controllability[row][column], observability[row][column], want)
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestStateSpaceErrors(t *testing.T) {
+
+	tests := []struct {
+
+		filter		Polynomial
+		realization Realization
+		message		string
+
+	}{
+
+		{ analoguePolynomial([]float64{ 1.0 }, []float64{ 1.0, 2.0, 1.0 }), Modal, "modal realization requires distinct poles" },
+		{ analoguePolynomial([]float64{ 1.0 }, []float64{ 1.0, -1.0, 2.0 }), Balanced, "balanced realization requires a stable minimal system" },
+		{ analoguePolynomial([]float64{ 1.0, 0.0, 0.0 }, []float64{ 1.0, 1.0 }), Controllable, "improper transfer function: numerator order 2 exceeds denominator order 1" },
+
+	}
+
+	for _, test := range tests {
+
+		if _, err := stateSpace(test.filter, test.realization); ((err == nil) || (err.Error() != test.message)) {
+
+			t.Errorf("%s: got %v, want %q", test.realization, err, test.message)
+
+		}
+
+	}
+
+}
+
+func TestZeroPoleGain(t *testing.T) {
+
+	digital := digitalPolynomial(processorNumerator, equalizerDenominator)
+	z := zeroPoleGain(digital)
+
+	if ((len(z.Zeros) != 4) || (len(z.Poles) != 4)) {
+
+		t.Errorf("got %d zeros and %d poles, want 4 of each", len(z.Zeros), len(z.Poles))
+
+	}
+
+	for _, frequency := range []float64{ 0.0, 100.0, 300.0 } {
+
+		want := frequencyResponse(digital, frequency, 1000.0)
+
+		if got := frequencyResponse(z.polynomial(), frequency, 1000.0); (cmplx.Abs(got - want) > 1e-8) {
+
+			t.Errorf("zpk response at %g Hz = %v, want %v", frequency, got, want)
+
+		}
+
+	}
+
+}
+
+func TestStateSpaceSimulation(t *testing.T) {
+
+	digital := digitalPolynomial(equalizerNumerator, equalizerDenominator)
+	system, _ := stateSpace(digital, Modal)
+	signal := testSignal(200)
+	compareSignals(t, "modal simulation", system.simulate(signal), referenceFilter(equalizerNumerator, equalizerDenominator, signal), 1e-10)
+
+	// the exact discretization of 1/(s + 1) reproduces the step response 1 - exp(-t)
+	lag, _ := stateSpace(analoguePolynomial([]float64{ 1.0 }, []float64{ 1.0, 1.0 }))
+	step := make([]float64, 101)
+
+	for index := range step {
+
+		step[index] = 1.0
+
+	}
+
+	response := lag.simulate(step, 100.0)
+
+	if (math.Abs(response[100] - (1.0 - math.Exp(-1.0))) > 1e-12) {
+
+		t.Errorf("step response at t = 1 is %g, want %g", response[100], 1.0 - math.Exp(-1.0))
+
+	}
+
+}
@@ -0,0 +1,457 @@
+package main
+
+import ( "fmt"
+		 "math"
+		 "math/cmplx"
+		 "strconv"
+		 "strings" )
+
+
+type Network struct {
+
+	Frequency  []float64
+	Parameters [][2][2]complex128
+	Reference  [2]float64
+
+}
+
+func (l Ladder) scattering(frequency float64, quality ...float64) [2][2]complex128 {
+
+	a, b, c, d := l.chain(complex(0, 2.0*math.Pi*frequency), quality...)
+	source := complex(l.Source, 0)
+	load := complex(l.Load, 0)
+	geometric := complex(2.0*math.Sqrt(l.Source*l.Load), 0)
+	denominator := a*load + b + c*source*load + d*source
+
+	return [2][2]complex128{
+
+		{ (a*load + b - c*source*load - d*source) / denominator, (a*d - b*c)*geometric / denominator },
+		{ geometric / denominator, (-a*load + b - c*source*load + d*source) / denominator },
+
+	}
+
+}
+
+func parseSweep(config Touchstone, low float64, high float64) ([]float64, []float64) {
+
+	if ((low <= 0.0) || (high <= 0.0) || math.IsInf(low, 0)) {
+
+		low, high = 1e6, 1e6
+
+	}
+
+	start := low / 10.0
+
+	if ((config.Start != nil) && (*config.Start > 0.0)) {
+
+		start = *config.Start
+
+	}
+
+	stop := 10.0*high
+
+	if ((config.Stop != nil) && (*config.Stop > start)) {
+
+		stop = *config.Stop
+
+	}
+
+	points := config.Points
+
+	if (points < 2) {
+
+		points = 201
+
+	}
+
+	quality := []float64{ 0.0, 0.0 }
+
+	if ((config.InductorQ != nil) && (*config.InductorQ > 0.0)) {
+
+		quality[0] = *config.InductorQ
+
+	}
+
+	if ((config.CapacitorQ != nil) && (*config.CapacitorQ > 0.0)) {
+
+		quality[1] = *config.CapacitorQ
+
+	}
+
+	if config.Logarithmic {
+
+		frequencies := linearSpace(math.Log10(start), math.Log10(stop), points)
+
+		for index := range frequencies {
+
+			frequencies[index] = math.Pow(10, frequencies[index])
+
+		}
+
+		return frequencies, quality
+
+	}
+
+	return linearSpace(start, stop, points), quality
+
+}
+
+func (l Ladder) network(config Touchstone) Network {
+
+	low, high := l.band()
+	frequencies, quality := parseSweep(config, low, high)
+	network := Network{ Frequency: frequencies, Parameters: make([][2][2]complex128, len(frequencies)), Reference: [2]float64{ l.Source, l.Load } }
+
+	for index, frequency := range frequencies {
+
+		network.Parameters[index] = l.scattering(frequency, quality...)
+
+	}
+
+	return network
+
+}
+
+func touchstoneUnit(frequency float64) (string, float64) {
+
+	switch {
+
+		case (frequency >= 1e9):
+
+			return "GHz", 1e9
+
+		case (frequency >= 1e6):
+
+			return "MHz", 1e6
+
+		case (frequency >= 1e3):
+
+			return "kHz", 1e3
+
+	}
+
+	return "Hz", 1.0
+
+}
+
+func (n Network) touchstone(notation Notation) string {
+
+	if (!notation.exists()) {
+
+		notation = MagnitudeAngle
+
+	}
+
+	builder := strings.Builder{}
+	unit, scale := touchstoneUnit(0.0)
+
+	if (len(n.Frequency) > 0) {
+
+		unit, scale = touchstoneUnit(n.Frequency[len(n.Frequency) - 1])
+
+	}
+
+	mixed := (math.Abs(n.Reference[0] - n.Reference[1]) > 1e-9*n.Reference[0])
+	fmt.Fprintf(&builder, "! two-port S-parameters, source %.9g ohm, load %.9g ohm\n", n.Reference[0], n.Reference[1])
+
+	if mixed {
+
+		fmt.Fprintf(&builder, "[Version] 2.0\n")
+
+	}
+
+	fmt.Fprintf(&builder, "# %s S %s R %.9g\n", unit, strings.ToUpper(string(notation)), n.Reference[0])
+
+	if mixed {
+
+		fmt.Fprintf(&builder, "[Number of Ports] 2\n[Two-Port Data Order] 21_12\n")
+		fmt.Fprintf(&builder, "[Number of Frequencies] %d\n[Reference] %.9g %.9g\n[Network Data]\n", len(n.Frequency), n.Reference[0], n.Reference[1])
+
+	}
+
+	for index, frequency := range n.Frequency {
+
+		fmt.Fprintf(&builder, "%.9g", frequency / scale)
+		parameters := n.Parameters[index]
+
+		for _, value := range []complex128{ parameters[0][0], parameters[1][0], parameters[0][1], parameters[1][1] } {
+
+			magnitude := cmplx.Abs(value)
+			angle := cmplx.Phase(value)*180.0 / math.Pi
+
+			switch notation {
+
+				case RealImaginary:
+
+					fmt.Fprintf(&builder, " %.9g %.9g", real(value), imag(value))
+
+				case DecibelAngle:
+
+					fmt.Fprintf(&builder, " %.9g %.9g", decibels(math.Max(magnitude, 1e-300)), angle)
+
+				default:
+
+					fmt.Fprintf(&builder, " %.9g %.9g", magnitude, angle)
+
+			}
+
+		}
+
+		builder.WriteString("\n")
+
+	}
+
+	if mixed {
+
+		fmt.Fprintf(&builder, "[End]\n")
+
+	}
+
+	return builder.String()
+
+}
+
+func (l Ladder) touchstone(config Touchstone) string {
+
+	return l.network(config).touchstone(config.Notation)
+
+}
+
+func touchstonePair(first float64, second float64, notation Notation) complex128 {
+
+	switch notation {
+
+		case RealImaginary:
+
+			return complex(first, second)
+
+		case DecibelAngle:
+
+			return cmplx.Rect(math.Pow(10, first / 20.0), second*math.Pi / 180.0)
+
+	}
+
+	return cmplx.Rect(first, second*math.Pi / 180.0)
+
+}
+
+func parseTouchstone(data string) (Network, error) {
+
+	network := Network{ Frequency: []float64{}, Parameters: [][2][2]complex128{}, Reference: [2]float64{ 50.0, 50.0 } }
+	scale := 1e9
+	notation := MagnitudeAngle
+	parameter := "s"
+	swapped := false
+	version := false
+	active := true
+	values := []float64{}
+
+	for number, line := range strings.Split(data, "\n") {
+
+		line, _, _ = strings.Cut(line, "!")
+		fields := strings.Fields(line)
+
+		if (len(fields) == 0) {
+
+			continue
+
+		}
+
+		if strings.HasPrefix(fields[0], "#") {
+
+			fields = strings.Fields(strings.ToLower(strings.TrimPrefix(strings.TrimSpace(line), "#")))
+
+			for index := 0; index < len(fields); index++ {
+
+				switch fields[index] {
+
+					case "hz", "khz", "mhz", "ghz":
+
+						scale = map[string]float64{ "hz": 1.0, "khz": 1e3, "mhz": 1e6, "ghz": 1e9 }[fields[index]]
+
+					case "s", "y", "z", "h", "g":
+
+						parameter = fields[index]
+
+					case "ma", "db", "ri":
+
+						notation = Notation(fields[index])
+
+					case "r":
+
+						if (index + 1 < len(fields)) {
+
+							resistance, err := strconv.ParseFloat(fields[index + 1], 64)
+
+							if (err != nil) {
+
+								return network, fmt.Errorf("line %d: invalid reference resistance %q", number + 1, fields[index + 1])
+
+							}
+
+							network.Reference = [2]float64{ resistance, resistance }
+
+							index++
+
+						}
+
+				}
+
+			}
+
+			continue
+
+		}
+
+		if strings.HasPrefix(fields[0], "[") {
+
+			keyword, rest, _ := strings.Cut(strings.ToLower(strings.TrimSpace(line)), "]")
+			arguments := strings.Fields(rest)
+
+			switch strings.TrimSpace(strings.TrimPrefix(keyword, "[")) {
+
+				case "version":
+
+					version = true
+					active = false
+
+				case "two-port data order":
+
+					swapped = ((len(arguments) > 0) && (arguments[0] == "12_21"))
+
+				case "reference":
+
+					for index := 0; index < min(len(arguments), 2); index++ {
+
+						resistance, err := strconv.ParseFloat(arguments[index], 64)
+
+						if (err != nil) {
+
+							return network, fmt.Errorf("line %d: invalid reference resistance %q", number + 1, arguments[index])
+
+						}
+
+						network.Reference[index] = resistance
+
+						if (len(arguments) == 1) {
+
+							network.Reference[1] = resistance
+
+						}
+
+					}
+
+				case "network data":
+
+					active = true
+
+				default:
+
+					active = !version
+
+			}
+
+			continue
+
+		}
+
+		if !active {
+
+			continue
+
+		}
+
+		for _, field := range fields {
+
+			value, err := strconv.ParseFloat(field, 64)
+
+			if (err != nil) {
+
+				return network, fmt.Errorf("line %d: invalid value %q", number + 1, field)
+
+			}
+
+			values = append(values, value)
+
+		}
+
+	}
+
+	if (parameter != "s") {
+
+		return network, fmt.Errorf("%s parameters are not supported, only S parameters can be read", strings.ToUpper(parameter))
+
+	}
+
+	index := 0
+
+	for ; index + 9 <= len(values); index += 9 {
+
+		frequency := values[index]*scale
+
+		// a falling frequency starts the noise parameter block of a version 1 file
+		if ((len(network.Frequency) > 0) && (frequency <= network.Frequency[len(network.Frequency) - 1])) {
+
+			return network, nil
+
+		}
+
+		record := values[index + 1:index + 9]
+		forward := touchstonePair(record[2], record[3], notation)
+		reverse := touchstonePair(record[4], record[5], notation)
+
+		if swapped {
+
+			forward, reverse = reverse, forward
+
+		}
+
+		network.Frequency = append(network.Frequency, frequency)
+		network.Parameters = append(network.Parameters, [2][2]complex128{
+
+			{ touchstonePair(record[0], record[1], notation), reverse },
+			{ forward, touchstonePair(record[6], record[7], notation) },
+
+		})
+
+	}
+
+	if ((index < len(values)) &&
+		((len(network.Frequency) == 0) || (values[index]*scale > network.Frequency[len(network.Frequency) - 1]))) {
+
+		return network, fmt.Errorf("final record has %d of 9 values", len(values) - index)
+
+	}
+
+	return network, nil
+
+}
+
+func (n Network) compare(designed Ladder, quality ...float64) (Overlay, Overlay) {
+
+	terminated := Ladder{ Elements: designed.Elements, Source: n.Reference[0], Load: n.Reference[1] }
+	transmission := make([]complex128, len(n.Parameters))
+	reflection := make([]complex128, len(n.Parameters))
+
+	for index, parameters := range n.Parameters {
+
+		transmission[index] = parameters[1][0]
+		reflection[index] = parameters[0][0]
+
+	}
+
+	forward := func(frequency float64) complex128 {
+
+		return terminated.scattering(frequency, quality...)[1][0]
+
+	}
+
+	input := func(frequency float64) complex128 {
+
+		return terminated.scattering(frequency, quality...)[0][0]
+
+	}
+
+	return overlayResponses(n.Frequency, transmission, forward), overlayResponses(n.Frequency, reflection, input)
+
+}
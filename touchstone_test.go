@@ -0,0 +1,155 @@
+package main
+
+import ( "math"
+		 "math/cmplx"
+		 "testing" )
+
+
+func TestScattering(t *testing.T) {
+
+	for _, load := range []float64{ 50.0, 100.0 } {
+
+		for _, response := range []Response{ LPF, HPF, BPF, BSF } {
+
+			ladder, _ := designPassive(Specs{ Response: response, Approximation: Chebyshev, Order: pointer(uint16(5)), PassbandAttenuation: pointer(0.5), CutoffFrequency: pointer(400e6), CenterFrequency: pointer(900e6), Bandwidth: pointer(100e6), LoadImpedance: pointer(load) })
+
+			for _, frequency := range []float64{ 1e8, 4e8, 8.7e8, 9e8, 2e9 } {
+
+				// a lossless reciprocal two-port passes or reflects all of the incident power
+				s := ladder.scattering(frequency)
+				power := cmplx.Abs(s[0][0])*cmplx.Abs(s[0][0]) + cmplx.Abs(s[1][0])*cmplx.Abs(s[1][0])
+
+				if ((math.Abs(power - 1.0) > 1e-9) || (cmplx.Abs(s[1][0] - ladder.response(frequency)) > 1e-9) || (cmplx.Abs(s[0][1] - s[1][0]) > 1e-9)) {
+
+					t.Errorf("%s into %g ohm at %g Hz: S = %v", response, load, frequency, s)
+
+				}
+
+				lossy := ladder.scattering(frequency, 50.0, 200.0)
+
+				if power := cmplx.Abs(lossy[0][0])*cmplx.Abs(lossy[0][0]) + cmplx.Abs(lossy[1][0])*cmplx.Abs(lossy[1][0]); (power >= 1.0) {
+
+					t.Errorf("%s into %g ohm at %g Hz: lossy ladder returns %g of the incident power", response, load, frequency, power)
+
+				}
+
+			}
+
+		}
+
+	}
+
+}
+
+func TestTouchstoneRoundTrip(t *testing.T) {
+
+	ladder, _ := designPassive(Specs{ Response: BPF, Approximation: Chebyshev, Order: pointer(uint16(5)), PassbandAttenuation: pointer(0.5), CenterFrequency: pointer(900e6), Bandwidth: pointer(100e6), LoadImpedance: pointer(100.0) })
+
+	for _, notation := range []Notation{ "", MagnitudeAngle, DecibelAngle, RealImaginary } {
+
+		// the written reference keeps a limited number of digits
+		config := Touchstone{ Notation: notation, InductorQ: pointer(80.0), CapacitorQ: pointer(300.0), Points: 51, Logarithmic: (notation == DecibelAngle) }
+		parsed, err := parseTouchstone(ladder.touchstone(config))
+
+		if ((err != nil) || (len(parsed.Frequency) != 51) || (parsed.Reference[0] != ladder.Source) || (math.Abs(parsed.Reference[1] - ladder.Load) > 1e-6)) {
+
+			t.Fatalf("%q: got %d frequencies, references %v and %v", notation, len(parsed.Frequency), parsed.Reference, err)
+
+		}
+
+		original := ladder.network(config)
+
+		for index, frequency := range parsed.Frequency {
+
+			if (math.Abs(frequency / original.Frequency[index] - 1.0) > 1e-7) {
+
+				t.Errorf("%q: frequency %d = %g, want %g", notation, index, frequency, original.Frequency[index])
+
+			}
+
+			for row := 0; row < 2; row++ {
+
+				for column := 0; column < 2; column++ {
+
+					if (cmplx.Abs(parsed.Parameters[index][row][column] - original.Parameters[index][row][column]) > 1e-7) {
+
+						t.Errorf("%q: S%d%d at %g Hz = %v, want %v", notation, row + 1, column + 1, frequency, parsed.Parameters[index][row][column], original.Parameters[index][row][column])
+
+					}
+
+				}
+
+			}
+
+		}
+
+		// the file only matches the design once the same element losses are applied
+		transmission, reflection := parsed.compare(ladder, 80.0, 300.0)
+		lossless, _ := parsed.compare(ladder)
+
+		if ((transmission.Error > 1e-5) || (reflection.Error > 1e-3) || (lossless.Error < 1e-3)) {
+
+			t.Errorf("%q: lossy comparison errors %g and %g, lossless %g", notation, transmission.Error, reflection.Error, lossless.Error)
+
+		}
+
+	}
+
+}
+
+func TestParseTouchstone(t *testing.T) {
+
+	// version 1 records may wrap across lines and a noise block follows the first frequency decrease
+	version1 := "! measured\n# MHz S RI R 75\n! freq\n100 0.1 0.2 0.9 -0.1\n    0.9 -0.1 0.3 0.0\n200 0.1 0.2 0.8 -0.1 0.8 -0.1 0.3 0.0\n! noise\n100 1.2 0.5 30 0.3\n"
+
+	if network, err := parseTouchstone(version1); ((err != nil) || (len(network.Frequency) != 2) || (network.Frequency[1] != 2e8) || (network.Reference != [2]float64{ 75.0, 75.0 }) || (network.Parameters[1][1][0] != complex(0.8, -0.1)) || (network.Parameters[0][1][1] != complex(0.3, 0.0))) {
+
+		t.Errorf("version 1 file gives %+v and %v", network, err)
+
+	}
+
+	version2 := "[Version] 2.0\n# GHz S DB R 50\n[Number of Ports] 2\n[Two-Port Data Order] 12_21\n[Number of Frequencies] 1\n[Reference] 50 100\n[Network Data]\n1 -20 0 -1 90 -3 -90 -10 0\n[End]\n"
+	network, err := parseTouchstone(version2)
+
+	if ((err != nil) || (len(network.Frequency) != 1) || (network.Frequency[0] != 1e9) || (network.Reference != [2]float64{ 50.0, 100.0 })) {
+
+		t.Fatalf("version 2 file gives %+v and %v", network, err)
+
+	}
+
+	// 12_21 order puts the second pair in S12
+	if ((math.Abs(cmplx.Phase(network.Parameters[0][1][0]) + math.Pi/2.0) > 1e-12) || (math.Abs(cmplx.Abs(network.Parameters[0][0][1]) - math.Pow(10, -0.05)) > 1e-12)) {
+
+		t.Errorf("version 2 parameters are %v", network.Parameters[0])
+
+	}
+
+}
+
+func TestTouchstoneErrors(t *testing.T) {
+
+	tests := []struct {
+
+		data	string
+		message string
+
+	}{
+
+		{ "# MHz Y RI R 50\n100 1 0 0 0 0 0 1 0\n", "Y parameters are not supported, only S parameters can be read" },
+		{ "# MHz S RI R 50\n100 1 0 0 x 0 0 1 0\n", "line 2: invalid value \"x\"" },
+		{ "# MHz S RI R 50\n100 1 0 0 0 0 0 1 0\n200 1 0 0\n", "final record has 4 of 9 values" },
+		{ "# MHz S RI R abc\n", "line 1: invalid reference resistance \"abc\"" },
+
+	}
+
+	for _, test := range tests {
+
+		if _, err := parseTouchstone(test.data); ((err == nil) || (err.Error() != test.message)) {
+
+			t.Errorf("got %v, want %q", err, test.message)
+
+		}
+
+	}
+
+}
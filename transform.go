@@ -0,0 +1,180 @@
+package main
+
+import ( "math" )
+
+
+func lowPassToLowPass(prototype Polynomial, cutoffFrequency float64) Polynomial {
+
+	omega := 2.0*math.Pi*cutoffFrequency
+	numerator, denominator := prototype.analogueCoefficients()
+
+	for index := range numerator {
+
+		numerator[index] /= math.Pow(omega, float64(len(numerator) - index - 1))
+
+	}
+
+	for index := range denominator {
+
+		denominator[index] /= math.Pow(omega, float64(len(denominator) - index - 1))
+
+	}
+
+	return analoguePolynomial(numerator, denominator)
+
+}
+
+func lowPassToHighPass(prototype Polynomial, cutoffFrequency float64) Polynomial {
+
+	omega := 2.0*math.Pi*cutoffFrequency
+	numerator, denominator := prototype.analogueCoefficients()
+	order := max(len(numerator), len(denominator))
+	top := make([]float64, order)
+	bottom := make([]float64, order)
+
+	for index, coefficient := range numerator {
+
+		power := len(numerator) - index - 1
+		top[power] = coefficient*math.Pow(omega, float64(power))
+
+	}
+
+	for index, coefficient := range denominator {
+
+		power := len(denominator) - index - 1
+		bottom[power] = coefficient*math.Pow(omega, float64(power))
+
+	}
+
+	return analoguePolynomial(top, bottom)
+
+}
+
+func bilinear(analogue Polynomial, samplingFrequency float64, prewarp ...float64) Polynomial {
+
+	k := 2.0*samplingFrequency
+
+	if ((len(prewarp) > 0) &&
+		(prewarp[0] > 0.0) &&
+		(prewarp[0] < samplingFrequency / 2.0)) {
+
+		omega := 2.0*math.Pi*prewarp[0]
+		k = omega / math.Tan(omega / (2.0*samplingFrequency))
+
+	}
+
+	numerator, denominator := analogue.analogueCoefficients()
+	order := max(len(numerator), len(denominator)) - 1
+	expand := func(coefficients []float64) []float64 {
+
+		result := make([]float64, order + 1)
+
+		for index, coefficient := range coefficients {
+
+			power := len(coefficients) - index - 1
+			term := []float64{ coefficient*math.Pow(k, float64(power)) }
+
+			for count := 0; count < power; count++ {
+
+				term = convolve(term, []float64{ 1.0, -1.0 })
+
+			}
+
+			for count := 0; count < order - power; count++ {
+
+				term = convolve(term, []float64{ 1.0, 1.0 })
+
+			}
+
+			for position, value := range term {
+
+				result[position] += value
+
+			}
+
+		}
+
+		return result
+
+	}
+
+	top := expand(numerator)
+	bottom := expand(denominator)
+	scale := bottom[0]
+
+	for index := range top {
+
+		top[index] /= scale
+		bottom[index] /= scale
+
+	}
+
+	return digitalPolynomial(top, bottom)
+
+}
+
+func substitute(coefficients []float64, order int, top []float64, bottom []float64) []float64 {
+
+	// sum of c_i*top^i*bottom^(order - i), clearing the denominator of a rational substitution
+	result := []float64{ 0.0 }
+
+	for index, coefficient := range coefficients {
+
+		power := len(coefficients) - index - 1
+		term := []float64{ coefficient }
+
+		for count := 0; count < power; count++ {
+
+			term = convolve(term, top)
+
+		}
+
+		for count := 0; count < order - power; count++ {
+
+			term = convolve(term, bottom)
+
+		}
+
+		if (len(term) > len(result)) {
+
+			result = append(make([]float64, len(term) - len(result)), result...)
+
+		}
+
+		offset := len(result) - len(term)
+
+		for position, value := range term {
+
+			result[offset + position] += value
+
+		}
+
+	}
+
+	return result
+
+}
+
+func lowPassToBandPass(prototype Polynomial, centerFrequency float64, bandwidth float64) Polynomial {
+
+	omega := 2.0*math.Pi*centerFrequency
+	width := 2.0*math.Pi*bandwidth
+	numerator, denominator := prototype.analogueCoefficients()
+	order := max(len(numerator), len(denominator)) - 1
+	resonator := []float64{ 1.0, 0.0, omega*omega }
+	integrator := []float64{ width, 0.0 }
+	return analoguePolynomial(substitute(numerator, order, resonator, integrator), substitute(denominator, order, resonator, integrator))
+
+}
+
+func lowPassToBandStop(prototype Polynomial, centerFrequency float64, bandwidth float64) Polynomial {
+
+	omega := 2.0*math.Pi*centerFrequency
+	width := 2.0*math.Pi*bandwidth
+	numerator, denominator := prototype.analogueCoefficients()
+	order := max(len(numerator), len(denominator)) - 1
+	resonator := []float64{ 1.0, 0.0, omega*omega }
+	integrator := []float64{ width, 0.0 }
+	return analoguePolynomial(substitute(numerator, order, integrator, resonator), substitute(denominator, order, integrator, resonator))
+
+}
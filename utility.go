@@ -1,6 +1,9 @@
 package main
 
 import ( "strings"
+		 "math"
+		 "math/cmplx"
+		 "slices"
 		 "strconv" )
 
 
@@ -48,3 +51,613 @@ func getSuperscript(exponent string) string {
 	return power
 
 }
+
+func convolve(p []float64, q []float64) []float64 {
+
+	if ((len(p) == 0) ||
+		(len(q) == 0)) {
+
+		return []float64{}
+
+	}
+
+	product := make([]float64, len(p) + len(q) - 1)
+
+	for i, a := range p {
+
+		for j, b := range q {
+
+			product[i + j] += a*b
+
+		}
+
+	}
+
+	return product
+
+}
+
+func complexPower(x complex128, exponent int64) complex128 {
+
+	power := complex(1, 0)
+	base := x
+
+	if (exponent < 0) {
+
+		base = 1 / x
+		exponent = -exponent
+
+	}
+
+	for (exponent > 0) {
+
+		if (exponent%2 == 1) {
+
+			power *= base
+
+		}
+
+		base *= base
+		exponent /= 2
+
+	}
+
+	return power
+
+}
+
+func linearSpace(start float64, stop float64, points int) []float64 {
+
+	grid := make([]float64, points)
+
+	if (points == 1) {
+
+		grid[0] = start
+		return grid
+
+	}
+
+	step := (stop - start) / float64(points - 1)
+
+	for index := 0; index < points; index++ {
+
+		grid[index] = start + float64(index)*step
+
+	}
+
+	return grid
+
+}
+
+func nelderMead(objective func([]float64) float64, initial []float64, iterations int, tolerance float64) ([]float64, float64) {
+
+	dimension := len(initial)
+	simplex := make([][]float64, dimension + 1)
+	cost := make([]float64, dimension + 1)
+
+	for index := range simplex {
+
+		vertex := append([]float64{}, initial...)
+
+		if (index > 0) {
+
+			step := 0.1*math.Abs(vertex[index - 1])
+
+			if (step == 0.0) {
+
+				step = 0.05
+
+			}
+
+			vertex[index - 1] += step
+
+		}
+
+		simplex[index] = vertex
+		cost[index] = objective(vertex)
+
+	}
+
+	for iteration := 0; iteration < iterations; iteration++ {
+
+		for i := 1; i <= dimension; i++ {
+
+			for j := i; (j > 0) && (cost[j] < cost[j - 1]); j-- {
+
+				simplex[j], simplex[j - 1] = simplex[j - 1], simplex[j]
+				cost[j], cost[j - 1] = cost[j - 1], cost[j]
+
+			}
+
+		}
+
+		if (math.Abs(cost[dimension] - cost[0]) <= tolerance*(math.Abs(cost[0]) + tolerance)) {
+
+			break
+
+		}
+
+		centroid := make([]float64, dimension)
+
+		for _, vertex := range simplex[:dimension] {
+
+			for index, value := range vertex {
+
+				centroid[index] += value / float64(dimension)
+
+			}
+
+		}
+
+		worst := simplex[dimension]
+		point := func(scale float64) []float64 {
+
+			vertex := make([]float64, dimension)
+
+			for index := range vertex {
+
+				vertex[index] = centroid[index] + scale*(worst[index] - centroid[index])
+
+			}
+
+			return vertex
+
+		}
+
+		reflected := point(-1.0)
+		reflectedCost := objective(reflected)
+
+		if (reflectedCost < cost[0]) {
+
+			expanded := point(-2.0)
+			expandedCost := objective(expanded)
+
+			if (expandedCost < reflectedCost) {
+
+				simplex[dimension], cost[dimension] = expanded, expandedCost
+
+			} else {
+
+				simplex[dimension], cost[dimension] = reflected, reflectedCost
+
+			}
+
+		} else if (reflectedCost < cost[dimension - 1]) {
+
+			simplex[dimension], cost[dimension] = reflected, reflectedCost
+
+		} else {
+
+			contracted := point(0.5)
+			contractedCost := objective(contracted)
+
+			if (contractedCost < cost[dimension]) {
+
+				simplex[dimension], cost[dimension] = contracted, contractedCost
+
+			} else {
+
+				for index := 1; index <= dimension; index++ {
+
+					for k := range simplex[index] {
+
+						simplex[index][k] = simplex[0][k] + 0.5*(simplex[index][k] - simplex[0][k])
+
+					}
+
+					cost[index] = objective(simplex[index])
+
+				}
+
+			}
+
+		}
+
+	}
+
+	best := 0
+
+	for index := range cost {
+
+		if (cost[index] < cost[best]) {
+
+			best = index
+
+		}
+
+	}
+
+	return simplex[best], cost[best]
+
+}
+
+func solveLinear(matrix [][]float64, vector []float64) []float64 {
+
+	size := len(vector)
+	a := make([][]float64, size)
+
+	for row := range a {
+
+		a[row] = append(append([]float64{}, matrix[row]...), vector[row])
+
+	}
+
+	for column := 0; column < size; column++ {
+
+		pivot := column
+
+		for row := column + 1; row < size; row++ {
+
+			if (math.Abs(a[row][column]) > math.Abs(a[pivot][column])) {
+
+				pivot = row
+
+			}
+
+		}
+
+		a[column], a[pivot] = a[pivot], a[column]
+
+		if (a[column][column] == 0.0) {
+
+			continue
+
+		}
+
+		for row := column + 1; row < size; row++ {
+
+			factor := a[row][column] / a[column][column]
+
+			for k := column; k <= size; k++ {
+
+				a[row][k] -= factor*a[column][k]
+
+			}
+
+		}
+
+	}
+
+	solution := make([]float64, size)
+
+	for row := size - 1; row >= 0; row-- {
+
+		sum := a[row][size]
+
+		for k := row + 1; k < size; k++ {
+
+			sum -= a[row][k]*solution[k]
+
+		}
+
+		if (a[row][row] != 0.0) {
+
+			solution[row] = sum / a[row][row]
+
+		}
+
+	}
+
+	return solution
+
+}
+
+func hornerBound(coefficients []complex128, x complex128) (complex128, float64) {
+
+	value := complex(0, 0)
+	bound := 0.0
+
+	for _, coefficient := range coefficients {
+
+		value = value*x + coefficient
+		bound = bound*cmplx.Abs(x) + cmplx.Abs(coefficient)
+
+	}
+
+	return value, bound
+
+}
+
+func clusterRoots(monic []complex128, roots []complex128) {
+
+	// a root of multiplicity m only converges to about eps^(1/m), so replace each
+	// cluster by the simple root of the (m - 1)th derivative when it also zeroes p
+	assigned := make([]bool, len(roots))
+
+	for seed := range roots {
+
+		if assigned[seed] {
+
+			continue
+
+		}
+
+		assigned[seed] = true
+		cluster := []int{ seed }
+		tolerance := 0.05*math.Max(cmplx.Abs(roots[seed]), 1.0)
+
+		for candidate := seed + 1; candidate < len(roots); candidate++ {
+
+			if (!assigned[candidate] && (cmplx.Abs(roots[candidate] - roots[seed]) < tolerance)) {
+
+				cluster = append(cluster, candidate)
+
+			}
+
+		}
+
+		if (len(cluster) < 2) {
+
+			continue
+
+		}
+
+		centroid := complex(0, 0)
+
+		for _, index := range cluster {
+
+			centroid += roots[index] / complex(float64(len(cluster)), 0)
+
+		}
+
+		derivative := slices.Clone(monic)
+
+		for order := 1; order < len(cluster); order++ {
+
+			degree := len(derivative) - 1
+
+			for index := range derivative[:degree] {
+
+				derivative[index] *= complex(float64(degree - index), 0)
+
+			}
+
+			derivative = derivative[:degree]
+
+		}
+
+		slope := slices.Clone(derivative[:len(derivative) - 1])
+
+		for index := range slope {
+
+			slope[index] *= complex(float64(len(slope) - index), 0)
+
+		}
+
+		for iteration := 0; iteration < 50; iteration++ {
+
+			value, _ := hornerBound(derivative, centroid)
+			gradient, _ := hornerBound(slope, centroid)
+
+			if (gradient == 0) {
+
+				break
+
+			}
+
+			step := value / gradient
+			centroid -= step
+
+			if (cmplx.Abs(step) <= 1e-15*math.Max(cmplx.Abs(centroid), 1.0)) {
+
+				break
+
+			}
+
+		}
+
+		value, bound := hornerBound(monic, centroid)
+
+		if (cmplx.Abs(value) > 2e-15*float64(len(monic))*bound) {
+
+			continue
+
+		}
+
+		for _, index := range cluster {
+
+			roots[index] = centroid
+			assigned[index] = true
+
+		}
+
+	}
+
+}
+
+func polynomialRoots(coefficients []float64) []complex128 {
+
+	for ((len(coefficients) > 0) && (coefficients[0] == 0.0)) {
+
+		coefficients = coefficients[1:]
+
+	}
+
+	degree := len(coefficients) - 1
+
+	if (degree < 1) {
+
+		return []complex128{}
+
+	}
+
+	monic := make([]complex128, degree + 1)
+
+	for index, coefficient := range coefficients {
+
+		monic[index] = complex(coefficient / coefficients[0], 0)
+
+	}
+
+	radius := 0.0
+
+	for _, coefficient := range monic[1:] {
+
+		radius = math.Max(radius, cmplx.Abs(coefficient))
+
+	}
+
+	radius = 1.0 + radius
+	roots := make([]complex128, degree)
+
+	for index := range roots {
+
+		roots[index] = cmplx.Rect(radius, 2.0*math.Pi*float64(index) / float64(degree) + 0.4)
+
+	}
+
+	evaluate := func(x complex128) (complex128, complex128) {
+
+		value := complex(0, 0)
+		slope := complex(0, 0)
+
+		for _, coefficient := range monic {
+
+			slope = slope*x + value
+			value = value*x + coefficient
+
+		}
+
+		return value, slope
+
+	}
+
+	for iteration := 0; iteration < 500; iteration++ {
+
+		converged := true
+
+		for i := range roots {
+
+			value, slope := evaluate(roots[i])
+
+			if (value == 0) {
+
+				continue
+
+			}
+
+			ratio := value / slope
+			sum := complex(0, 0)
+
+			for j := range roots {
+
+				if (i != j) {
+
+					sum += 1 / (roots[i] - roots[j])
+
+				}
+
+			}
+
+			step := ratio / (1 - ratio*sum)
+			roots[i] -= step
+
+			if (cmplx.Abs(step) > 1e-14*math.Max(cmplx.Abs(roots[i]), 1.0)) {
+
+				converged = false
+
+			}
+
+		}
+
+		if converged {
+
+			break
+
+		}
+
+	}
+
+	clusterRoots(monic, roots)
+
+	for index, root := range roots {
+
+		if (math.Abs(imag(root)) < 1e-9*math.Max(cmplx.Abs(root), 1.0)) {
+
+			roots[index] = complex(real(root), 0)
+
+		}
+
+	}
+
+	return roots
+
+}
+
+func quadraticFactors(roots []complex128) [][3]float64 {
+
+	factors := [][3]float64{}
+	reals := []float64{}
+	upper := []complex128{}
+	lower := []complex128{}
+
+	for _, root := range roots {
+
+		if (imag(root) > 0.0) {
+
+			upper = append(upper, root)
+
+		} else if (imag(root) < 0.0) {
+
+			lower = append(lower, root)
+
+		} else {
+
+			reals = append(reals, real(root))
+
+		}
+
+	}
+
+	for _, root := range upper {
+
+		if (len(lower) == 0) {
+
+			reals = append(reals, real(root))
+			continue
+
+		}
+
+		nearest := 0
+
+		for index := range lower {
+
+			if (cmplx.Abs(lower[index] - cmplx.Conj(root)) < cmplx.Abs(lower[nearest] - cmplx.Conj(root))) {
+
+				nearest = index
+
+			}
+
+		}
+
+		partner := lower[nearest]
+		lower = slices.Delete(lower, nearest, nearest + 1)
+		factors = append(factors, [3]float64{ 1.0, -real(root + partner), real(root*partner) })
+
+	}
+
+	for _, root := range lower {
+
+		reals = append(reals, real(root))
+
+	}
+
+	slices.Sort(reals)
+
+	for index := 0; index < len(reals); index += 2 {
+
+		if (index + 1 < len(reals)) {
+
+			factors = append(factors, [3]float64{ 1.0, -(reals[index] + reals[index + 1]), reals[index]*reals[index + 1] })
+
+		} else {
+
+			factors = append(factors, [3]float64{ 1.0, -reals[index], 0.0 })
+
+		}
+
+	}
+
+	return factors
+
+}
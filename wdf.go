@@ -0,0 +1,379 @@
+package main
+
+import ( "fmt"
+		 "math" )
+
+
+type WaveDigitalFilter struct {
+
+	Ladder			  Ladder
+	SamplingFrequency float64
+	elements		  []waveElement
+	upward			  []float64
+	reflected		  []float64
+	normalization	  float64
+
+}
+
+type waveLeaf struct {
+
+	resistance float64
+	sign	   float64
+	state	   float64
+
+}
+
+type waveElement struct {
+
+	leaves	   []waveLeaf
+	parallel   bool
+	shunt	   bool
+	resistance float64
+	down	   float64
+
+}
+
+func newWaveElement(element Element, samplingFrequency float64) waveElement {
+
+	wave := waveElement{ leaves: []waveLeaf{}, parallel: element.Parallel, shunt: element.Shunt }
+
+	if (element.Inductance > 0.0) {
+
+		wave.leaves = append(wave.leaves, waveLeaf{ resistance: 2.0*element.Inductance*samplingFrequency, sign: -1.0 })
+
+	}
+
+	if (element.Capacitance > 0.0) {
+
+		wave.leaves = append(wave.leaves, waveLeaf{ resistance: 1.0 / (2.0*element.Capacitance*samplingFrequency), sign: 1.0 })
+
+	}
+
+	switch len(wave.leaves) {
+
+		case 1:
+
+			wave.resistance = wave.leaves[0].resistance
+
+		case 2:
+
+			if wave.parallel {
+
+				wave.resistance = 1.0 / (1.0 / wave.leaves[0].resistance + 1.0 / wave.leaves[1].resistance)
+
+			} else {
+
+				wave.resistance = wave.leaves[0].resistance + wave.leaves[1].resistance
+
+			}
+
+	}
+
+	return wave
+
+}
+
+func (w *waveElement) wave() float64 {
+
+	if (len(w.leaves) == 1) {
+
+		return w.leaves[0].sign*w.leaves[0].state
+
+	}
+
+	first := w.leaves[0].sign*w.leaves[0].state
+	second := w.leaves[1].sign*w.leaves[1].state
+
+	if w.parallel {
+
+		return w.resistance*(first / w.leaves[0].resistance + second / w.leaves[1].resistance)
+
+	}
+
+	return -(first + second)
+
+}
+
+func (w *waveElement) incident(a float64) {
+
+	if (len(w.leaves) == 1) {
+
+		w.leaves[0].state = a
+		return
+
+	}
+
+	first := w.leaves[0].sign*w.leaves[0].state
+	second := w.leaves[1].sign*w.leaves[1].state
+
+	if w.parallel {
+
+		junction := a + w.resistance*(first / w.leaves[0].resistance + second / w.leaves[1].resistance)
+		w.leaves[0].state = junction - first
+		w.leaves[1].state = junction - second
+		return
+
+	}
+
+	sum := a + first + second
+	w.leaves[0].state = first - w.leaves[0].resistance / w.resistance*sum
+	w.leaves[1].state = second - w.leaves[1].resistance / w.resistance*sum
+
+}
+
+func scatter(shunt bool, resistances [3]float64, incident [3]float64) [3]float64 {
+
+	reflected := [3]float64{}
+
+	if shunt {
+
+		total := 0.0
+		junction := 0.0
+
+		for index := range resistances {
+
+			total += 1.0 / resistances[index]
+			junction += 2.0*incident[index] / resistances[index]
+
+		}
+
+		for index := range reflected {
+
+			reflected[index] = junction / total - incident[index]
+
+		}
+
+		return reflected
+
+	}
+
+	total := resistances[0] + resistances[1] + resistances[2]
+	sum := incident[0] + incident[1] + incident[2]
+
+	for index := range reflected {
+
+		reflected[index] = incident[index] - 2.0*resistances[index] / total*sum
+
+	}
+
+	return reflected
+
+}
+
+func newWaveDigitalFilter(ladder Ladder, samplingFrequency float64) *WaveDigitalFilter {
+
+	filter := &WaveDigitalFilter{
+
+		Ladder: ladder,
+		SamplingFrequency: samplingFrequency,
+		elements: []waveElement{},
+		normalization: 2.0*math.Sqrt(ladder.Source / ladder.Load),
+
+	}
+
+	for _, element := range ladder.Elements {
+
+		wave := newWaveElement(element, samplingFrequency)
+
+		if (len(wave.leaves) > 0) {
+
+			filter.elements = append(filter.elements, wave)
+
+		}
+
+	}
+
+	down := ladder.Load
+
+	for index := len(filter.elements) - 1; index >= 0; index-- {
+
+		element := &filter.elements[index]
+		element.down = down
+
+		if element.shunt {
+
+			down = 1.0 / (1.0 / element.resistance + 1.0 / down)
+
+		} else {
+
+			down = element.resistance + down
+			filter.normalization = -filter.normalization
+
+		}
+
+	}
+
+	filter.upward = make([]float64, len(filter.elements) + 1)
+	filter.reflected = make([]float64, len(filter.elements))
+	return filter
+
+}
+
+func designWaveDigitalFilter(config Specs) (*WaveDigitalFilter, error) {
+
+	samplingFrequency := 48000.0
+
+	if ((config.SamplingFrequency != nil) && (*config.SamplingFrequency > 0.0)) {
+
+		samplingFrequency = *config.SamplingFrequency
+
+	}
+
+	cutoffFrequency := samplingFrequency / 8.0
+
+	if ((config.CutoffFrequency != nil) &&
+		(*config.CutoffFrequency > 0.0) &&
+		(*config.CutoffFrequency < samplingFrequency / 2.0)) {
+
+		cutoffFrequency = *config.CutoffFrequency
+
+	}
+
+	ladder, err := synthesizeLadder(analoguePrototype(config), CauerI)
+
+	if (err != nil) {
+
+		return nil, err
+
+	}
+
+	warp := func(frequency float64) float64 {
+
+		return samplingFrequency*math.Tan(math.Pi*frequency / samplingFrequency) / math.Pi
+
+	}
+
+	switch config.Response {
+
+		case HPF:
+
+			return newWaveDigitalFilter(ladder.highPass().scale(warp(cutoffFrequency)), samplingFrequency), nil
+
+		case BPF, BSF, BRF:
+
+			// warp both geometric band edges, then rebuild the center and relative bandwidth from them
+			_, centerFrequency, bandwidth := analogueFrequencies(config)
+			lower := math.Sqrt(bandwidth*bandwidth / 4.0 + centerFrequency*centerFrequency) - bandwidth / 2.0
+			upper := lower + bandwidth
+
+			if (upper >= samplingFrequency / 2.0) {
+
+				return nil, fmt.Errorf("upper band edge %.6g Hz is not below the Nyquist frequency of %.6g Hz", upper, samplingFrequency / 2.0)
+
+			}
+
+			lower, upper = warp(lower), warp(upper)
+			centerFrequency = math.Sqrt(lower*upper)
+
+			if (config.Response == BPF) {
+
+				return newWaveDigitalFilter(ladder.bandPass((upper - lower) / centerFrequency).scale(centerFrequency), samplingFrequency), nil
+
+			}
+
+			return newWaveDigitalFilter(ladder.bandStop((upper - lower) / centerFrequency).scale(centerFrequency), samplingFrequency), nil
+
+		case LPF, "":
+
+			return newWaveDigitalFilter(ladder.scale(warp(cutoffFrequency)), samplingFrequency), nil
+
+	}
+
+	return nil, fmt.Errorf("wave digital filters do not support the %s response", config.Response)
+
+}
+
+func (w *WaveDigitalFilter) Reset() {
+
+	for index := range w.elements {
+
+		for leaf := range w.elements[index].leaves {
+
+			w.elements[index].leaves[leaf].state = 0.0
+
+		}
+
+	}
+
+}
+
+func (w *WaveDigitalFilter) Process(x float64) float64 {
+
+	count := len(w.elements)
+	source := w.Ladder.Source
+	load := w.Ladder.Load
+
+	if (count == 0) {
+
+		return w.normalization*x*load / (source + load)
+
+	}
+
+	w.upward[count] = 0.0
+
+	for index := count - 1; index >= 0; index-- {
+
+		element := &w.elements[index]
+		w.reflected[index] = element.wave()
+
+		if (index == 0) {
+
+			break
+
+		}
+
+		if element.shunt {
+
+			up := 1.0 / (1.0 / element.resistance + 1.0 / element.down)
+			w.upward[index] = up*(w.reflected[index] / element.resistance + w.upward[index + 1] / element.down)
+
+		} else {
+
+			w.upward[index] = -(w.reflected[index] + w.upward[index + 1])
+
+		}
+
+	}
+
+	root := &w.elements[0]
+	outgoing := scatter(root.shunt, [3]float64{ source, root.resistance, root.down }, [3]float64{ x, w.reflected[0], w.upward[1] })
+	root.incident(outgoing[1])
+	incident := outgoing[2]
+
+	for index := 1; index < count; index++ {
+
+		element := &w.elements[index]
+		e := w.reflected[index]
+		d := w.upward[index + 1]
+
+		if element.shunt {
+
+			up := 1.0 / (1.0 / element.resistance + 1.0 / element.down)
+			junction := incident + up*(e / element.resistance + d / element.down)
+			element.incident(junction - e)
+			incident = junction - d
+
+		} else {
+
+			up := element.resistance + element.down
+			sum := incident + e + d
+			element.incident(e - element.resistance / up*sum)
+			incident = d - element.down / up*sum
+
+		}
+
+	}
+
+	return w.normalization*incident / 2.0
+
+}
+
+func (w *WaveDigitalFilter) ProcessBlock(in []float64, out []float64) {
+
+	for index := 0; index < min(len(in), len(out)); index++ {
+
+		out[index] = w.Process(in[index])
+
+	}
+
+}
@@ -0,0 +1,97 @@
+package main
+
+import ( "testing" )
+
+
+func TestWaveDigitalFilterMatchesBilinear(t *testing.T) {
+
+	tests := []struct {
+
+		approximation Approximation
+		order		  uint16
+		response	  Response
+
+	}{
+
+		{ Butterworth, 4, LPF },
+		{ Butterworth, 5, HPF },
+		{ Chebyshev, 3, LPF },
+		{ Chebyshev, 5, HPF },
+		{ Butterworth, 3, BPF },
+		{ Chebyshev, 2, BSF },
+
+	}
+
+	for _, test := range tests {
+
+		// a passive ladder realizes the same bilinear transfer function as the prewarped digital design
+		config := Specs{
+
+			Domain: Digital,
+			Response: test.response,
+			Approximation: test.approximation,
+			Order: pointer(test.order),
+			PassbandAttenuation: pointer(1.0),
+			CutoffFrequency: pointer(3000.0),
+			CenterFrequency: pointer(6000.0),
+			Bandwidth: pointer(2000.0),
+			SamplingFrequency: pointer(48000.0),
+
+		}
+
+		filter, err := designWaveDigitalFilter(config)
+
+		if (err != nil) {
+
+			t.Fatalf("%s %s: %v", test.approximation, test.response, err)
+
+		}
+
+		reference, err := designFilter(config)
+
+		if (err != nil) {
+
+			t.Fatalf("%s %s: %v", test.approximation, test.response, err)
+
+		}
+
+		signal := testStimulus(500)
+		got := make([]float64, len(signal))
+		want := make([]float64, len(signal))
+		filter.ProcessBlock(signal, got)
+		newProcessor(reference).ProcessBlock(signal, want)
+		compareSignals(t, string(test.approximation) + " " + string(test.response), got, want, 1e-9)
+
+		filter.Reset()
+		filter.ProcessBlock(signal, got)
+		compareSignals(t, "after reset", got, want, 1e-9)
+
+	}
+
+}
+
+func TestWaveDigitalFilterErrors(t *testing.T) {
+
+	tests := []struct {
+
+		config  Specs
+		message string
+
+	}{
+
+		{ Specs{ Response: Notch }, "wave digital filters do not support the notch response" },
+		{ Specs{ Response: BPF, CenterFrequency: pointer(20000.0), Bandwidth: pointer(10000.0) }, "upper band edge 25615.5 Hz is not below the Nyquist frequency of 24000 Hz" },
+
+	}
+
+	for _, test := range tests {
+
+		if _, err := designWaveDigitalFilter(test.config); ((err == nil) || (err.Error() != test.message)) {
+
+			t.Errorf("got %v, want %q", err, test.message)
+
+		}
+
+	}
+
+}